@@ -2,11 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/database/mongodb"
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/router"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/dependencies"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/metrics"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -19,16 +29,108 @@ func main() {
 		return
 	}
 
-	databaseConnection, err := mongodb.NewMongoDBConnection(ctx)
+	shutdownTracing, err := tracing.Init(ctx)
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
 
-	userController, bidController, auctionController := dependencies.InitDependencies(databaseConnection)
+	prometheusMetrics := metrics.NewPrometheus()
+
+	databaseConnection, err := mongodb.NewMongoDBConnection(ctx,
+		metrics.NewCommandMonitor(prometheusMetrics), tracing.NewCommandMonitor())
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	deps, err := dependencies.InitDependencies(databaseConnection, prometheusMetrics)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
 
 	r := gin.Default()
-	router.RegisterRoutes(r, userController, bidController, auctionController)
+	if err := r.SetTrustedProxies(getTrustedProxies()); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	router.RegisterRoutes(r, deps)
+
+	server := &http.Server{Addr: ":8080", Handler: r}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-signalCtx.Done()
+
+	// Readiness fails first, and the server keeps serving for the drain delay,
+	// so the orchestrator stops routing traffic here before connections are
+	// refused.
+	logger.Info("Shutting down")
+	deps.Health.ShutDown()
+	time.Sleep(getDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second))
+
+	// Each phase gets a budget of its own, so a slow one does not leave the
+	// next without time to store the pending bids.
+	server.RegisterOnShutdown(deps.CloseStreams)
+	withTimeout(ctx, getDuration("SHUTDOWN_TIMEOUT", 15*time.Second), func(ctx context.Context) {
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("Error trying to shut down the HTTP server", err)
+		}
+	})
+	withTimeout(ctx, getDuration("SHUTDOWN_BROKER_TIMEOUT", 5*time.Second), func(ctx context.Context) {
+		if err := deps.DrainBroker(ctx); err != nil {
+			logger.Error("Error trying to drain the broker connection", err)
+		}
+	})
+	withTimeout(ctx, getDuration("SHUTDOWN_BIDS_TIMEOUT", 10*time.Second), func(ctx context.Context) {
+		if err := deps.StopBids(ctx); err != nil {
+			logger.Error("Error trying to store the pending bids", err)
+		}
+	})
+	withTimeout(ctx, getDuration("SHUTDOWN_ORDERS_TIMEOUT", 5*time.Second), func(ctx context.Context) {
+		if err := deps.StopOrders(ctx); err != nil {
+			logger.Error("Error trying to stop expiring the overdue orders", err)
+		}
+	})
+	withTimeout(ctx, getDuration("SHUTDOWN_TRACING_TIMEOUT", 5*time.Second), func(ctx context.Context) {
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Error trying to flush the traces", err)
+		}
+	})
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration, phase func(ctx context.Context)) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	phase(ctx)
+}
+
+func getDuration(name string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || duration < 0 {
+		return fallback
+	}
+
+	return duration
+}
+
+// getTrustedProxies reads TRUSTED_PROXIES, a comma separated list of the IPs
+// or CIDRs of the reverse proxies allowed to set X-Forwarded-For. Without it no
+// proxy is trusted and the client IP is the address of the connection.
+func getTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
 
-	r.Run(":8080")
+	return proxies
 }
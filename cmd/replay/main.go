@@ -0,0 +1,81 @@
+// Command replay rebuilds the leader, the current price and the end of the
+// auctions from their bid ledger. It replays every auction, or only the one
+// given by -auction.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/database/mongodb"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/auction"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/bid"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/credit"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/ledger"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/outbox"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/fx"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/ledger_usecase"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	auctionId := flag.String("auction", "", "replay only this auction")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	if err := godotenv.Load("cmd/auction/.env"); err != nil {
+		log.Fatal("Error trying to load env variables")
+		return
+	}
+
+	databaseConnection, err := mongodb.NewMongoDBConnection(ctx)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	rateProvider, err := fx.NewStaticProviderFromEnv()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	outboxRepository := outbox.NewOutboxRepository(databaseConnection)
+	auctionRepository := auction.NewAuctionRepository(databaseConnection, outboxRepository)
+	creditRepository := credit.NewCreditRepository(databaseConnection, rateProvider)
+	ledgerRepository := ledger.NewLedgerRepository(databaseConnection)
+	bidRepository := bid.NewBidRepository(
+		databaseConnection, auctionRepository, creditRepository, outboxRepository, ledgerRepository,
+		metrics_entity.Discard{})
+
+	ledgerUseCase := ledger_usecase.NewLedgerUseCase(
+		ledgerRepository, auctionRepository, bidRepository, creditRepository)
+
+	if *auctionId != "" {
+		projection, err := ledgerUseCase.ReplayAuction(ctx, *auctionId)
+		if err != nil {
+			log.Fatal(err.Error())
+			return
+		}
+
+		fmt.Printf("auction %s: leader %q, price %s %s, ends at %s, version %d\n",
+			projection.AuctionId, projection.LeadingBidId, projection.CurrentPrice, projection.Currency,
+			projection.EndsAt.Format("2006-01-02 15:04:05"), projection.Version)
+		return
+	}
+
+	summary, replayErr := ledgerUseCase.ReplayAll(ctx)
+	if replayErr != nil {
+		log.Fatal(replayErr.Error())
+		return
+	}
+
+	fmt.Printf("%d auctions replayed, %d changed, %d failed\n", summary.Auctions, summary.Changed, summary.Failed)
+	if summary.Failed > 0 {
+		log.Fatal("Some auctions could not be replayed, see the logs above")
+	}
+}
@@ -3,15 +3,19 @@ package mongodb
 import (
 	"context"
 	"log"
+	"math"
 	"os"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -20,13 +24,19 @@ const (
 	APP_MODE    = "APP_MODE"
 )
 
-func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
+// NewMongoDBConnection connects to MONGODB_URL. monitors watch the commands of
+// every repository, as the metrics and the traces do.
+func NewMongoDBConnection(ctx context.Context, monitors ...*event.CommandMonitor) (*mongo.Database, error) {
 	mongoURL := os.Getenv(MONGODB_URL)
 	mongoDatabase := os.Getenv(MONGODB_DB)
 	appMode := os.Getenv(APP_MODE)
 
-	client, err := mongo.Connect(
-		ctx, options.Client().ApplyURI(mongoURL))
+	clientOptions := options.Client().ApplyURI(mongoURL)
+	if len(monitors) > 0 {
+		clientOptions.SetMonitor(combineMonitors(monitors))
+	}
+
+	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		logger.Error("Error trying to connect to mongodb database", err)
 		return nil, err
@@ -39,12 +49,24 @@ func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 
 	db := client.Database(mongoDatabase)
 
+	if err := ensureIndexes(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if err := migrateAmounts(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if err := migrateEndTimes(ctx, db); err != nil {
+		return nil, err
+	}
+
 	if appMode == "dev" {
 		err = ensureUsersCollection(ctx, db)
 		if err != nil {
 			return nil, err
 		}
-		err = ensureAuctionsCollection(ctx, db)
+		err = ensureCategoriesCollection(ctx, db)
 		if err != nil {
 			return nil, err
 		}
@@ -53,6 +75,228 @@ func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 	return db, nil
 }
 
+// ensureIndexes creates the indexes backing the keyset pagination of the
+// listings, the auction full-text search and the API key lookup. CreateMany is
+// idempotent for existing indexes.
+func ensureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		"auctions": {
+			{Keys: bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "current_price", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "currency", Value: 1}, {Key: "current_price", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "category", Value: 1}}},
+			{
+				Keys: bson.D{
+					{Key: "product_name", Value: "text"},
+					{Key: "category", Value: "text"},
+					{Key: "description", Value: "text"},
+				},
+				Options: options.Index().
+					SetName("auctions_text").
+					SetWeights(bson.D{
+						{Key: "product_name", Value: 10},
+						{Key: "category", Value: 5},
+						{Key: "description", Value: 1},
+					}).
+					SetDefaultLanguage("portuguese"),
+			},
+		},
+		"categories": {
+			{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+		},
+		"users": {
+			{
+				Keys: bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+			},
+			{Keys: bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"bids": {
+			{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"bids_dead_letter": {
+			{Keys: bson.D{{Key: "failed_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "failed_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"audit_log": {
+			{
+				Keys:    bson.D{{Key: "sequence", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "sequence", Value: -1}}},
+			{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "sequence", Value: -1}}},
+			{Keys: bson.D{{Key: "request_id", Value: 1}}},
+		},
+		"bid_ledger": {
+			{
+				Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "version", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"credit_accounts": {
+			{Keys: bson.D{{Key: "holds.auction_id", Value: 1}}},
+		},
+		"orders": {
+			{
+				Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "second_chance", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{Keys: bson.D{{Key: "buyer_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "payment_due_at", Value: 1}}},
+		},
+		"watchlist": {
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "watched_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "auction_id", Value: 1}}},
+		},
+		"notifications": {
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"api_keys": {
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"outbox": {
+			{
+				Keys: bson.D{{Key: "sequence", Value: 1}},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"sequence": bson.M{"$gt": 0}}),
+			},
+			{Keys: bson.D{{Key: "sequence", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}},
+		},
+		"webhooks": {
+			{Keys: bson.D{{Key: "events", Value: 1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"webhook_deliveries": {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+			{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			logger.Error("Error trying to create indexes for "+collection+" collection", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// combineMonitors notifies every monitor of each command, as the client takes
+// a single monitor.
+func combineMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Started != nil {
+					monitor.Started(ctx, started)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			for _, monitor := range monitors {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, succeeded)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, failed)
+				}
+			}
+		},
+	}
+}
+
+// migrateAmounts converts the amounts stored as floating point numbers before
+// money was kept in minor units. Those amounts were always in the default
+// currency, which is also set on the documents that have no currency yet.
+// Documents already converted do not match, so it runs on every startup.
+func migrateAmounts(ctx context.Context, db *mongo.Database) error {
+	fields := map[string][]string{
+		"auctions":         {"current_price"},
+		"bids":             {"amount"},
+		"bids_dead_letter": {"amount"},
+		"orders":           {"amount"},
+		"credit_accounts":  {"limit", "held"},
+	}
+
+	for collection, names := range fields {
+		legacy := bson.A{bson.M{"currency": bson.M{"$exists": false}}}
+		set := bson.M{"currency": bson.M{"$ifNull": bson.A{"$currency", money_entity.DefaultCurrency}}}
+		for _, name := range names {
+			legacy = append(legacy, bson.M{name: bson.M{"$type": "double"}})
+			set[name] = toMinorUnits("$" + name)
+		}
+
+		if collection == "credit_accounts" {
+			legacy = append(legacy, bson.M{"holds.amount": bson.M{"$type": "double"}})
+			set["holds"] = bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$holds", bson.A{}}},
+				"in": bson.M{"$mergeObjects": bson.A{
+					"$$this", bson.M{"amount": toMinorUnits("$$this.amount")},
+				}},
+			}}
+		}
+
+		_, err := db.Collection(collection).UpdateMany(
+			ctx, bson.M{"$or": legacy}, bson.A{bson.M{"$set": set}})
+		if err != nil {
+			logger.Error("Error trying to migrate the amounts of "+collection+" collection", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateEndTimes sets the end of the auctions stored before it was kept on
+// them, AUCTION_INTERVAL after their creation as they were closed, so the
+// ending filters and the ending_soonest order can rely on end_time.
+func migrateEndTimes(ctx context.Context, db *mongo.Database) error {
+	interval := int64(getAuctioInterval().Seconds())
+	set := bson.M{"end_time": bson.M{"$add": bson.A{"$timestamp", interval}}}
+
+	_, err := db.Collection("auctions").UpdateMany(
+		ctx, bson.M{"end_time": bson.M{"$exists": false}}, bson.A{bson.M{"$set": set}})
+	if err != nil {
+		logger.Error("Error trying to migrate the end of auctions", err)
+		return err
+	}
+
+	return nil
+}
+
+// toMinorUnits is the expression converting a floating point amount in major
+// units of the default currency to integer minor units.
+func toMinorUnits(field string) bson.M {
+	scale := math.Pow10(money_entity.DefaultCurrency.Exponent())
+
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, "double"}},
+		bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, scale}}, 0}}},
+		field,
+	}}
+}
+
+// devUserId and devUserPassword identify the user seeded in dev mode, so the
+// examples in api/*.http can log in and create auctions and bids.
+const (
+	devUserId       = "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7"
+	devUserPassword = "user-test-password"
+)
+
 func ensureUsersCollection(ctx context.Context, client *mongo.Database) error {
 	collection := client.Collection("users")
 
@@ -64,12 +308,22 @@ func ensureUsersCollection(ctx context.Context, client *mongo.Database) error {
 	}
 
 	if count == 0 {
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(devUserPassword), bcrypt.DefaultCost)
+		if err != nil {
+			log.Println("Error hashing the dev user password:", err)
+			return err
+		}
+
 		user := bson.M{
-			"_id":  "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7",
-			"name": "user-test",
+			"_id":           devUserId,
+			"name":          "user-test",
+			"email":         "user-test@example.com",
+			"password_hash": string(passwordHash),
+			"roles":         []string{"admin", "seller", "bidder"},
+			"timestamp":     time.Now().Unix(),
 		}
 
-		_, err := collection.InsertOne(ctx, user)
+		_, err = collection.InsertOne(ctx, user)
 		if err != nil {
 			log.Println("Error inserting user into users collection:", err)
 			return err
@@ -80,58 +334,76 @@ func ensureUsersCollection(ctx context.Context, client *mongo.Database) error {
 	return nil
 }
 
-func ensureAuctionsCollection(ctx context.Context, client *mongo.Database) error {
-	collection := client.Collection("auctions")
+func ensureCategoriesCollection(ctx context.Context, client *mongo.Database) error {
+	collection := client.Collection("categories")
 
 	var count int64
 	count, err := collection.CountDocuments(ctx, bson.D{})
 	if err != nil {
-		log.Println("Error counting documents in auctions collection:", err)
+		log.Println("Error counting documents in categories collection:", err)
 		return err
 	}
 
 	if count == 0 {
-		id := "44c402b6-2960-4f9f-999f-5f217f40cee8"
-		user := bson.M{
-			"_id":          id,
-			"product_name": "Mandolate",
-			"category":     "Doce",
-			"description":  "A melhor sobremesa do RU",
-			"condition":    1,
-			"status":       0,
-			"timestamp":    time.Now().Unix(),
+		foodId := "0b9f4c5e-6c1e-4d4b-9a57-2f1f7c3f8a10"
+		categories := []interface{}{
+			bson.M{"_id": foodId, "name": "Alimentos", "slug": "alimentos", "timestamp": time.Now().Unix()},
+			bson.M{"_id": "5d0c8a2e-3b7f-4f3e-8d21-7a9e6b4c2d11", "name": "Doce", "slug": "doce",
+				"parent_id": foodId, "timestamp": time.Now().Unix()},
+			bson.M{"_id": "9e3a1f6b-2c4d-4e8f-a7b9-1c2d3e4f5a12", "name": "Brinquedo", "slug": "brinquedo",
+				"timestamp": time.Now().Unix()},
+			bson.M{"_id": "3f8e2d1c-7b6a-4c5d-9e8f-0a1b2c3d4e13", "name": "Imóveis", "slug": "imoveis",
+				"timestamp": time.Now().Unix()},
 		}
 
-		_, err := collection.InsertOne(ctx, user)
+		_, err := collection.InsertMany(ctx, categories)
 		if err != nil {
-			log.Println("Error inserting auction into auctions collection:", err)
+			log.Println("Error inserting categories into categories collection:", err)
 			return err
 		}
-		log.Println("Auction inserted successfully into auctions collection")
-
-		go func() {
-			select {
-			case <-time.After(getAuctioInterval()):
-				closeAuction(ctx, collection, id)
-			case <-ctx.Done():
-				logger.Error("Error to close auction, context cancelled", ctx.Err())
-				return
-			}
-		}()
+		log.Println("Categories inserted successfully into categories collection")
 	}
 
 	return nil
 }
 
-func closeAuction(ctx context.Context, collection *mongo.Collection, auctionId string) {
-	update := bson.M{"$set": bson.M{"status": auction_entity.Completed}}
-	filter := bson.M{"_id": auctionId}
+// EnsureDevAuction seeds an auction in dev mode. Unlike the other seeds it is
+// created through repository once the application is wired, so it gets its end
+// time and closes like any other auction, with its events, settlement and
+// credit release.
+func EnsureDevAuction(
+	ctx context.Context, client *mongo.Database, repository auction_entity.AuctionRepositoryInterface) error {
+	if os.Getenv(APP_MODE) != "dev" {
+		return nil
+	}
 
-	_, err := collection.UpdateOne(ctx, filter, update)
+	count, err := client.Collection("auctions").CountDocuments(ctx, bson.D{})
 	if err != nil {
-		logger.Error("Error trying to update auction status to completed", err)
-		return
+		log.Println("Error counting documents in auctions collection:", err)
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	auction := &auction_entity.Auction{
+		Id:           "44c402b6-2960-4f9f-999f-5f217f40cee8",
+		SellerId:     devUserId,
+		ProductName:  "Mandolate",
+		Category:     "doce",
+		Description:  "A melhor sobremesa do RU",
+		Condition:    auction_entity.New,
+		Status:       auction_entity.Active,
+		CurrentPrice: money_entity.New(0, money_entity.DefaultCurrency),
+		Timestamp:    time.Now(),
 	}
+	if err := repository.CreateAuction(ctx, auction); err != nil {
+		log.Println("Error inserting auction into auctions collection:", err)
+		return err
+	}
+	log.Println("Auction inserted successfully into auctions collection")
+
+	return nil
 }
 
 func getAuctioInterval() time.Duration {
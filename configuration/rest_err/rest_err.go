@@ -28,6 +28,16 @@ func ConvertError(internalError *internal_error.InternalError) *RestErr {
 		return NewBadRequestError(internalError.Error())
 	case "not_found":
 		return NewNotFoundError(internalError.Error())
+	case "conflict":
+		return NewConflictError(internalError.Error())
+	case "unprocessable_entity":
+		return NewUnprocessableEntityError(internalError.Error())
+	case "unauthorized":
+		return NewUnauthorizedError(internalError.Error())
+	case "forbidden":
+		return NewForbiddenError(internalError.Error())
+	case "service_unavailable":
+		return NewServiceUnavailableError(internalError.Error())
 	default:
 		return NewInternalServerError(internalError.Error())
 	}
@@ -59,3 +69,57 @@ func NewNotFoundError(message string) *RestErr {
 		Causes:  nil,
 	}
 }
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+		Causes:  nil,
+	}
+}
+
+func NewUnprocessableEntityError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unprocessable_entity",
+		Code:    http.StatusUnprocessableEntity,
+		Causes:  nil,
+	}
+}
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+		Causes:  nil,
+	}
+}
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "forbidden",
+		Code:    http.StatusForbidden,
+		Causes:  nil,
+	}
+}
+
+func NewTooManyRequestsError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "too_many_requests",
+		Code:    http.StatusTooManyRequests,
+		Causes:  nil,
+	}
+}
+
+func NewServiceUnavailableError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "service_unavailable",
+		Code:    http.StatusServiceUnavailable,
+		Causes:  nil,
+	}
+}
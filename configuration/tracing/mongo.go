@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewCommandMonitor traces every MongoDB command as a client span, child of
+// the span of the repository call that sent it. Only the command name and the
+// collection are recorded, never the documents.
+func NewCommandMonitor() *event.CommandMonitor {
+	// spans keeps the span of each started command until it finishes.
+	var spans sync.Map
+
+	finish := func(requestId int64) (trace.Span, bool) {
+		span, ok := spans.LoadAndDelete(requestId)
+		if !ok {
+			return nil, false
+		}
+		return span.(trace.Span), true
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			name := started.CommandName
+			attributes := []trace.SpanStartOption{
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemNameMongoDB,
+					semconv.DBNamespace(started.DatabaseName),
+					semconv.DBOperationName(started.CommandName)),
+			}
+			if collection := commandCollection(started); collection != "" {
+				name += " " + collection
+				attributes = append(attributes, trace.WithAttributes(semconv.DBCollectionName(collection)))
+			}
+
+			_, span := Start(ctx, name, attributes...)
+			spans.Store(started.RequestID, span)
+		},
+		Succeeded: func(_ context.Context, succeeded *event.CommandSucceededEvent) {
+			if span, ok := finish(succeeded.RequestID); ok {
+				span.End()
+			}
+		},
+		Failed: func(_ context.Context, failed *event.CommandFailedEvent) {
+			if span, ok := finish(failed.RequestID); ok {
+				Fail(span, errors.New(failed.Failure))
+				span.End()
+			}
+		},
+	}
+}
+
+// commandCollection reads the collection of a command, named by the command
+// itself ({"find": "bids"}) except for getMore.
+func commandCollection(started *event.CommandStartedEvent) string {
+	field := started.CommandName
+	if field == "getMore" {
+		field = "collection"
+	}
+
+	collection, _ := started.Command.Lookup(field).StringValueOK()
+	return collection
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/Berchon/fullcycle-auction_go"
+
+	// ServiceName names the application in the traces, unless OTEL_SERVICE_NAME
+	// is set.
+	ServiceName = "auction"
+)
+
+// Start starts a span named name as a child of the span in ctx. Outside of a
+// trace, as in the polling routines, it starts nothing and returns the no-op
+// span of ctx, so that only requests and the work started with StartRoot are
+// traced.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// StartRoot starts a new trace for background work worth following on its
+// own, such as a bid batch; links relate it to the traces that caused it.
+func StartRoot(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithNewRoot())
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// Fail marks span as failed with err.
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Init installs the global tracer provider and the W3C trace context
+// propagator. OTEL_TRACES_EXPORTER chooses where the spans go: "otlp", to the
+// collector at OTEL_EXPORTER_OTLP_ENDPOINT over HTTP, "stdout", or "none", the
+// default, which keeps the trace context propagating without exporting spans.
+// The returned function flushes the pending spans.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch name := os.Getenv("OTEL_TRACES_EXPORTER"); name {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		otlpExporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		exporter = otlpExporter
+	case "stdout":
+		stdoutExporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+		exporter = stdoutExporter
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q, expected otlp, stdout or none", name)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost())
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
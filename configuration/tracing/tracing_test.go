@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestStart(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, untraced := Start(context.Background(), "Repository.Poll")
+	untraced.End()
+	assert.Empty(t, recorder.Ended(), "no span is started outside of a trace")
+
+	rootCtx, root := StartRoot(context.Background(), "BidUseCase.flushBatch")
+	_, child := Start(rootCtx, "BidRepository.CreateBid")
+	child.End()
+	root.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "BidRepository.CreateBid", spans[0].Name())
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.False(t, spans[1].Parent().IsValid())
+}
+
+func TestStartRoot(t *testing.T) {
+	recorder := recordSpans(t)
+
+	requestCtx, request := StartRoot(context.Background(), "POST /bid")
+	request.End()
+
+	_, batch := StartRoot(requestCtx, "BidUseCase.flushBatch", trace.WithLinks(trace.LinkFromContext(requestCtx)))
+	batch.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.NotEqual(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	require.Len(t, spans[1].Links(), 1)
+	assert.Equal(t, request.SpanContext().SpanID(), spans[1].Links()[0].SpanContext.SpanID())
+}
+
+func TestCommandMonitor(t *testing.T) {
+	recorder := recordSpans(t)
+	monitor := NewCommandMonitor()
+
+	ctx, parent := StartRoot(context.Background(), "BidRepository.FindBidById")
+	start := func(ctx context.Context, id int64, name string, command bson.D) {
+		raw, err := bson.Marshal(command)
+		require.NoError(t, err)
+		monitor.Started(ctx, &event.CommandStartedEvent{
+			Command: raw, CommandName: name, DatabaseName: "auctions", RequestID: id})
+	}
+
+	start(ctx, 1, "find", bson.D{{Key: "find", Value: "bids"}})
+	start(ctx, 2, "insert", bson.D{{Key: "insert", Value: "bids"}})
+	start(context.Background(), 3, "ping", bson.D{{Key: "ping", Value: 1}})
+
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", RequestID: 2},
+		Failure:              "duplicate key"})
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "ping", RequestID: 3}})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "find bids", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "insert bids", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "duplicate key", spans[1].Status().Description)
+}
@@ -0,0 +1,173 @@
+package api_key_entity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+// ApiKey is a long-lived credential for machine clients. Only the hash of the
+// secret is stored; the secret itself is shown once, when the key is issued.
+type ApiKey struct {
+	Id         string
+	Name       string
+	OwnerId    string
+	Prefix     string
+	Hash       string
+	Scopes     []Scope
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type Scope string
+
+const (
+	ScopeRead          Scope = "read"
+	ScopeBid           Scope = "bid"
+	ScopeCreateAuction Scope = "create-auction"
+)
+
+const keyPrefix = "ak_"
+
+// Role returns the user role a scope acts with. A key can only hold scopes
+// whose role its owner has, so it never grants more than the owner could do.
+func (s Scope) Role() (user_entity.Role, bool) {
+	switch s {
+	case ScopeBid:
+		return user_entity.RoleBidder, true
+	case ScopeCreateAuction:
+		return user_entity.RoleSeller, true
+	default:
+		return "", false
+	}
+}
+
+// IssueApiKey creates a key acting on behalf of ownerId and returns it along
+// with the plain secret to hand over to the client.
+func IssueApiKey(
+	name, ownerId string,
+	scopes []Scope,
+	expiresAt *time.Time) (*ApiKey, string, *internal_error.InternalError) {
+	apiKey := &ApiKey{
+		Id:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		OwnerId:   ownerId,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := apiKey.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", internal_error.NewInternalServerError("Error trying to generate API key")
+	}
+
+	apiKey.Prefix = keyPrefix + apiKey.Id[:8]
+	secret := apiKey.Prefix + "_" + base64.RawURLEncoding.EncodeToString(random)
+	apiKey.Hash = HashSecret(secret)
+
+	return apiKey, secret, nil
+}
+
+func (k *ApiKey) Validate() *internal_error.InternalError {
+	if len(k.Name) <= 1 {
+		return internal_error.NewBadRequestError("Name is not a valid value")
+	}
+
+	if err := uuid.Validate(k.OwnerId); err != nil {
+		return internal_error.NewBadRequestError("OwnerId is not a valid id")
+	}
+
+	if len(k.Scopes) == 0 {
+		return internal_error.NewBadRequestError("API key must have at least one scope")
+	}
+
+	for _, scope := range k.Scopes {
+		if scope != ScopeRead && scope != ScopeBid && scope != ScopeCreateAuction {
+			return internal_error.NewBadRequestError(fmt.Sprintf("Scope %s is not a valid value", scope))
+		}
+	}
+
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now()) {
+		return internal_error.NewBadRequestError("ExpiresAt must be in the future")
+	}
+
+	return nil
+}
+
+// HashSecret returns the value stored and looked up for a key secret. Keys are
+// random, so a plain SHA-256 is enough (no salt or slow hash needed).
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive reports whether the key can still authenticate requests at now.
+func (k *ApiKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+func (k *ApiKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// RoleNames returns the roles granted by the key scopes, in the format carried
+// by the request claims.
+func (k *ApiKey) RoleNames() []string {
+	names := []string{}
+	for _, scope := range k.Scopes {
+		if role, ok := scope.Role(); ok && !slices.Contains(names, string(role)) {
+			names = append(names, string(role))
+		}
+	}
+
+	return names
+}
+
+func (k *ApiKey) ScopeNames() []string {
+	names := make([]string, 0, len(k.Scopes))
+	for _, scope := range k.Scopes {
+		names = append(names, string(scope))
+	}
+
+	return names
+}
+
+type ApiKeyRepositoryInterface interface {
+	CreateApiKey(
+		ctx context.Context, apiKey *ApiKey) *internal_error.InternalError
+
+	RevokeApiKey(
+		ctx context.Context, id string, revokedAt time.Time) *internal_error.InternalError
+
+	TouchApiKey(
+		ctx context.Context, id string, usedAt time.Time) *internal_error.InternalError
+
+	FindApiKeyByHash(
+		ctx context.Context, hash string) (*ApiKey, *internal_error.InternalError)
+
+	FindApiKeys(
+		ctx context.Context,
+		page pagination_entity.PageRequest) ([]ApiKey, *pagination_entity.PageInfo, *internal_error.InternalError)
+}
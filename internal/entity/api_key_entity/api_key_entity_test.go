@@ -0,0 +1,58 @@
+package api_key_entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ownerId = "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7"
+
+func TestIssueApiKey(t *testing.T) {
+	t.Run("should only store the hash of the secret", func(t *testing.T) {
+		apiKey, secret, err := IssueApiKey("Bidding bot", ownerId, []Scope{ScopeRead, ScopeBid}, nil)
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(secret, apiKey.Prefix+"_"))
+		assert.NotContains(t, apiKey.Hash, secret)
+		assert.Equal(t, HashSecret(secret), apiKey.Hash)
+		assert.Equal(t, []string{"bidder"}, apiKey.RoleNames())
+	})
+
+	t.Run("should issue a different secret each time", func(t *testing.T) {
+		_, first, _ := IssueApiKey("Bot", ownerId, []Scope{ScopeRead}, nil)
+		_, second, _ := IssueApiKey("Bot", ownerId, []Scope{ScopeRead}, nil)
+
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		_, _, err := IssueApiKey("Bot", ownerId, nil, nil)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, _, err = IssueApiKey("Bot", ownerId, []Scope{"delete"}, nil)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, _, err = IssueApiKey("Bot", "owner", []Scope{ScopeRead}, nil)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, _, err = IssueApiKey("Bot", ownerId, []Scope{ScopeRead}, &past)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestApiKeyIsActive(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	apiKey := &ApiKey{ExpiresAt: &expiresAt}
+
+	assert.True(t, apiKey.IsActive(now))
+	assert.False(t, apiKey.IsActive(expiresAt))
+
+	apiKey.RevokedAt = &now
+	assert.False(t, apiKey.IsActive(now))
+}
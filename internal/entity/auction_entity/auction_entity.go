@@ -2,23 +2,29 @@ package auction_entity
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 	"github.com/google/uuid"
 )
 
 func CreateAuction(
-	productName, category, description string,
-	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+	sellerId, productName, category, description string,
+	condition ProductCondition,
+	currency money_entity.Currency) (*Auction, *internal_error.InternalError) {
 	auction := &Auction{
-		Id:          uuid.New().String(),
-		ProductName: productName,
-		Category:    category,
-		Description: description,
-		Condition:   condition,
-		Status:      Active,
-		Timestamp:   time.Now(),
+		Id:           uuid.New().String(),
+		SellerId:     sellerId,
+		ProductName:  productName,
+		Category:     category,
+		Description:  description,
+		Condition:    condition,
+		Status:       Active,
+		CurrentPrice: money_entity.New(0, currency),
+		Timestamp:    time.Now(),
 	}
 
 	if err := auction.Validate(); err != nil {
@@ -29,6 +35,15 @@ func CreateAuction(
 }
 
 func (au *Auction) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(au.SellerId); err != nil {
+		return internal_error.NewBadRequestError("SellerId is not a valid id")
+	}
+
+	if !au.CurrentPrice.Currency.IsValid() {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Currency %s is not supported", au.CurrentPrice.Currency))
+	}
+
 	if len(au.ProductName) <= 1 ||
 		len(au.Category) <= 2 ||
 		len(au.Description) <= 10 && (au.Condition != New &&
@@ -41,23 +56,156 @@ func (au *Auction) Validate() *internal_error.InternalError {
 }
 
 type Auction struct {
-	Id          string
-	ProductName string
-	Category    string
-	Description string
-	Condition   ProductCondition
-	Status      AuctionStatus
-	Timestamp   time.Time
+	Id           string
+	SellerId     string
+	ProductName  string
+	Category     string
+	Description  string
+	Condition    ProductCondition
+	Status       AuctionStatus
+	CurrentPrice money_entity.Money
+	LeadingBidId string
+	LeaderId     string
+	Timestamp    time.Time
+
+	// EndTime is when the auction ends as projected from its bid ledger, later
+	// than interval after its creation once extended. It is zero for auctions
+	// stored before the end was projected.
+	EndTime time.Time
+}
+
+// Currency is the currency every bid of the auction is placed in.
+func (au *Auction) Currency() money_entity.Currency {
+	return au.CurrentPrice.Currency
+}
+
+// EndsAt is when the automatic closure completes the auction: its EndTime, or
+// interval after its creation when it has none.
+func (au *Auction) EndsAt(interval time.Duration) time.Time {
+	if !au.EndTime.IsZero() {
+		return au.EndTime
+	}
+
+	return au.Timestamp.Add(interval)
+}
+
+// Remaining is the time left until EndsAt, zero once the auction is no longer
+// open.
+func (au *Auction) Remaining(interval time.Duration, now time.Time) time.Duration {
+	if au.Status != Active && au.Status != Paused {
+		return 0
+	}
+
+	if remaining := au.EndsAt(interval).Sub(now); remaining > 0 {
+		return remaining
+	}
+
+	return 0
+}
+
+// LeadingBid is the highest bid accepted so far by an auction.
+type LeadingBid struct {
+	BidId  string
+	UserId string
+	Amount money_entity.Money
 }
 
 type ProductCondition int
 type AuctionStatus int
+type AuctionSort string
+
+const (
+	SortNewest        AuctionSort = "newest"
+	SortEndingSoonest AuctionSort = "ending_soonest"
+	SortHighestBid    AuctionSort = "highest_bid"
+)
+
+// AuctionFilter holds the filters shared by the auction listing and search.
+// Empty slices and nil bounds mean the filter is not applied; values inside
+// the same slice are alternatives (OR) and different filters are combined (AND).
+// A Currency restricts the auctions to it, and price bounds only match auctions
+// in the currency of the bound.
+type AuctionFilter struct {
+	Statuses     []AuctionStatus
+	Categories   []string
+	Conditions   []ProductCondition
+	ProductName  string
+	Currency     money_entity.Currency
+	MinPrice     *money_entity.Money
+	MaxPrice     *money_entity.Money
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	EndingAfter  *time.Time
+	EndingBefore *time.Time
+}
+
+type AuctionQuery struct {
+	AuctionFilter
+	Sort AuctionSort
+}
+
+// AuctionSearch is a full-text search over product name, category and
+// description. Phrase searches match Text literally instead of word by word.
+type AuctionSearch struct {
+	AuctionFilter
+	Text   string
+	Phrase bool
+}
 
 const (
 	Active AuctionStatus = iota
 	Completed
+	Paused
+	Cancelled
 )
 
+// AuctionAction is an administrative status change.
+type AuctionAction string
+
+const (
+	ActionPause  AuctionAction = "pause"
+	ActionResume AuctionAction = "resume"
+	ActionCancel AuctionAction = "cancel"
+	ActionClose  AuctionAction = "close"
+)
+
+type statusTransition struct {
+	from []AuctionStatus
+	to   AuctionStatus
+}
+
+var transitions = map[AuctionAction]statusTransition{
+	ActionPause:  {from: []AuctionStatus{Active}, to: Paused},
+	ActionResume: {from: []AuctionStatus{Paused}, to: Active},
+	ActionCancel: {from: []AuctionStatus{Active, Paused}, to: Cancelled},
+	ActionClose:  {from: []AuctionStatus{Active, Paused}, to: Completed},
+}
+
+// Transition returns the statuses action can be applied to and the resulting status.
+func (a AuctionAction) Transition() ([]AuctionStatus, AuctionStatus, bool) {
+	transition, ok := transitions[a]
+	return transition.from, transition.to, ok
+}
+
+// OpenStatuses are the statuses of auctions that were not finished yet; the
+// automatic closure only completes auctions in one of them.
+var OpenStatuses = []AuctionStatus{Active, Paused}
+
+func (s AuctionStatus) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Completed:
+		return "completed"
+	case Paused:
+		return "paused"
+	case Cancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	New ProductCondition = iota + 1
 	Used
@@ -71,9 +219,40 @@ type AuctionRepositoryInterface interface {
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		query AuctionQuery,
+		page pagination_entity.PageRequest) ([]Auction, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	SearchAuctions(
+		ctx context.Context,
+		search AuctionSearch,
+		page pagination_entity.PageRequest) ([]Auction, *pagination_entity.PageInfo, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	// FindAuctionsByIds skips the ids without an auction.
+	FindAuctionsByIds(
+		ctx context.Context, ids []string) ([]Auction, *internal_error.InternalError)
+
+	UpdateAuctionStatus(
+		ctx context.Context,
+		id string,
+		from []AuctionStatus,
+		to AuctionStatus) (*Auction, *internal_error.InternalError)
+
+	// UpdateProjection replaces the leader, the current price and the end of the
+	// auction with the ones projected from its ledger, as long as the leader is
+	// still expectedLeaderId. It returns a conflict when another bid took the
+	// lead in the meantime, so the projection can be built again.
+	UpdateProjection(
+		ctx context.Context,
+		id string,
+		expectedLeaderId string,
+		leader *LeadingBid,
+		endTime time.Time) (bool, *internal_error.InternalError)
+
+	// FindAuctionsAfter returns up to limit auctions with an id after afterId,
+	// in id order, to go through every auction.
+	FindAuctionsAfter(
+		ctx context.Context, afterId string, limit int64) ([]Auction, *internal_error.InternalError)
 }
@@ -0,0 +1,82 @@
+package auction_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionActionTransition(t *testing.T) {
+	cases := map[AuctionAction]struct {
+		from []AuctionStatus
+		to   AuctionStatus
+	}{
+		ActionPause:  {from: []AuctionStatus{Active}, to: Paused},
+		ActionResume: {from: []AuctionStatus{Paused}, to: Active},
+		ActionCancel: {from: []AuctionStatus{Active, Paused}, to: Cancelled},
+		ActionClose:  {from: []AuctionStatus{Active, Paused}, to: Completed},
+	}
+
+	for action, expected := range cases {
+		from, to, ok := action.Transition()
+
+		assert.True(t, ok, "action %s", action)
+		assert.Equal(t, expected.from, from, "from of %s", action)
+		assert.Equal(t, expected.to, to, "to of %s", action)
+	}
+
+	_, _, ok := AuctionAction("delete").Transition()
+	assert.False(t, ok)
+}
+
+func TestCreateAuction(t *testing.T) {
+	t.Run("should require a seller", func(t *testing.T) {
+		_, err := CreateAuction("", "Mola maluca", "brinquedo", "Você vai adorar", New, money_entity.DefaultCurrency)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+
+	t.Run("should start active", func(t *testing.T) {
+		auction, err := CreateAuction(
+			"e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", "Mola maluca", "brinquedo", "Você vai adorar", New, "USD")
+
+		assert.Nil(t, err)
+		assert.Equal(t, Active, auction.Status)
+		assert.Equal(t, money_entity.New(0, "USD"), auction.CurrentPrice)
+		assert.Equal(t, money_entity.Currency("USD"), auction.Currency())
+	})
+
+	t.Run("should reject unsupported currencies", func(t *testing.T) {
+		_, err := CreateAuction(
+			"e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", "Mola maluca", "brinquedo", "Você vai adorar", New, "XYZ")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestAuctionRemaining(t *testing.T) {
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	auction := &Auction{Status: Active, Timestamp: created}
+
+	assert.Equal(t, created.Add(time.Hour), auction.EndsAt(time.Hour))
+	assert.Equal(t, 45*time.Minute, auction.Remaining(time.Hour, created.Add(15*time.Minute)))
+	assert.Equal(t, time.Duration(0), auction.Remaining(time.Hour, created.Add(2*time.Hour)))
+
+	auction.Status = Paused
+	assert.Equal(t, 45*time.Minute, auction.Remaining(time.Hour, created.Add(15*time.Minute)))
+
+	auction.Status = Completed
+	assert.Equal(t, time.Duration(0), auction.Remaining(time.Hour, created.Add(15*time.Minute)))
+}
+
+func TestAuctionEndsAtExtended(t *testing.T) {
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	auction := &Auction{Status: Active, Timestamp: created, EndTime: created.Add(90 * time.Minute)}
+
+	assert.Equal(t, created.Add(90*time.Minute), auction.EndsAt(time.Hour))
+	assert.Equal(t, 30*time.Minute, auction.Remaining(time.Hour, created.Add(time.Hour)))
+}
@@ -0,0 +1,228 @@
+package audit_entity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+type ActorType string
+
+const (
+	ActorUser   ActorType = "user"
+	ActorApiKey ActorType = "api_key"
+	// ActorAnonymous makes the changes of unauthenticated requests, such as
+	// signing up.
+	ActorAnonymous ActorType = "anonymous"
+	// ActorSystem is the application itself, for the changes made by its
+	// schedulers.
+	ActorSystem ActorType = "system"
+)
+
+// Actor is who made a change. UserId is set for users and for API keys, which
+// act in the name of their owner.
+type Actor struct {
+	Type     ActorType
+	UserId   string
+	ApiKeyId string
+}
+
+// SystemActor is the actor of the changes made outside of a request.
+var SystemActor = Actor{Type: ActorSystem}
+
+// Entry records one change. Entries form a single chain: each one carries the
+// hash of the entry before it and its own hash over both, so changing or
+// removing an entry breaks every hash after it.
+type Entry struct {
+	Id         string
+	Sequence   int64
+	Actor      Actor
+	Action     string
+	TargetType string
+	TargetId   string
+	// Before and After are JSON snapshots of the target, empty when there is
+	// nothing to show (before a creation, after a removal).
+	Before    string
+	After     string
+	RequestId string
+	// ClientIP is the client as told by the trusted proxies, RemoteIP the
+	// address the request came from.
+	ClientIP   string
+	RemoteIP   string
+	Status     int
+	OccurredAt time.Time
+
+	PreviousHash string
+	Hash         string
+}
+
+// NewEntry takes the actor, the request id and the client and remote IPs from
+// the request context; outside of a request the change is made by the system.
+func NewEntry(ctx context.Context, action, targetType, targetId string, before, after any) Entry {
+	entry := Entry{
+		Id:         uuid.New().String(),
+		Actor:      SystemActor,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		OccurredAt: time.Now(),
+	}
+
+	if request, ok := RequestFrom(ctx); ok {
+		entry.RequestId = request.RequestId
+		entry.ClientIP = request.ClientIP
+		entry.RemoteIP = request.RemoteIP
+		if request.Actor.Type != "" {
+			entry.Actor = request.Actor
+		}
+	}
+
+	return entry
+}
+
+func snapshot(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.RawMessage:
+		return string(value)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(raw)
+}
+
+// Seal places the entry after the one with previousHash, empty for the first
+// entry, at sequence.
+func (e *Entry) Seal(sequence int64, previousHash string) {
+	e.Sequence = sequence
+	e.PreviousHash = previousHash
+	e.Hash = e.computeHash()
+}
+
+// computeHash covers every field but the hash itself. Times are taken in unix
+// seconds, the precision they are stored with. RemoteIP is left out when empty,
+// so entries recorded before it existed keep their hash.
+func (e *Entry) computeHash() string {
+	content, _ := json.Marshal(struct {
+		Id           string    `json:"id"`
+		Sequence     int64     `json:"sequence"`
+		ActorType    ActorType `json:"actor_type"`
+		UserId       string    `json:"user_id"`
+		ApiKeyId     string    `json:"api_key_id"`
+		Action       string    `json:"action"`
+		TargetType   string    `json:"target_type"`
+		TargetId     string    `json:"target_id"`
+		Before       string    `json:"before"`
+		After        string    `json:"after"`
+		RequestId    string    `json:"request_id"`
+		ClientIP     string    `json:"client_ip"`
+		RemoteIP     string    `json:"remote_ip,omitempty"`
+		Status       int       `json:"status"`
+		OccurredAt   int64     `json:"occurred_at"`
+		PreviousHash string    `json:"previous_hash"`
+	}{
+		e.Id, e.Sequence, e.Actor.Type, e.Actor.UserId, e.Actor.ApiKeyId, e.Action, e.TargetType, e.TargetId,
+		e.Before, e.After, e.RequestId, e.ClientIP, e.RemoteIP, e.Status, e.OccurredAt.Unix(), e.PreviousHash,
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainBreak tells where a chain stops being trustworthy.
+type ChainBreak struct {
+	Sequence int64
+	Reason   string
+}
+
+// VerifyChain checks entries that follow the entry with previousHash at
+// sequence previousSequence, returning the first break found or nil.
+func VerifyChain(previousSequence int64, previousHash string, entries []Entry) *ChainBreak {
+	for _, entry := range entries {
+		switch {
+		case entry.Sequence != previousSequence+1:
+			return &ChainBreak{Sequence: previousSequence + 1, Reason: "entry is missing"}
+		case entry.PreviousHash != previousHash:
+			return &ChainBreak{Sequence: entry.Sequence, Reason: "entry does not follow the previous one"}
+		case entry.Hash != entry.computeHash():
+			return &ChainBreak{Sequence: entry.Sequence, Reason: "entry was changed"}
+		}
+
+		previousSequence, previousHash = entry.Sequence, entry.Hash
+	}
+
+	return nil
+}
+
+// RequestInfo is what a request tells about the changes it makes.
+type RequestInfo struct {
+	RequestId string
+	ClientIP  string
+	RemoteIP  string
+	Actor     Actor
+}
+
+type requestKey struct{}
+
+func WithRequest(ctx context.Context, request RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+func RequestFrom(ctx context.Context) (RequestInfo, bool) {
+	if ctx == nil {
+		return RequestInfo{}, false
+	}
+
+	request, ok := ctx.Value(requestKey{}).(RequestInfo)
+	return request, ok
+}
+
+// EntryFilter narrows the audit log; empty fields and nil bounds are not applied.
+type EntryFilter struct {
+	UserId     string
+	Action     string
+	TargetType string
+	TargetId   string
+	RequestId  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// RecorderInterface records changes that already happened, so recording never
+// fails the change: errors are only logged.
+type RecorderInterface interface {
+	Record(ctx context.Context, entry Entry)
+}
+
+type AuditRepositoryInterface interface {
+	// AppendEntry seals the entry after the last one of the chain and stores
+	// it. Entries are never updated nor removed.
+	AppendEntry(
+		ctx context.Context, entry Entry) (*Entry, *internal_error.InternalError)
+
+	// FindEntries returns the newest entries first.
+	FindEntries(
+		ctx context.Context,
+		filter EntryFilter,
+		page pagination_entity.PageRequest) ([]Entry, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	// FindChain returns up to limit entries after afterSequence, in sequence
+	// order.
+	FindChain(
+		ctx context.Context, afterSequence int64, limit int64) ([]Entry, *internal_error.InternalError)
+}
@@ -0,0 +1,112 @@
+package audit_entity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chain seals entries one after the other, as the repository does.
+func chain(entries ...Entry) []Entry {
+	previousHash := ""
+	for i := range entries {
+		entries[i].Seal(int64(i+1), previousHash)
+		previousHash = entries[i].Hash
+	}
+	return entries
+}
+
+func TestNewEntry(t *testing.T) {
+	t.Run("should take the actor and the request from the context", func(t *testing.T) {
+		ctx := WithRequest(context.Background(), RequestInfo{
+			RequestId: "request-1",
+			ClientIP:  "203.0.113.7",
+			RemoteIP:  "10.0.0.2",
+			Actor:     Actor{Type: ActorUser, UserId: "user-1"},
+		})
+
+		entry := NewEntry(ctx, "auction.pause", "auction", "auction-1",
+			map[string]string{"status": "active"}, map[string]string{"status": "paused"})
+
+		assert.Equal(t, Actor{Type: ActorUser, UserId: "user-1"}, entry.Actor)
+		assert.Equal(t, "request-1", entry.RequestId)
+		assert.Equal(t, "203.0.113.7", entry.ClientIP)
+		assert.Equal(t, "10.0.0.2", entry.RemoteIP)
+		assert.JSONEq(t, `{"status":"active"}`, entry.Before)
+		assert.JSONEq(t, `{"status":"paused"}`, entry.After)
+	})
+
+	t.Run("should be made by the system outside of a request", func(t *testing.T) {
+		entry := NewEntry(context.Background(), "auction.close", "auction", "auction-1", nil, nil)
+
+		assert.Equal(t, SystemActor, entry.Actor)
+		assert.Empty(t, entry.RequestId)
+		assert.Empty(t, entry.Before)
+	})
+}
+
+func TestVerifyChain(t *testing.T) {
+	ctx := context.Background()
+	newEntries := func() []Entry {
+		return chain(
+			NewEntry(ctx, "auction.create", "auction", "auction-1", nil, `{"status":"active"}`),
+			NewEntry(ctx, "auction.pause", "auction", "auction-1", `{"status":"active"}`, `{"status":"paused"}`),
+			NewEntry(ctx, "auction.resume", "auction", "auction-1", `{"status":"paused"}`, `{"status":"active"}`),
+		)
+	}
+
+	t.Run("should accept an untouched chain", func(t *testing.T) {
+		assert.Nil(t, VerifyChain(0, "", newEntries()))
+	})
+
+	t.Run("should continue a chain verified in batches", func(t *testing.T) {
+		entries := newEntries()
+
+		assert.Nil(t, VerifyChain(1, entries[0].Hash, entries[1:]))
+	})
+
+	t.Run("should find a changed entry", func(t *testing.T) {
+		entries := newEntries()
+		entries[1].After = `{"status":"cancelled"}`
+
+		chainBreak := VerifyChain(0, "", entries)
+
+		require.NotNil(t, chainBreak)
+		assert.Equal(t, int64(2), chainBreak.Sequence)
+		assert.Equal(t, "entry was changed", chainBreak.Reason)
+	})
+
+	t.Run("should find a changed remote IP", func(t *testing.T) {
+		entries := newEntries()
+		entries[2].RemoteIP = "10.0.0.2"
+
+		chainBreak := VerifyChain(0, "", entries)
+
+		require.NotNil(t, chainBreak)
+		assert.Equal(t, int64(3), chainBreak.Sequence)
+	})
+
+	t.Run("should find an entry sealed again after a change", func(t *testing.T) {
+		entries := newEntries()
+		entries[1].Actor = Actor{Type: ActorUser, UserId: "user-2"}
+		entries[1].Seal(2, entries[0].Hash)
+
+		chainBreak := VerifyChain(0, "", entries)
+
+		require.NotNil(t, chainBreak)
+		assert.Equal(t, int64(3), chainBreak.Sequence)
+		assert.Equal(t, "entry does not follow the previous one", chainBreak.Reason)
+	})
+
+	t.Run("should find a removed entry", func(t *testing.T) {
+		entries := newEntries()
+
+		chainBreak := VerifyChain(0, "", append(entries[:1], entries[2:]...))
+
+		require.NotNil(t, chainBreak)
+		assert.Equal(t, int64(2), chainBreak.Sequence)
+		assert.Equal(t, "entry is missing", chainBreak.Reason)
+	})
+}
@@ -0,0 +1,43 @@
+package auth_entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// Claims is the identity of a request, taken from an access token or an API key.
+// Requests made with an API key also carry the key id and its scopes.
+type Claims struct {
+	UserId    string
+	Roles     []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	ApiKeyId string
+	Scopes   []string
+}
+
+// HasScope reports whether the request may use scope. Scopes only restrict API
+// keys; user tokens are limited by their roles alone.
+func (c *Claims) HasScope(scope string) bool {
+	return c.ApiKeyId == "" || slices.Contains(c.Scopes, scope)
+}
+
+// HasAnyRole reports whether the claims grant at least one of roles.
+func (c *Claims) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(c.Roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type TokenServiceInterface interface {
+	IssueToken(userId string, roles []string) (string, *Claims, *internal_error.InternalError)
+
+	ParseToken(token string) (*Claims, *internal_error.InternalError)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 	"github.com/google/uuid"
 )
@@ -12,11 +14,39 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    money_entity.Money
 	Timestamp time.Time
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
+type BidSort string
+
+// DeadLetterBid is a bid accepted by the API but rejected when its batch was
+// processed, kept so admins can see why it never showed up.
+type DeadLetterBid struct {
+	Bid
+	Reason   DeadLetterReason
+	Detail   string
+	FailedAt time.Time
+}
+
+type DeadLetterReason string
+
+const (
+	ReasonAuctionLookupFailed DeadLetterReason = "auction_lookup_failed"
+	ReasonAuctionNotActive    DeadLetterReason = "auction_not_active"
+	ReasonAuctionEnded        DeadLetterReason = "auction_ended"
+	ReasonInsertFailed        DeadLetterReason = "insert_failed"
+	ReasonCreditLimitExceeded DeadLetterReason = "credit_limit_exceeded"
+	ReasonCreditHoldFailed    DeadLetterReason = "credit_hold_failed"
+	ReasonExchangeRateMissing DeadLetterReason = "exchange_rate_missing"
+)
+
+const (
+	SortNewest        BidSort = "newest"
+	SortHighestAmount BidSort = "highest_amount"
+)
+
+func CreateBid(userId, auctionId string, amount money_entity.Money) (*Bid, *internal_error.InternalError) {
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
@@ -37,7 +67,7 @@ func (b *Bid) Validate() *internal_error.InternalError {
 		return internal_error.NewBadRequestError("UserId is not a valid id")
 	} else if err := uuid.Validate(b.AuctionId); err != nil {
 		return internal_error.NewBadRequestError("AuctionId is not a valid id")
-	} else if b.Amount <= 0 {
+	} else if !b.Amount.Currency.IsValid() || !b.Amount.IsPositive() {
 		return internal_error.NewBadRequestError("Amount is not a valid value")
 	}
 
@@ -50,8 +80,28 @@ type BidEntityRepository interface {
 		bidEntities []Bid) *internal_error.InternalError
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
+		ctx context.Context,
+		auctionId string,
+		sort BidSort,
+		page pagination_entity.PageRequest) ([]Bid, *pagination_entity.PageInfo, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	FindRunnerUpBid(
+		ctx context.Context, auctionId, winnerId string) (*Bid, *internal_error.InternalError)
+
+	FindDeadLetterBids(
+		ctx context.Context,
+		auctionId string,
+		page pagination_entity.PageRequest) ([]DeadLetterBid, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	FindBidById(
+		ctx context.Context, bidId string) (*Bid, *internal_error.InternalError)
+
+	FindAllBidsByAuctionId(
+		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
+
+	MarkBidRetracted(
+		ctx context.Context, bidId string) *internal_error.InternalError
 }
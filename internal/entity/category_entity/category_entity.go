@@ -0,0 +1,105 @@
+package category_entity
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Category struct {
+	Id        string
+	Name      string
+	Slug      string
+	ParentId  string
+	Timestamp time.Time
+}
+
+func CreateCategory(name, parentId string) (*Category, *internal_error.InternalError) {
+	category := &Category{
+		Id:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Slug:      NormalizeSlug(name),
+		ParentId:  parentId,
+		Timestamp: time.Now(),
+	}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (c *Category) Validate() *internal_error.InternalError {
+	if len(c.Name) <= 1 || len(c.Slug) <= 1 {
+		return internal_error.NewBadRequestError("invalid category object")
+	}
+
+	if c.ParentId != "" {
+		if err := uuid.Validate(c.ParentId); err != nil {
+			return internal_error.NewBadRequestError("ParentId is not a valid id")
+		}
+		if c.ParentId == c.Id {
+			return internal_error.NewBadRequestError("A category cannot be its own parent")
+		}
+	}
+
+	return nil
+}
+
+// NormalizeSlug builds the canonical key of a category name: accents are
+// removed, letters are lowercased and any run of other characters becomes a
+// single hyphen, so "Doce", "doce" and " DOCE " share the slug "doce".
+func NormalizeSlug(value string) string {
+	withoutAccents, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		withoutAccents = value
+	}
+
+	var builder strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(withoutAccents) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && builder.Len() > 0 {
+				builder.WriteRune('-')
+			}
+			builder.WriteRune(r)
+			pendingHyphen = false
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	return builder.String()
+}
+
+type CategoryRepositoryInterface interface {
+	CreateCategory(
+		ctx context.Context, category *Category) *internal_error.InternalError
+
+	UpdateCategory(
+		ctx context.Context, category *Category) *internal_error.InternalError
+
+	DeleteCategory(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindCategoryById(
+		ctx context.Context, id string) (*Category, *internal_error.InternalError)
+
+	FindCategoryBySlug(
+		ctx context.Context, slug string) (*Category, *internal_error.InternalError)
+
+	FindCategories(
+		ctx context.Context, parentId *string) ([]Category, *internal_error.InternalError)
+
+	FindDescendants(
+		ctx context.Context, id string) ([]Category, *internal_error.InternalError)
+}
@@ -0,0 +1,39 @@
+package category_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	cases := map[string]string{
+		"Doce":                  "doce",
+		" DOCE ":                "doce",
+		"Imóveis":               "imoveis",
+		"Instrumentos Musicais": "instrumentos-musicais",
+		"Casa & Jardim!":        "casa-jardim",
+		"--":                    "",
+	}
+
+	for input, expected := range cases {
+		assert.Equal(t, expected, NormalizeSlug(input), "slug of %q", input)
+	}
+}
+
+func TestCreateCategory(t *testing.T) {
+	t.Run("should derive the slug from the name", func(t *testing.T) {
+		category, err := CreateCategory("  Doce  ", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Doce", category.Name)
+		assert.Equal(t, "doce", category.Slug)
+	})
+
+	t.Run("should reject an invalid parent id", func(t *testing.T) {
+		_, err := CreateCategory("Doce", "invalid")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
@@ -0,0 +1,148 @@
+package credit_entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// CreditAccount caps how much a bidder can commit at once. Each leading bid
+// holds its amount until the bidder is outbid or the auction ends without them
+// winning, and the sum of the holds can never exceed Limit. Limit, Held and
+// every hold share the currency of the account; bids in auctions of another
+// currency hold their amount converted with NewHold.
+type CreditAccount struct {
+	UserId    string
+	Limit     money_entity.Money
+	Held      money_entity.Money
+	Holds     []Hold
+	UpdatedAt time.Time
+}
+
+// Hold is the amount reserved by the leading bid of a user in one auction. A
+// user has at most one hold per auction. Amount is in the account currency and
+// Bid in the auction currency; Rate is the snapshot that converted Bid into
+// Amount, nil when both are in the same currency.
+type Hold struct {
+	AuctionId string
+	BidId     string
+	Amount    money_entity.Money
+	Bid       money_entity.Money
+	Rate      *money_entity.Rate
+	HeldAt    time.Time
+}
+
+// NewCreditAccount returns the account of a user that never had one.
+func NewCreditAccount(userId string, limit money_entity.Money) *CreditAccount {
+	return &CreditAccount{
+		UserId: userId,
+		Limit:  limit,
+		Held:   money_entity.New(0, limit.Currency),
+		Holds:  []Hold{},
+	}
+}
+
+func ValidateLimit(limit money_entity.Money) *internal_error.InternalError {
+	if !limit.Currency.IsValid() || limit.Amount < 0 {
+		return internal_error.NewBadRequestError("Limit is not a valid value")
+	}
+
+	return nil
+}
+
+func (a *CreditAccount) Currency() money_entity.Currency {
+	return a.Limit.Currency
+}
+
+func (a *CreditAccount) Available() money_entity.Money {
+	available := a.Limit.Sub(a.Held)
+	if available.Amount < 0 {
+		return money_entity.New(0, a.Currency())
+	}
+
+	return available
+}
+
+// NewHold returns the hold of bidId, a bid of amount in auctionId, converted
+// into the account currency. The rate is kept on the hold: a bid in an auction
+// the account already holds reuses the rate of that hold, so the bids of an
+// auction are always compared at the same rate, and only a new hold takes the
+// current rate of rates. A missing rate is an unprocessable entity error.
+func (a *CreditAccount) NewHold(
+	ctx context.Context,
+	rates money_entity.RateProviderInterface,
+	auctionId, bidId string,
+	amount money_entity.Money) (Hold, *internal_error.InternalError) {
+	hold := Hold{AuctionId: auctionId, BidId: bidId, Amount: amount, Bid: amount, HeldAt: time.Now()}
+	if amount.Currency == a.Currency() {
+		return hold, nil
+	}
+
+	current, ok := a.HoldFor(auctionId)
+	if ok && current.Rate != nil && current.Rate.From == amount.Currency && current.Rate.To == a.Currency() {
+		hold.Rate = current.Rate
+	} else {
+		rate, err := rates.Rate(ctx, amount.Currency, a.Currency())
+		if err != nil {
+			return Hold{}, internal_error.NewUnprocessableEntityError(fmt.Sprintf(
+				"Your credit is in %s and there is no exchange rate from %s to hold it", a.Currency(), amount.Currency))
+		}
+		hold.Rate = rate
+	}
+
+	hold.Amount = amount.Convert(*hold.Rate)
+	return hold, nil
+}
+
+func (a *CreditAccount) HoldFor(auctionId string) (Hold, bool) {
+	for _, hold := range a.Holds {
+		if hold.AuctionId == auctionId {
+			return hold, true
+		}
+	}
+
+	return Hold{}, false
+}
+
+// CanHold reports whether a bid of amount fits the limit. A higher bid in an
+// auction replaces the hold of the previous one, so only the difference counts,
+// and a bid that does not raise the hold always fits. Amounts in another
+// currency never fit.
+func (a *CreditAccount) CanHold(auctionId string, amount money_entity.Money) bool {
+	if amount.Currency != a.Currency() {
+		return false
+	}
+
+	current, ok := a.HoldFor(auctionId)
+	if ok && amount.Amount <= current.Amount.Amount {
+		return true
+	}
+
+	return a.Held.Amount-current.Amount.Amount+amount.Amount <= a.Limit.Amount
+}
+
+type CreditRepositoryInterface interface {
+	FindCreditAccount(
+		ctx context.Context, userId string) (*CreditAccount, *internal_error.InternalError)
+
+	SetCreditLimit(
+		ctx context.Context, userId string, limit money_entity.Money) (*CreditAccount, *internal_error.InternalError)
+
+	// HoldCredit reserves hold.Bid for the user in hold.AuctionId, converted
+	// into the account currency with NewHold, returning a conflict error when
+	// it would exceed the limit.
+	HoldCredit(
+		ctx context.Context, userId string, hold Hold) *internal_error.InternalError
+
+	// ReleaseHold frees the hold of the user in auctionId, unless it already
+	// belongs to a bid other than bidId.
+	ReleaseHold(
+		ctx context.Context, userId, auctionId, bidId string) *internal_error.InternalError
+
+	// ReleaseAuctionHolds frees every hold in auctionId except the one of keepBidId.
+	ReleaseAuctionHolds(
+		ctx context.Context, auctionId, keepBidId string) *internal_error.InternalError
+}
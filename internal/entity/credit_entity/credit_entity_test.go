@@ -0,0 +1,110 @@
+package credit_entity
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func brl(amount int64) money_entity.Money {
+	return money_entity.New(amount, "BRL")
+}
+
+func TestCanHold(t *testing.T) {
+	account := NewCreditAccount("user-1", brl(100000))
+	account.Held = brl(70000)
+	account.Holds = []Hold{
+		{AuctionId: "auction-1", BidId: "bid-1", Amount: brl(50000)},
+		{AuctionId: "auction-2", BidId: "bid-2", Amount: brl(20000)},
+	}
+
+	t.Run("should accept a bid within the available credit", func(t *testing.T) {
+		assert.True(t, account.CanHold("auction-3", brl(30000)))
+		assert.False(t, account.CanHold("auction-3", brl(30001)))
+	})
+
+	t.Run("should only count the raise over the current hold", func(t *testing.T) {
+		assert.True(t, account.CanHold("auction-1", brl(80000)))
+		assert.False(t, account.CanHold("auction-1", brl(80100)))
+	})
+
+	t.Run("should reject amounts in another currency", func(t *testing.T) {
+		assert.False(t, account.CanHold("auction-3", money_entity.New(1, "USD")))
+	})
+
+	t.Run("should accept a lower bid in an auction already held", func(t *testing.T) {
+		account.Limit = brl(60000)
+
+		assert.True(t, account.CanHold("auction-1", brl(10000)))
+		assert.False(t, account.CanHold("auction-3", brl(1)))
+		assert.Equal(t, brl(0), account.Available())
+	})
+}
+
+func TestValidateLimit(t *testing.T) {
+	assert.Nil(t, ValidateLimit(brl(0)))
+	assert.Nil(t, ValidateLimit(brl(250050)))
+	assert.NotNil(t, ValidateLimit(brl(-1)))
+	assert.NotNil(t, ValidateLimit(money_entity.New(100, "XYZ")))
+}
+
+type usdRates struct{}
+
+func (usdRates) Rate(
+	_ context.Context, from, to money_entity.Currency) (*money_entity.Rate, *internal_error.InternalError) {
+	if from != "USD" || to != "BRL" {
+		return nil, internal_error.NewBadRequestError("no rate")
+	}
+
+	return &money_entity.Rate{From: from, To: to, Value: big.NewRat(5, 1)}, nil
+}
+
+func TestNewHold(t *testing.T) {
+	ctx := context.Background()
+	account := NewCreditAccount("user-1", brl(100000))
+
+	t.Run("should keep amounts in the account currency", func(t *testing.T) {
+		hold, err := account.NewHold(ctx, usdRates{}, "auction-1", "bid-1", brl(1500))
+		assert.Nil(t, err)
+		assert.Equal(t, brl(1500), hold.Amount)
+		assert.Nil(t, hold.Rate)
+	})
+
+	t.Run("should convert amounts of auctions in another currency", func(t *testing.T) {
+		hold, err := account.NewHold(ctx, usdRates{}, "auction-1", "bid-1", money_entity.New(1500, "USD"))
+		assert.Nil(t, err)
+		assert.Equal(t, brl(7500), hold.Amount)
+		assert.Equal(t, money_entity.New(1500, "USD"), hold.Bid)
+		assert.Equal(t, "5", hold.Rate.String())
+	})
+
+	t.Run("should reuse the rate of the hold of the auction", func(t *testing.T) {
+		held := NewCreditAccount("user-1", brl(100000))
+		held.Holds = []Hold{{
+			AuctionId: "auction-1",
+			BidId:     "bid-1",
+			Amount:    brl(6000),
+			Bid:       money_entity.New(1500, "USD"),
+			Rate:      &money_entity.Rate{From: "USD", To: "BRL", Value: big.NewRat(4, 1)},
+		}}
+
+		hold, err := held.NewHold(ctx, usdRates{}, "auction-1", "bid-2", money_entity.New(2000, "USD"))
+		assert.Nil(t, err)
+		assert.Equal(t, brl(8000), hold.Amount)
+
+		hold, err = held.NewHold(ctx, usdRates{}, "auction-2", "bid-3", money_entity.New(2000, "USD"))
+		assert.Nil(t, err)
+		assert.Equal(t, brl(10000), hold.Amount)
+	})
+
+	t.Run("should reject currencies without a rate", func(t *testing.T) {
+		_, err := account.NewHold(ctx, usdRates{}, "auction-1", "bid-1", money_entity.New(1500, "EUR"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "unprocessable_entity", err.Err)
+		}
+	})
+}
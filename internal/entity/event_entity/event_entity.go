@@ -0,0 +1,126 @@
+package event_entity
+
+import (
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+)
+
+type EventType string
+
+const (
+	BidAccepted      EventType = "bid.accepted"
+	LeaderChanged    EventType = "leader.changed"
+	AuctionPaused    EventType = "auction.paused"
+	AuctionResumed   EventType = "auction.resumed"
+	AuctionClosed    EventType = "auction.closed"
+	AuctionCancelled EventType = "auction.cancelled"
+)
+
+// Event is something that happened to an auction. Id is assigned when the event
+// is published and increases with every event, so subscribers can resume after
+// the last one they saw.
+type Event struct {
+	Id         string
+	Type       EventType
+	AuctionId  string
+	Data       interface{}
+	OccurredAt time.Time
+}
+
+// Final reports whether the auction is over, so no other event follows.
+func (e Event) Final() bool {
+	return e.Type == AuctionClosed || e.Type == AuctionCancelled
+}
+
+// BidData is the payload of BidAccepted and LeaderChanged; the previous leader
+// is only set on LeaderChanged.
+type BidData struct {
+	BidId            string               `json:"bid_id"`
+	UserId           string               `json:"user_id"`
+	Amount           money_entity.Decimal `json:"amount"`
+	Currency         string               `json:"currency"`
+	PreviousBidId    string               `json:"previous_bid_id,omitempty"`
+	PreviousLeaderId string               `json:"previous_leader_id,omitempty"`
+}
+
+type StatusData struct {
+	Status string `json:"status"`
+}
+
+func NewBidAccepted(bid bid_entity.Bid) Event {
+	return Event{
+		Type:       BidAccepted,
+		AuctionId:  bid.AuctionId,
+		Data:       newBidData(bid),
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewLeaderChanged is published when bid takes the lead from previous, which is
+// nil for the first bid of the auction.
+func NewLeaderChanged(bid bid_entity.Bid, previous *auction_entity.LeadingBid) Event {
+	data := newBidData(bid)
+	if previous != nil {
+		data.PreviousBidId = previous.BidId
+		data.PreviousLeaderId = previous.UserId
+	}
+
+	return Event{
+		Type:       LeaderChanged,
+		AuctionId:  bid.AuctionId,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewStatusChanged returns the event of an auction moved to status. Auctions
+// only become active again when resumed.
+func NewStatusChanged(auctionId string, status auction_entity.AuctionStatus) (Event, bool) {
+	types := map[auction_entity.AuctionStatus]EventType{
+		auction_entity.Active:    AuctionResumed,
+		auction_entity.Paused:    AuctionPaused,
+		auction_entity.Completed: AuctionClosed,
+		auction_entity.Cancelled: AuctionCancelled,
+	}
+
+	eventType, ok := types[status]
+	if !ok {
+		return Event{}, false
+	}
+
+	return Event{
+		Type:       eventType,
+		AuctionId:  auctionId,
+		Data:       StatusData{Status: status.String()},
+		OccurredAt: time.Now(),
+	}, true
+}
+
+func newBidData(bid bid_entity.Bid) BidData {
+	return BidData{
+		BidId:    bid.Id,
+		UserId:   bid.UserId,
+		Amount:   bid.Amount.Decimal(),
+		Currency: string(bid.Amount.Currency),
+	}
+}
+
+// Subscription receives the events of one auction. Replay holds the events
+// published after the requested one that are still kept by the hub. Events is
+// closed by Close or when the subscriber falls behind, in which case it should
+// subscribe again from the last event it received.
+type Subscription struct {
+	Replay []Event
+	Events <-chan Event
+	Close  func()
+}
+
+// HubInterface fans the auction events out to the subscribers of each auction.
+type HubInterface interface {
+	Publish(event Event)
+
+	Subscribe(auctionId, lastEventId string) *Subscription
+}
@@ -0,0 +1,47 @@
+package event_entity
+
+import (
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLeaderChanged(t *testing.T) {
+	bid := bid_entity.Bid{Id: "bid-2", UserId: "user-2", AuctionId: "auction-1", Amount: money_entity.New(1550, "BRL")}
+
+	event := NewLeaderChanged(bid, &auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1"})
+
+	assert.Equal(t, LeaderChanged, event.Type)
+	assert.Equal(t, "auction-1", event.AuctionId)
+	assert.Equal(t, BidData{
+		BidId:            "bid-2",
+		UserId:           "user-2",
+		Amount:           "15.50",
+		Currency:         "BRL",
+		PreviousBidId:    "bid-1",
+		PreviousLeaderId: "user-1",
+	}, event.Data)
+}
+
+func TestNewStatusChanged(t *testing.T) {
+	cases := map[auction_entity.AuctionStatus]EventType{
+		auction_entity.Active:    AuctionResumed,
+		auction_entity.Paused:    AuctionPaused,
+		auction_entity.Completed: AuctionClosed,
+		auction_entity.Cancelled: AuctionCancelled,
+	}
+
+	for status, expected := range cases {
+		event, ok := NewStatusChanged("auction-1", status)
+		assert.True(t, ok)
+		assert.Equal(t, expected, event.Type)
+		assert.Equal(t, StatusData{Status: status.String()}, event.Data)
+		assert.Equal(t, status == auction_entity.Completed || status == auction_entity.Cancelled, event.Final())
+	}
+
+	_, ok := NewStatusChanged("auction-1", auction_entity.AuctionStatus(9))
+	assert.False(t, ok)
+}
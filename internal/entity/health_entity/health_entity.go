@@ -0,0 +1,74 @@
+package health_entity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// CheckerInterface is implemented by the components the readiness probe
+// checks. A nil error means the component is up.
+type CheckerInterface interface {
+	CheckHealth(ctx context.Context) *internal_error.InternalError
+}
+
+// CheckFunc adapts a function to CheckerInterface.
+type CheckFunc func(ctx context.Context) *internal_error.InternalError
+
+func (f CheckFunc) CheckHealth(ctx context.Context) *internal_error.InternalError {
+	return f(ctx)
+}
+
+// Heartbeat tracks a background worker: whether it runs and when it last made
+// progress.
+type Heartbeat struct {
+	mutex   sync.Mutex
+	running bool
+	last    time.Time
+}
+
+// Start marks the worker as running, as of now.
+func (h *Heartbeat) Start(now time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.running = true
+	h.last = now
+}
+
+// Beat records that the worker made progress at now.
+func (h *Heartbeat) Beat(now time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.last = now
+}
+
+// Stop marks the worker as no longer running.
+func (h *Heartbeat) Stop() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.running = false
+}
+
+// Check fails when the worker does not run, or made no progress for longer
+// than timeout.
+func (h *Heartbeat) Check(now time.Time, timeout time.Duration) *internal_error.InternalError {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if !h.running {
+		return internal_error.NewInternalServerError("worker is not running")
+	}
+
+	if idle := now.Sub(h.last); idle > timeout {
+		return internal_error.NewInternalServerError(fmt.Sprintf(
+			"worker stalled: no progress since %s", h.last.Format(time.RFC3339)))
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package health_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeat(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should fail before the worker starts", func(t *testing.T) {
+		var heartbeat Heartbeat
+
+		err := heartbeat.Check(start, time.Minute)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "worker is not running", err.Message)
+		}
+	})
+
+	t.Run("should pass while the worker beats within the timeout", func(t *testing.T) {
+		var heartbeat Heartbeat
+		heartbeat.Start(start)
+		heartbeat.Beat(start.Add(50 * time.Second))
+
+		assert.Nil(t, heartbeat.Check(start.Add(100*time.Second), time.Minute))
+	})
+
+	t.Run("should fail once the worker stalls", func(t *testing.T) {
+		var heartbeat Heartbeat
+		heartbeat.Start(start)
+
+		err := heartbeat.Check(start.Add(61*time.Second), time.Minute)
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Message, "worker stalled")
+		}
+	})
+
+	t.Run("should fail after the worker stops", func(t *testing.T) {
+		var heartbeat Heartbeat
+		heartbeat.Start(start)
+		heartbeat.Stop()
+
+		assert.NotNil(t, heartbeat.Check(start, time.Minute))
+	})
+}
@@ -0,0 +1,229 @@
+package ledger_entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	BidPlaced       EventType = "bid.placed"
+	BidRetracted    EventType = "bid.retracted"
+	BidRejected     EventType = "bid.rejected"
+	AuctionExtended EventType = "auction.extended"
+)
+
+// Event is an entry of the bid ledger of an auction. Entries are never changed
+// or removed; Version numbers the entries of each auction from 1, in the order
+// they were appended, which is the order the projection applies them in.
+type Event struct {
+	Id         string
+	AuctionId  string
+	Version    int64
+	Type       EventType
+	BidId      string
+	UserId     string
+	Amount     money_entity.Money
+	Reason     string
+	Detail     string
+	EndsAt     time.Time
+	OccurredAt time.Time
+}
+
+// NewBidPlaced is appended once the bid is stored, whether or not it took the
+// lead.
+func NewBidPlaced(bid bid_entity.Bid) Event {
+	event := newEvent(BidPlaced, bid.AuctionId)
+	event.BidId = bid.Id
+	event.UserId = bid.UserId
+	event.Amount = bid.Amount
+	event.OccurredAt = bid.Timestamp
+
+	return event
+}
+
+// NewBidRetracted withdraws a placed bid; when it led the auction, the highest
+// bid left takes the lead.
+func NewBidRetracted(bid bid_entity.Bid, reason string) Event {
+	event := newEvent(BidRetracted, bid.AuctionId)
+	event.BidId = bid.Id
+	event.UserId = bid.UserId
+	event.Amount = bid.Amount
+	event.Reason = reason
+
+	return event
+}
+
+// NewBidRejected keeps a bid that never became part of the auction, for the
+// record only.
+func NewBidRejected(bid bid_entity.Bid, reason bid_entity.DeadLetterReason, detail string) Event {
+	event := newEvent(BidRejected, bid.AuctionId)
+	event.BidId = bid.Id
+	event.UserId = bid.UserId
+	event.Amount = bid.Amount
+	event.Reason = string(reason)
+	event.Detail = detail
+
+	return event
+}
+
+// NewAuctionExtended moves the end of the auction to endsAt.
+func NewAuctionExtended(auctionId string, endsAt time.Time, reason string) Event {
+	event := newEvent(AuctionExtended, auctionId)
+	event.EndsAt = endsAt
+	event.Reason = reason
+
+	return event
+}
+
+func newEvent(eventType EventType, auctionId string) Event {
+	return Event{
+		Id:         uuid.New().String(),
+		AuctionId:  auctionId,
+		Type:       eventType,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Projection is the state of an auction derived from its ledger: the leading
+// bid, whose amount is the current price, and the end of the auction.
+type Projection struct {
+	AuctionId string
+	Leader    *auction_entity.LeadingBid
+	EndsAt    time.Time
+	Version   int64
+
+	// live are the placed bids not retracted, in the order they were placed.
+	live []auction_entity.LeadingBid
+	// placed are the ids of every bid placed, retracted ones included.
+	placed map[string]struct{}
+}
+
+// NewProjection starts the projection of an auction without events, which ends
+// at endsAt unless extended.
+func NewProjection(auctionId string, endsAt time.Time) *Projection {
+	return &Projection{
+		AuctionId: auctionId,
+		EndsAt:    endsAt,
+		placed:    make(map[string]struct{}),
+	}
+}
+
+// Project builds the projection of an auction from all of its events.
+func Project(auctionId string, endsAt time.Time, events []Event) (*Projection, *internal_error.InternalError) {
+	projection := NewProjection(auctionId, endsAt)
+	for _, event := range events {
+		if err := projection.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+
+	return projection, nil
+}
+
+// Apply folds the next event of the auction into the projection. Events must
+// come in version order without gaps, so a missing entry is never mistaken for
+// a bid that was not placed.
+func (p *Projection) Apply(event Event) *internal_error.InternalError {
+	if event.AuctionId != p.AuctionId {
+		return internal_error.NewInternalServerError(fmt.Sprintf(
+			"Ledger event %s belongs to auction %s, not %s", event.Id, event.AuctionId, p.AuctionId))
+	}
+	if event.Version != p.Version+1 {
+		return internal_error.NewInternalServerError(fmt.Sprintf(
+			"Ledger of auction %s jumps from version %d to %d", p.AuctionId, p.Version, event.Version))
+	}
+
+	switch event.Type {
+	case BidPlaced:
+		// A replay importing the stored bids may race a live bid and record
+		// it twice; only its first placement counts.
+		if _, ok := p.placed[event.BidId]; ok {
+			break
+		}
+		p.placed[event.BidId] = struct{}{}
+
+		bid := auction_entity.LeadingBid{BidId: event.BidId, UserId: event.UserId, Amount: event.Amount}
+		p.live = append(p.live, bid)
+
+		// As when bids are placed, a bid only takes the lead by beating the
+		// leader; ties keep the earlier bid.
+		if p.Leader == nil || beats(bid, *p.Leader) {
+			p.Leader = &bid
+		}
+	case BidRetracted:
+		for i, bid := range p.live {
+			if bid.BidId == event.BidId {
+				p.live = append(p.live[:i:i], p.live[i+1:]...)
+				break
+			}
+		}
+
+		if p.Leader != nil && p.Leader.BidId == event.BidId {
+			p.Leader = p.highest()
+		}
+	case AuctionExtended:
+		if event.EndsAt.After(p.EndsAt) {
+			p.EndsAt = event.EndsAt
+		}
+	case BidRejected:
+	default:
+		return internal_error.NewInternalServerError(fmt.Sprintf(
+			"Ledger event %s has unknown type %s", event.Id, event.Type))
+	}
+
+	p.Version = event.Version
+	return nil
+}
+
+// IsLive tells whether the bid was placed and not retracted.
+func (p *Projection) IsLive(bidId string) bool {
+	for _, bid := range p.live {
+		if bid.BidId == bidId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// highest returns the earliest of the highest live bids, or nil without bids.
+func (p *Projection) highest() *auction_entity.LeadingBid {
+	var leader *auction_entity.LeadingBid
+	for i := range p.live {
+		if leader == nil || beats(p.live[i], *leader) {
+			bid := p.live[i]
+			leader = &bid
+		}
+	}
+
+	return leader
+}
+
+func beats(bid, leader auction_entity.LeadingBid) bool {
+	return bid.Amount.SameCurrency(leader.Amount) && bid.Amount.Amount > leader.Amount.Amount
+}
+
+type LedgerRepositoryInterface interface {
+	// AppendEvent gives the event the next version of its auction and stores it.
+	AppendEvent(
+		ctx context.Context, event Event) (*Event, *internal_error.InternalError)
+
+	// FindAuctionEvents returns every event of the auction in version order.
+	FindAuctionEvents(
+		ctx context.Context, auctionId string) ([]Event, *internal_error.InternalError)
+
+	FindEvents(
+		ctx context.Context,
+		auctionId string,
+		page pagination_entity.PageRequest) ([]Event, *pagination_entity.PageInfo, *internal_error.InternalError)
+}
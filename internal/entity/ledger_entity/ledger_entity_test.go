@@ -0,0 +1,146 @@
+package ledger_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const auctionId = "auction-1"
+
+func placed(bidId, userId string, amount int64) Event {
+	return NewBidPlaced(bid_entity.Bid{
+		Id:        bidId,
+		UserId:    userId,
+		AuctionId: auctionId,
+		Amount:    money_entity.New(amount, "BRL"),
+		Timestamp: time.Now(),
+	})
+}
+
+func retracted(bidId string) Event {
+	return NewBidRetracted(bid_entity.Bid{Id: bidId, AuctionId: auctionId}, "fraud")
+}
+
+// numbered versions the events as the repository does when appending them.
+func numbered(events ...Event) []Event {
+	for i := range events {
+		events[i].Version = int64(i + 1)
+	}
+	return events
+}
+
+func TestProject(t *testing.T) {
+	endsAt := time.Unix(1700000000, 0)
+
+	t.Run("should lead with the highest bid and keep the earlier one on ties", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			placed("bid-1", "user-1", 1000),
+			placed("bid-2", "user-2", 1500),
+			placed("bid-3", "user-3", 1500),
+			placed("bid-4", "user-1", 1200),
+		))
+
+		require.Nil(t, err)
+		assert.Equal(t, &auction_entity.LeadingBid{
+			BidId: "bid-2", UserId: "user-2", Amount: money_entity.New(1500, "BRL"),
+		}, projection.Leader)
+		assert.Equal(t, int64(4), projection.Version)
+		assert.Equal(t, endsAt, projection.EndsAt)
+	})
+
+	t.Run("should give the lead to the highest bid left when the leader is retracted", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			placed("bid-1", "user-1", 1000),
+			placed("bid-2", "user-2", 1200),
+			placed("bid-3", "user-3", 1200),
+			placed("bid-4", "user-4", 1500),
+			retracted("bid-4"),
+		))
+
+		require.Nil(t, err)
+		assert.Equal(t, "bid-2", projection.Leader.BidId)
+		assert.False(t, projection.IsLive("bid-4"))
+		assert.True(t, projection.IsLive("bid-3"))
+	})
+
+	t.Run("should keep the leader when another bid is retracted", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			placed("bid-1", "user-1", 1000),
+			placed("bid-2", "user-2", 1500),
+			retracted("bid-1"),
+		))
+
+		require.Nil(t, err)
+		assert.Equal(t, "bid-2", projection.Leader.BidId)
+	})
+
+	t.Run("should have no leader once every bid is retracted", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			placed("bid-1", "user-1", 1000),
+			retracted("bid-1"),
+		))
+
+		require.Nil(t, err)
+		assert.Nil(t, projection.Leader)
+	})
+
+	t.Run("should only count the first placement of a bid", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			placed("bid-1", "user-1", 1000),
+			placed("bid-2", "user-2", 1500),
+			placed("bid-1", "user-1", 1000),
+			retracted("bid-1"),
+		))
+
+		require.Nil(t, err)
+		assert.Equal(t, "bid-2", projection.Leader.BidId)
+		assert.False(t, projection.IsLive("bid-1"))
+	})
+
+	t.Run("should ignore rejected bids", func(t *testing.T) {
+		rejected := NewBidRejected(bid_entity.Bid{
+			Id: "bid-2", AuctionId: auctionId, Amount: money_entity.New(9000, "BRL"),
+		}, bid_entity.ReasonAuctionEnded, "auction ended")
+
+		projection, err := Project(auctionId, endsAt, numbered(placed("bid-1", "user-1", 1000), rejected))
+
+		require.Nil(t, err)
+		assert.Equal(t, "bid-1", projection.Leader.BidId)
+	})
+
+	t.Run("should only move the end forward", func(t *testing.T) {
+		projection, err := Project(auctionId, endsAt, numbered(
+			NewAuctionExtended(auctionId, endsAt.Add(10*time.Minute), ""),
+			NewAuctionExtended(auctionId, endsAt.Add(5*time.Minute), ""),
+		))
+
+		require.Nil(t, err)
+		assert.Equal(t, endsAt.Add(10*time.Minute), projection.EndsAt)
+	})
+
+	t.Run("should return internal error when a version is missing", func(t *testing.T) {
+		events := numbered(placed("bid-1", "user-1", 1000), placed("bid-2", "user-2", 1500))
+		events[1].Version = 3
+
+		projection, err := Project(auctionId, endsAt, events)
+
+		assert.Nil(t, projection)
+		require.NotNil(t, err)
+		assert.Equal(t, "internal_server_error", err.Err)
+	})
+
+	t.Run("should return internal error for events of another auction", func(t *testing.T) {
+		event := placed("bid-1", "user-1", 1000)
+		event.AuctionId = "auction-2"
+
+		_, err := Project(auctionId, endsAt, numbered(event))
+
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,44 @@
+package metrics_entity
+
+import "time"
+
+// MetricsInterface records what the application does, so the code measuring
+// it does not depend on a metrics backend.
+type MetricsInterface interface {
+	// ObserveRequest records an HTTP request; route is the route pattern,
+	// not the requested path.
+	ObserveRequest(method, route string, status int, duration time.Duration)
+
+	// SetBidQueueDepth records how many bids wait for the next batch.
+	SetBidQueueDepth(depth int)
+
+	// ObserveBidBatch records the size of a flushed bid batch and how long
+	// storing it took.
+	ObserveBidBatch(size int, duration time.Duration)
+
+	BidAccepted()
+
+	// BidRejected records a bid rejected by its batch, by dead letter reason.
+	BidRejected(reason string)
+
+	AuctionOpened()
+
+	// AuctionClosed records an auction that ended with status, completed or
+	// cancelled.
+	AuctionClosed(status string)
+
+	// ObserveDatabaseCommand records a database command on collection.
+	ObserveDatabaseCommand(collection, command string, duration time.Duration, failed bool)
+}
+
+// Discard records nothing, for the tools that run without a metrics endpoint.
+type Discard struct{}
+
+func (Discard) ObserveRequest(string, string, int, time.Duration)          {}
+func (Discard) SetBidQueueDepth(int)                                       {}
+func (Discard) ObserveBidBatch(int, time.Duration)                         {}
+func (Discard) BidAccepted()                                               {}
+func (Discard) BidRejected(string)                                         {}
+func (Discard) AuctionOpened()                                             {}
+func (Discard) AuctionClosed(string)                                       {}
+func (Discard) ObserveDatabaseCommand(string, string, time.Duration, bool) {}
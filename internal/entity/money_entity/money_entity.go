@@ -0,0 +1,217 @@
+package money_entity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// DefaultCurrency is used by auctions created without a currency and by credit
+// accounts.
+const DefaultCurrency Currency = "BRL"
+
+// exponents is the number of minor unit digits of each supported currency.
+var exponents = map[Currency]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"ARS": 2,
+	"MXN": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"JPY": 0,
+}
+
+// ParseCurrency accepts a supported currency code in any case.
+func ParseCurrency(code string) (Currency, *internal_error.InternalError) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := exponents[currency]; !ok {
+		return "", internal_error.NewBadRequestError(fmt.Sprintf("Currency %s is not supported", code))
+	}
+
+	return currency, nil
+}
+
+func (c Currency) IsValid() bool {
+	_, ok := exponents[c]
+	return ok
+}
+
+// Exponent is the number of decimal places of the currency minor unit.
+func (c Currency) Exponent() int {
+	return exponents[c]
+}
+
+// Money is an amount in integer minor units (cents for BRL) of a currency, so
+// amounts are compared and added without floating point rounding.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a non-negative decimal in major units ("12.34") into the minor
+// units of currency. More decimal places than the currency has are rejected
+// instead of rounded.
+func Parse(value string, currency Currency) (Money, *internal_error.InternalError) {
+	if !currency.IsValid() {
+		return Money{}, internal_error.NewBadRequestError(fmt.Sprintf("Currency %s is not supported", currency))
+	}
+
+	invalid := internal_error.NewBadRequestError(
+		fmt.Sprintf("Amount %q is not a valid %s value", value, currency))
+
+	whole, fraction, hasPoint := strings.Cut(value, ".")
+	exponent := currency.Exponent()
+	if whole == "" || (hasPoint && fraction == "") || len(fraction) > exponent {
+		return Money{}, invalid
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+	var amount int64
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return Money{}, invalid
+		}
+		if amount > (math.MaxInt64-int64(digit-'0'))/10 {
+			return Money{}, invalid
+		}
+		amount = amount*10 + int64(digit-'0')
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+// Add and Sub only make sense for amounts of the same currency; callers check
+// SameCurrency first.
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}
+}
+
+// Decimal formats the amount in major units with all the currency decimal places.
+func (m Money) Decimal() Decimal {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	exponent := m.Currency.Exponent()
+	if exponent == 0 {
+		return Decimal(fmt.Sprintf("%s%d", sign, amount))
+	}
+
+	digits := fmt.Sprintf("%0*d", exponent+1, amount)
+	split := len(digits) - exponent
+	return Decimal(sign + digits[:split] + "." + digits[split:])
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.Currency)
+}
+
+// Decimal is an amount in major units as written by clients ("12.34"). It is
+// read from JSON strings or numbers keeping the digits as sent, so no float
+// rounding happens before Parse, and it is always written as a string.
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*d = Decimal(strings.TrimSpace(text))
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return &json.UnmarshalTypeError{Value: string(data), Type: reflect.TypeOf(*d)}
+	}
+
+	*d = Decimal(number.String())
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(d))
+}
+
+// Rate is the price of one unit of From in units of To, as published by a
+// rate provider at AsOf.
+type Rate struct {
+	From  Currency
+	To    Currency
+	Value *big.Rat
+	AsOf  time.Time
+}
+
+// String formats the rate with up to 8 decimal places, without trailing zeros.
+func (r Rate) String() string {
+	text := r.Value.FloatString(8)
+	if strings.Contains(text, ".") {
+		text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
+
+// Convert returns m in the rate target currency, rounding half away from zero
+// to its minor unit. m must be in the rate source currency.
+func (m Money) Convert(rate Rate) Money {
+	units := new(big.Rat).SetFrac(pow10(rate.To.Exponent()), pow10(rate.From.Exponent()))
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate.Value)
+	value.Mul(value, units)
+
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return Money{Amount: quotient.Int64(), Currency: rate.To}
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// RateProviderInterface is a source of exchange rates, used to show amounts in
+// the currency of the user. The original amounts remain authoritative.
+type RateProviderInterface interface {
+	// Rate returns a bad request error when there is no rate between the currencies.
+	Rate(ctx context.Context, from, to Currency) (*Rate, *internal_error.InternalError)
+}
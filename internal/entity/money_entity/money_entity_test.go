@@ -0,0 +1,107 @@
+package money_entity
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("should read major units into minor units", func(t *testing.T) {
+		cases := map[string]int64{"12.34": 1234, "12.3": 1230, "12": 1200, "0.01": 1, "0": 0}
+		for value, expected := range cases {
+			money, err := Parse(value, "BRL")
+			assert.Nil(t, err, value)
+			assert.Equal(t, New(expected, "BRL"), money, value)
+		}
+	})
+
+	t.Run("should follow the exponent of the currency", func(t *testing.T) {
+		money, err := Parse("1500", "JPY")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1500), money.Amount)
+
+		_, err = Parse("1500.5", "JPY")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should reject values that are not plain non-negative decimals", func(t *testing.T) {
+		for _, value := range []string{"", ".5", "5.", "12.345", "-1", "1e3", "1,50", "abc", "99999999999999999999"} {
+			_, err := Parse(value, "BRL")
+			assert.NotNil(t, err, value)
+		}
+	})
+
+	t.Run("should reject unsupported currencies", func(t *testing.T) {
+		_, err := Parse("10", "XYZ")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := ParseCurrency("usd")
+	assert.Nil(t, err)
+	assert.Equal(t, Currency("USD"), currency)
+
+	_, err = ParseCurrency("XYZ")
+	assert.NotNil(t, err)
+}
+
+func TestDecimal(t *testing.T) {
+	assert.Equal(t, Decimal("12.34"), New(1234, "BRL").Decimal())
+	assert.Equal(t, Decimal("0.05"), New(5, "USD").Decimal())
+	assert.Equal(t, Decimal("-1.50"), New(-150, "EUR").Decimal())
+	assert.Equal(t, Decimal("1500"), New(1500, "JPY").Decimal())
+	assert.Equal(t, "12.34 BRL", New(1234, "BRL").String())
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var input struct {
+		Amount Decimal `json:"amount"`
+	}
+
+	t.Run("should accept strings and numbers keeping the digits", func(t *testing.T) {
+		assert.NoError(t, json.Unmarshal([]byte(`{"amount": "10.10"}`), &input))
+		assert.Equal(t, Decimal("10.10"), input.Amount)
+
+		assert.NoError(t, json.Unmarshal([]byte(`{"amount": 0.30}`), &input))
+		assert.Equal(t, Decimal("0.30"), input.Amount)
+	})
+
+	t.Run("should reject other JSON types", func(t *testing.T) {
+		var typeErr *json.UnmarshalTypeError
+		assert.ErrorAs(t, json.Unmarshal([]byte(`{"amount": true}`), &input), &typeErr)
+	})
+
+	t.Run("should always write a string", func(t *testing.T) {
+		output, err := json.Marshal(New(990, "BRL").Decimal())
+		assert.NoError(t, err)
+		assert.Equal(t, `"9.90"`, string(output))
+	})
+}
+
+func TestConvert(t *testing.T) {
+	rate := func(from, to Currency, value string) Rate {
+		parsed, _ := new(big.Rat).SetString(value)
+		return Rate{From: from, To: to, Value: parsed}
+	}
+
+	t.Run("should round half away from zero to the target minor unit", func(t *testing.T) {
+		assert.Equal(t, New(196, "USD"), New(1000, "BRL").Convert(rate("BRL", "USD", "0.19607843")))
+		assert.Equal(t, New(5100, "BRL"), New(1000, "USD").Convert(rate("USD", "BRL", "5.1")))
+		assert.Equal(t, New(2, "USD"), New(3, "BRL").Convert(rate("BRL", "USD", "0.5")))
+	})
+
+	t.Run("should follow the exponent of each currency", func(t *testing.T) {
+		assert.Equal(t, New(1500, "JPY"), New(1000, "USD").Convert(rate("USD", "JPY", "150")))
+		assert.Equal(t, New(667, "USD"), New(1000, "JPY").Convert(rate("JPY", "USD", "0.00666667")))
+	})
+
+	t.Run("should format the rate without trailing zeros", func(t *testing.T) {
+		assert.Equal(t, "5.1", rate("USD", "BRL", "5.10").String())
+		assert.Equal(t, "0.33333333", rate("USD", "BRL", "1/3").String())
+		assert.Equal(t, "150", rate("USD", "JPY", "150").String())
+	})
+}
@@ -0,0 +1,180 @@
+package notification_entity
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	Outbid        NotificationType = "outbid"
+	AuctionEnding NotificationType = "auction_ending"
+	AuctionWon    NotificationType = "auction_won"
+)
+
+var NotificationTypes = []NotificationType{Outbid, AuctionEnding, AuctionWon}
+
+// Channel is how a notification reaches the user.
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelInApp Channel = "in_app"
+)
+
+var Channels = []Channel{ChannelEmail, ChannelInApp}
+
+// Notification is a message to one user about one auction. Key identifies what
+// the user is told about, so the same fact is never notified twice.
+type Notification struct {
+	Id        string
+	Key       string
+	UserId    string
+	Type      NotificationType
+	AuctionId string
+	Title     string
+	Message   string
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+func CreateNotification(
+	key, userId string,
+	notificationType NotificationType,
+	auctionId, title, message string) (*Notification, *internal_error.InternalError) {
+	notification := &Notification{
+		Id:        uuid.New().String(),
+		Key:       key,
+		UserId:    userId,
+		Type:      notificationType,
+		AuctionId: auctionId,
+		Title:     title,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+
+	if err := notification.Validate(); err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+func (n *Notification) Validate() *internal_error.InternalError {
+	if n.Key == "" {
+		return internal_error.NewBadRequestError("Key is not a valid value")
+	}
+
+	if err := uuid.Validate(n.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	}
+
+	if !slices.Contains(NotificationTypes, n.Type) {
+		return internal_error.NewBadRequestError(fmt.Sprintf("Notification type %s is not a valid value", n.Type))
+	}
+
+	if n.Title == "" || n.Message == "" {
+		return internal_error.NewBadRequestError("Notification has no content")
+	}
+
+	return nil
+}
+
+// OutbidKey tells a bidder once that their bid was beaten.
+func OutbidKey(auctionId, bidId string) string {
+	return fmt.Sprintf("%s:%s:%s", Outbid, auctionId, bidId)
+}
+
+// AuctionEndingKey tells each watcher once that the auction is about to end.
+func AuctionEndingKey(auctionId, userId string) string {
+	return fmt.Sprintf("%s:%s:%s", AuctionEnding, auctionId, userId)
+}
+
+// AuctionWonKey tells the winner once that they won the auction.
+func AuctionWonKey(auctionId, userId string) string {
+	return fmt.Sprintf("%s:%s:%s", AuctionWon, auctionId, userId)
+}
+
+// Preferences are the channels each notification type is sent through. Users
+// that never changed them get DefaultPreferences.
+type Preferences struct {
+	UserId    string
+	Channels  map[NotificationType][]Channel
+	UpdatedAt time.Time
+}
+
+func DefaultPreferences(userId string) *Preferences {
+	channels := make(map[NotificationType][]Channel, len(NotificationTypes))
+	for _, notificationType := range NotificationTypes {
+		channels[notificationType] = slices.Clone(Channels)
+	}
+
+	return &Preferences{
+		UserId:   userId,
+		Channels: channels,
+	}
+}
+
+// Validate rejects unknown types and channels. Types left out keep no channel,
+// so the user is not notified about them.
+func (p *Preferences) Validate() *internal_error.InternalError {
+	for notificationType, channels := range p.Channels {
+		if !slices.Contains(NotificationTypes, notificationType) {
+			return internal_error.NewBadRequestError(
+				fmt.Sprintf("Notification type %s is not a valid value", notificationType))
+		}
+
+		for _, channel := range channels {
+			if !slices.Contains(Channels, channel) {
+				return internal_error.NewBadRequestError(fmt.Sprintf("Channel %s is not a valid value", channel))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Preferences) Allows(notificationType NotificationType, channel Channel) bool {
+	return slices.Contains(p.Channels[notificationType], channel)
+}
+
+// NotifierInterface delivers notifications through one channel.
+type NotifierInterface interface {
+	Channel() Channel
+
+	Notify(
+		ctx context.Context,
+		recipient user_entity.User,
+		notification Notification) *internal_error.InternalError
+}
+
+type NotificationRepositoryInterface interface {
+	// ClaimNotification records key and reports whether it was new, so only the
+	// first of concurrent or repeated notifications of the same fact is sent.
+	ClaimNotification(
+		ctx context.Context, key string, claimedAt time.Time) (bool, *internal_error.InternalError)
+
+	FindPreferences(
+		ctx context.Context, userId string) (*Preferences, *internal_error.InternalError)
+
+	UpdatePreferences(
+		ctx context.Context, preferences *Preferences) *internal_error.InternalError
+
+	// FindNotifications lists the in-app notifications of the user, newest first.
+	FindNotifications(
+		ctx context.Context,
+		userId string,
+		page pagination_entity.PageRequest) ([]Notification, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	// MarkAsRead keeps the first read date when called again.
+	MarkAsRead(
+		ctx context.Context, userId, notificationId string, readAt time.Time) *internal_error.InternalError
+}
@@ -0,0 +1,56 @@
+package notification_entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNotification(t *testing.T) {
+	userId, auctionId := uuid.New().String(), uuid.New().String()
+
+	notification, err := CreateNotification(
+		AuctionWonKey(auctionId, userId), userId, AuctionWon, auctionId, "You won", "Mola maluca is yours")
+	assert.Nil(t, err)
+	assert.Equal(t, "auction_won:"+auctionId+":"+userId, notification.Key)
+	assert.Nil(t, notification.ReadAt)
+
+	_, err = CreateNotification("", userId, AuctionWon, auctionId, "You won", "Mola maluca is yours")
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = CreateNotification("key", userId, "promotion", auctionId, "You won", "Mola maluca is yours")
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = CreateNotification("key", userId, AuctionWon, auctionId, "", "")
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestPreferences(t *testing.T) {
+	t.Run("should allow every channel by default", func(t *testing.T) {
+		preferences := DefaultPreferences("user-1")
+
+		for _, notificationType := range NotificationTypes {
+			for _, channel := range Channels {
+				assert.True(t, preferences.Allows(notificationType, channel))
+			}
+		}
+	})
+
+	t.Run("should not notify types left out", func(t *testing.T) {
+		preferences := &Preferences{Channels: map[NotificationType][]Channel{Outbid: {ChannelInApp}}}
+
+		assert.Nil(t, preferences.Validate())
+		assert.True(t, preferences.Allows(Outbid, ChannelInApp))
+		assert.False(t, preferences.Allows(Outbid, ChannelEmail))
+		assert.False(t, preferences.Allows(AuctionWon, ChannelInApp))
+	})
+
+	t.Run("should reject unknown types and channels", func(t *testing.T) {
+		preferences := &Preferences{Channels: map[NotificationType][]Channel{"promotion": {ChannelInApp}}}
+		assert.Equal(t, "bad_request", preferences.Validate().Err)
+
+		preferences = &Preferences{Channels: map[NotificationType][]Channel{Outbid: {"sms"}}}
+		assert.Equal(t, "bad_request", preferences.Validate().Err)
+	})
+}
@@ -0,0 +1,170 @@
+package order_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+// Order settles a completed auction: the buyer pays the winning amount before
+// PaymentDueAt, the seller ships and the buyer confirms the receipt. When the
+// winner does not pay, the runner-up gets a second-chance order.
+type Order struct {
+	Id           string
+	AuctionId    string
+	SellerId     string
+	BuyerId      string
+	BidId        string
+	Amount       money_entity.Money
+	Status       OrderStatus
+	SecondChance bool
+	PaymentId    string
+	TrackingCode string
+	PaymentDueAt time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type OrderStatus string
+
+const (
+	AwaitingPayment OrderStatus = "awaiting_payment"
+	Paid            OrderStatus = "paid"
+	Shipped         OrderStatus = "shipped"
+	Completed       OrderStatus = "completed"
+	UnpaidTimeout   OrderStatus = "unpaid_timeout"
+)
+
+// OrderAction moves an order forward in the settlement.
+type OrderAction string
+
+const (
+	ActionPay      OrderAction = "pay"
+	ActionShip     OrderAction = "ship"
+	ActionComplete OrderAction = "complete"
+	ActionExpire   OrderAction = "expire"
+)
+
+var transitions = map[OrderAction]struct {
+	from OrderStatus
+	to   OrderStatus
+}{
+	ActionPay:      {from: AwaitingPayment, to: Paid},
+	ActionShip:     {from: Paid, to: Shipped},
+	ActionComplete: {from: Shipped, to: Completed},
+	ActionExpire:   {from: AwaitingPayment, to: UnpaidTimeout},
+}
+
+// Transition returns the status action can be applied to and the resulting status.
+func (a OrderAction) Transition() (OrderStatus, OrderStatus, bool) {
+	transition, ok := transitions[a]
+	return transition.from, transition.to, ok
+}
+
+func CreateOrder(
+	auctionId, sellerId, buyerId, bidId string,
+	amount money_entity.Money,
+	secondChance bool,
+	paymentWindow time.Duration) (*Order, *internal_error.InternalError) {
+	now := time.Now()
+	order := &Order{
+		Id:           uuid.New().String(),
+		AuctionId:    auctionId,
+		SellerId:     sellerId,
+		BuyerId:      buyerId,
+		BidId:        bidId,
+		Amount:       amount,
+		Status:       AwaitingPayment,
+		SecondChance: secondChance,
+		PaymentDueAt: now.Add(paymentWindow),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (o *Order) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(o.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	}
+
+	if err := uuid.Validate(o.BuyerId); err != nil {
+		return internal_error.NewBadRequestError("BuyerId is not a valid id")
+	}
+
+	if o.SellerId != "" && uuid.Validate(o.SellerId) != nil {
+		return internal_error.NewBadRequestError("SellerId is not a valid id")
+	}
+
+	if !o.Amount.Currency.IsValid() || !o.Amount.IsPositive() {
+		return internal_error.NewBadRequestError("Amount is not a valid value")
+	}
+
+	if !o.PaymentDueAt.After(o.CreatedAt) {
+		return internal_error.NewBadRequestError("PaymentDueAt must be after the order creation")
+	}
+
+	return nil
+}
+
+// IsOverdue reports whether the payment deadline passed without a payment.
+func (o *Order) IsOverdue(now time.Time) bool {
+	return o.Status == AwaitingPayment && !now.Before(o.PaymentDueAt)
+}
+
+// OrderFilter selects orders; empty fields are not applied.
+type OrderFilter struct {
+	BuyerId  string
+	SellerId string
+	Status   OrderStatus
+}
+
+// OrderChanges are the fields stored along with a status change.
+type OrderChanges struct {
+	PaymentId    string
+	TrackingCode string
+}
+
+type OrderRepositoryInterface interface {
+	// CreateOrder returns a conflict error when the auction already has an
+	// order of the same kind (winner or second chance).
+	CreateOrder(
+		ctx context.Context, order *Order) *internal_error.InternalError
+
+	FindOrderById(
+		ctx context.Context, id string) (*Order, *internal_error.InternalError)
+
+	FindOrders(
+		ctx context.Context,
+		filter OrderFilter,
+		page pagination_entity.PageRequest) ([]Order, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	FindOverdueOrders(
+		ctx context.Context, now time.Time, limit int64) ([]Order, *internal_error.InternalError)
+
+	// UpdateOrderStatus applies action only when the order is in its source status.
+	UpdateOrderStatus(
+		ctx context.Context,
+		id string,
+		action OrderAction,
+		changes OrderChanges) (*Order, *internal_error.InternalError)
+}
+
+// PaymentProviderInterface captures the payment of an order. Refund undoes a
+// capture whose order could not be marked as paid.
+type PaymentProviderInterface interface {
+	Capture(
+		ctx context.Context, order Order, paymentMethod string) (string, *internal_error.InternalError)
+
+	Refund(
+		ctx context.Context, paymentId string) *internal_error.InternalError
+}
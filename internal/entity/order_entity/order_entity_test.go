@@ -0,0 +1,49 @@
+package order_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrder(t *testing.T) {
+	auctionId, sellerId, buyerId := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+	t.Run("should await the payment until the deadline", func(t *testing.T) {
+		order, err := CreateOrder(auctionId, sellerId, buyerId, uuid.New().String(), money_entity.New(15000, "BRL"), false, time.Hour)
+
+		assert.Nil(t, err)
+		assert.Equal(t, AwaitingPayment, order.Status)
+		assert.Equal(t, time.Hour, order.PaymentDueAt.Sub(order.CreatedAt))
+		assert.False(t, order.IsOverdue(order.CreatedAt))
+		assert.True(t, order.IsOverdue(order.PaymentDueAt))
+	})
+
+	t.Run("should reject invalid orders", func(t *testing.T) {
+		_, err := CreateOrder(auctionId, sellerId, "buyer", uuid.New().String(), money_entity.New(15000, "BRL"), false, time.Hour)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateOrder(auctionId, sellerId, buyerId, uuid.New().String(), money_entity.New(0, "BRL"), false, time.Hour)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateOrder(auctionId, sellerId, buyerId, uuid.New().String(), money_entity.New(15000, "BRL"), false, 0)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestOrderActionTransition(t *testing.T) {
+	from, to, ok := ActionPay.Transition()
+	assert.True(t, ok)
+	assert.Equal(t, AwaitingPayment, from)
+	assert.Equal(t, Paid, to)
+
+	from, to, _ = ActionExpire.Transition()
+	assert.Equal(t, AwaitingPayment, from)
+	assert.Equal(t, UnpaidTimeout, to)
+
+	_, _, ok = OrderAction("refund").Transition()
+	assert.False(t, ok)
+}
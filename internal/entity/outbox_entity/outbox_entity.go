@@ -0,0 +1,300 @@
+package outbox_entity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	AuctionCreated   EventType = "auction.created"
+	AuctionPaused    EventType = "auction.paused"
+	AuctionResumed   EventType = "auction.resumed"
+	AuctionClosed    EventType = "auction.closed"
+	AuctionCancelled EventType = "auction.cancelled"
+	LeaderChanged    EventType = "auction.leader_changed"
+	BidAccepted      EventType = "bid.accepted"
+	BidRejected      EventType = "bid.rejected"
+)
+
+// Event is a state change of an auction or bid, recorded in the outbox by the
+// repository that made it. Sequence is zero until the relay numbers the event;
+// numbered events are delivered to every consumer in Sequence order.
+type Event struct {
+	Id         string
+	Sequence   int64
+	Type       EventType
+	AuctionId  string
+	Payload    []byte
+	OccurredAt time.Time
+}
+
+// AuctionData is the payload of AuctionCreated.
+type AuctionData struct {
+	Id           string               `json:"id"`
+	SellerId     string               `json:"seller_id,omitempty"`
+	ProductName  string               `json:"product_name"`
+	Category     string               `json:"category"`
+	Description  string               `json:"description"`
+	Condition    int                  `json:"condition"`
+	Status       int                  `json:"status"`
+	CurrentPrice money_entity.Decimal `json:"current_price"`
+	Currency     string               `json:"currency"`
+	Timestamp    time.Time            `json:"timestamp"`
+}
+
+// StatusData is the payload of the pauses, resumes, closures and
+// cancellations.
+type StatusData struct {
+	Status string `json:"status"`
+}
+
+// LeaderData is the payload of LeaderChanged; the previous leader is empty for
+// the first bid of the auction.
+type LeaderData struct {
+	BidId          string               `json:"bid_id"`
+	UserId         string               `json:"user_id"`
+	Amount         money_entity.Decimal `json:"amount"`
+	Currency       string               `json:"currency"`
+	PreviousBidId  string               `json:"previous_bid_id,omitempty"`
+	PreviousUserId string               `json:"previous_user_id,omitempty"`
+	PreviousAmount money_entity.Decimal `json:"previous_amount,omitempty"`
+}
+
+// BidData is the payload of BidAccepted. Leading tells whether the bid took
+// the lead, from the previous leader when there was one.
+type BidData struct {
+	BidId          string               `json:"bid_id"`
+	UserId         string               `json:"user_id"`
+	Amount         money_entity.Decimal `json:"amount"`
+	Currency       string               `json:"currency"`
+	Timestamp      time.Time            `json:"timestamp"`
+	Leading        bool                 `json:"leading"`
+	PreviousBidId  string               `json:"previous_bid_id,omitempty"`
+	PreviousUserId string               `json:"previous_user_id,omitempty"`
+	PreviousAmount money_entity.Decimal `json:"previous_amount,omitempty"`
+}
+
+// BidRejectedData is the payload of BidRejected.
+type BidRejectedData struct {
+	BidId     string               `json:"bid_id"`
+	UserId    string               `json:"user_id"`
+	Amount    money_entity.Decimal `json:"amount"`
+	Currency  string               `json:"currency"`
+	Timestamp time.Time            `json:"timestamp"`
+	Reason    string               `json:"reason"`
+	Detail    string               `json:"detail,omitempty"`
+}
+
+func NewAuctionCreated(auction auction_entity.Auction) Event {
+	return newEvent(AuctionCreated, auction.Id, AuctionData{
+		Id:           auction.Id,
+		SellerId:     auction.SellerId,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    int(auction.Condition),
+		Status:       int(auction.Status),
+		CurrentPrice: auction.CurrentPrice.Decimal(),
+		Currency:     string(auction.Currency()),
+		Timestamp:    auction.Timestamp,
+	})
+}
+
+// NewStatusChanged returns the event of an auction moved to status. Auctions
+// only become active again when resumed.
+func NewStatusChanged(auctionId string, status auction_entity.AuctionStatus) (Event, bool) {
+	types := map[auction_entity.AuctionStatus]EventType{
+		auction_entity.Active:    AuctionResumed,
+		auction_entity.Paused:    AuctionPaused,
+		auction_entity.Completed: AuctionClosed,
+		auction_entity.Cancelled: AuctionCancelled,
+	}
+
+	eventType, ok := types[status]
+	if !ok {
+		return Event{}, false
+	}
+
+	return newEvent(eventType, auctionId, StatusData{Status: status.String()}), true
+}
+
+// NewLeaderChanged is recorded when leader takes the lead of the auction from
+// previous, which is nil for the first bid.
+func NewLeaderChanged(
+	auctionId string, leader auction_entity.LeadingBid, previous *auction_entity.LeadingBid) Event {
+	data := LeaderData{
+		BidId:    leader.BidId,
+		UserId:   leader.UserId,
+		Amount:   leader.Amount.Decimal(),
+		Currency: string(leader.Amount.Currency),
+	}
+	if previous != nil {
+		data.PreviousBidId = previous.BidId
+		data.PreviousUserId = previous.UserId
+		data.PreviousAmount = previous.Amount.Decimal()
+	}
+
+	return newEvent(LeaderChanged, auctionId, data)
+}
+
+// NewBidAccepted is recorded once the bid is stored; previous is only kept
+// when the bid took the lead from it.
+func NewBidAccepted(bid bid_entity.Bid, leading bool, previous *auction_entity.LeadingBid) Event {
+	data := BidData{
+		BidId:     bid.Id,
+		UserId:    bid.UserId,
+		Amount:    bid.Amount.Decimal(),
+		Currency:  string(bid.Amount.Currency),
+		Timestamp: bid.Timestamp,
+		Leading:   leading,
+	}
+	if leading && previous != nil {
+		data.PreviousBidId = previous.BidId
+		data.PreviousUserId = previous.UserId
+		data.PreviousAmount = previous.Amount.Decimal()
+	}
+
+	return newEvent(BidAccepted, bid.AuctionId, data)
+}
+
+func NewBidRejected(bid bid_entity.Bid, reason bid_entity.DeadLetterReason, detail string) Event {
+	return newEvent(BidRejected, bid.AuctionId, BidRejectedData{
+		BidId:     bid.Id,
+		UserId:    bid.UserId,
+		Amount:    bid.Amount.Decimal(),
+		Currency:  string(bid.Amount.Currency),
+		Timestamp: bid.Timestamp,
+		Reason:    string(reason),
+		Detail:    detail,
+	})
+}
+
+// newEvent encodes data, which only holds strings, numbers and times and so
+// always encodes.
+func newEvent(eventType EventType, auctionId string, data interface{}) Event {
+	payload, _ := json.Marshal(data)
+
+	return Event{
+		Id:         uuid.New().String(),
+		Type:       eventType,
+		AuctionId:  auctionId,
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Decode reads the payload into data, which should be the payload type of the
+// event type.
+func (e Event) Decode(data interface{}) *internal_error.InternalError {
+	if err := json.Unmarshal(e.Payload, data); err != nil {
+		return internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to decode the payload of event %s: %s", e.Id, err))
+	}
+
+	return nil
+}
+
+func (d AuctionData) Auction() (auction_entity.Auction, *internal_error.InternalError) {
+	currentPrice, err := money_entity.Parse(string(d.CurrentPrice), money_entity.Currency(d.Currency))
+	if err != nil {
+		return auction_entity.Auction{}, err
+	}
+
+	return auction_entity.Auction{
+		Id:           d.Id,
+		SellerId:     d.SellerId,
+		ProductName:  d.ProductName,
+		Category:     d.Category,
+		Description:  d.Description,
+		Condition:    auction_entity.ProductCondition(d.Condition),
+		Status:       auction_entity.AuctionStatus(d.Status),
+		CurrentPrice: currentPrice,
+		Timestamp:    d.Timestamp,
+	}, nil
+}
+
+func (d BidData) Bid(auctionId string) (bid_entity.Bid, *internal_error.InternalError) {
+	amount, err := money_entity.Parse(string(d.Amount), money_entity.Currency(d.Currency))
+	if err != nil {
+		return bid_entity.Bid{}, err
+	}
+
+	return bid_entity.Bid{
+		Id:        d.BidId,
+		UserId:    d.UserId,
+		AuctionId: auctionId,
+		Amount:    amount,
+		Timestamp: d.Timestamp,
+	}, nil
+}
+
+// Previous returns the leader the bid took the lead from, or nil.
+func (d BidData) Previous() (*auction_entity.LeadingBid, *internal_error.InternalError) {
+	if d.PreviousBidId == "" {
+		return nil, nil
+	}
+
+	amount, err := money_entity.Parse(string(d.PreviousAmount), money_entity.Currency(d.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	return &auction_entity.LeadingBid{
+		BidId:  d.PreviousBidId,
+		UserId: d.PreviousUserId,
+		Amount: amount,
+	}, nil
+}
+
+// PublisherInterface sends the events to a system outside the application.
+// Publish may be called again with an event already published.
+type PublisherInterface interface {
+	Publish(ctx context.Context, event Event) *internal_error.InternalError
+}
+
+// WriteFunc stores a change and returns the events describing it.
+type WriteFunc func(ctx context.Context) ([]Event, *internal_error.InternalError)
+
+type OutboxRepositoryInterface interface {
+	AppendEvents(
+		ctx context.Context, events ...Event) *internal_error.InternalError
+
+	// WithEvents runs write and appends the events it returns in one
+	// transaction, so a change is never stored without its events nor the
+	// events without the change. Nothing is stored when write fails.
+	WithEvents(
+		ctx context.Context, write WriteFunc) *internal_error.InternalError
+
+	// SequenceEvents numbers up to limit events not numbered yet, in the order
+	// they were appended, and returns how many were numbered.
+	SequenceEvents(
+		ctx context.Context, limit int) (int, *internal_error.InternalError)
+
+	// FindEventsAfter returns up to limit numbered events following sequence.
+	FindEventsAfter(
+		ctx context.Context, sequence int64, limit int) ([]Event, *internal_error.InternalError)
+
+	// FindOffset returns the sequence of the last event handled by consumer, or
+	// zero for a new consumer.
+	FindOffset(
+		ctx context.Context, consumer string) (int64, *internal_error.InternalError)
+
+	// UpdateOffset moves the offset of consumer forward to sequence.
+	UpdateOffset(
+		ctx context.Context, consumer string, sequence int64) *internal_error.InternalError
+
+	// DeadLetterEvent keeps event, which consumer gave up handling after
+	// detail, so it can be inspected and handled by hand.
+	DeadLetterEvent(
+		ctx context.Context, consumer string, event Event, detail string) *internal_error.InternalError
+}
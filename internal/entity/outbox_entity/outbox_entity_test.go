@@ -0,0 +1,144 @@
+package outbox_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuctionCreated(t *testing.T) {
+	auction := auction_entity.Auction{
+		Id:           "auction-1",
+		SellerId:     "seller-1",
+		ProductName:  "Camera",
+		Category:     "Photo",
+		Description:  "A vintage film camera",
+		Condition:    auction_entity.Used,
+		Status:       auction_entity.Active,
+		CurrentPrice: money_entity.New(12000, "USD"),
+		Timestamp:    time.Unix(1700000000, 0).UTC(),
+	}
+
+	event := NewAuctionCreated(auction)
+
+	assert.Equal(t, AuctionCreated, event.Type)
+	assert.Equal(t, "auction-1", event.AuctionId)
+	assert.NotEmpty(t, event.Id)
+	assert.Zero(t, event.Sequence)
+
+	var data AuctionData
+	require.Nil(t, event.Decode(&data))
+	assert.Equal(t, money_entity.Decimal("120.00"), data.CurrentPrice)
+
+	decoded, err := data.Auction()
+	require.Nil(t, err)
+	assert.Equal(t, auction, decoded)
+}
+
+func TestNewStatusChanged(t *testing.T) {
+	cases := map[auction_entity.AuctionStatus]EventType{
+		auction_entity.Active:    AuctionResumed,
+		auction_entity.Paused:    AuctionPaused,
+		auction_entity.Completed: AuctionClosed,
+		auction_entity.Cancelled: AuctionCancelled,
+	}
+
+	for status, expected := range cases {
+		event, ok := NewStatusChanged("auction-1", status)
+		assert.True(t, ok)
+		assert.Equal(t, expected, event.Type)
+
+		var data StatusData
+		require.Nil(t, event.Decode(&data))
+		assert.Equal(t, status.String(), data.Status)
+	}
+
+	_, ok := NewStatusChanged("auction-1", auction_entity.AuctionStatus(42))
+	assert.False(t, ok)
+}
+
+func TestNewLeaderChanged(t *testing.T) {
+	event := NewLeaderChanged("auction-1",
+		auction_entity.LeadingBid{BidId: "bid-2", UserId: "user-2", Amount: money_entity.New(1550, "BRL")},
+		&auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1", Amount: money_entity.New(1000, "BRL")})
+
+	var data LeaderData
+	require.Nil(t, event.Decode(&data))
+	assert.Equal(t, LeaderChanged, event.Type)
+	assert.Equal(t, LeaderData{
+		BidId:          "bid-2",
+		UserId:         "user-2",
+		Amount:         "15.50",
+		Currency:       "BRL",
+		PreviousBidId:  "bid-1",
+		PreviousUserId: "user-1",
+		PreviousAmount: "10.00",
+	}, data)
+}
+
+func TestNewBidAccepted(t *testing.T) {
+	bid := bid_entity.Bid{
+		Id:        "bid-2",
+		UserId:    "user-2",
+		AuctionId: "auction-1",
+		Amount:    money_entity.New(1550, "BRL"),
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+	}
+	previous := &auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1", Amount: money_entity.New(1000, "BRL")}
+
+	t.Run("should keep the previous leader of a leading bid", func(t *testing.T) {
+		event := NewBidAccepted(bid, true, previous)
+
+		var data BidData
+		require.Nil(t, event.Decode(&data))
+		assert.True(t, data.Leading)
+
+		decodedBid, err := data.Bid(event.AuctionId)
+		require.Nil(t, err)
+		assert.Equal(t, bid, decodedBid)
+
+		decodedPrevious, err := data.Previous()
+		require.Nil(t, err)
+		assert.Equal(t, previous, decodedPrevious)
+	})
+
+	t.Run("should drop the previous leader of a bid that did not lead", func(t *testing.T) {
+		event := NewBidAccepted(bid, false, previous)
+
+		var data BidData
+		require.Nil(t, event.Decode(&data))
+		assert.False(t, data.Leading)
+
+		decodedPrevious, err := data.Previous()
+		require.Nil(t, err)
+		assert.Nil(t, decodedPrevious)
+	})
+}
+
+func TestNewBidRejected(t *testing.T) {
+	bid := bid_entity.Bid{Id: "bid-1", UserId: "user-1", AuctionId: "auction-1", Amount: money_entity.New(500, "BRL")}
+
+	event := NewBidRejected(bid, bid_entity.ReasonAuctionEnded, "auction ended")
+
+	var data BidRejectedData
+	require.Nil(t, event.Decode(&data))
+	assert.Equal(t, BidRejected, event.Type)
+	assert.Equal(t, "auction-1", event.AuctionId)
+	assert.Equal(t, string(bid_entity.ReasonAuctionEnded), data.Reason)
+	assert.Equal(t, money_entity.Decimal("5.00"), data.Amount)
+}
+
+func TestEventDecode(t *testing.T) {
+	event := Event{Id: "event-1", Payload: []byte("not json")}
+
+	var data StatusData
+	err := event.Decode(&data)
+
+	require.NotNil(t, err)
+	assert.Equal(t, "internal_server_error", err.Err)
+}
@@ -0,0 +1,33 @@
+package pagination_entity
+
+const (
+	DefaultLimit int64 = 20
+	MaxLimit     int64 = 100
+)
+
+type PageRequest struct {
+	Limit        int64
+	Cursor       string
+	IncludeTotal bool
+}
+
+type PageInfo struct {
+	Limit      int64
+	NextCursor string
+	HasMore    bool
+	Total      *int64
+}
+
+// NormalizedLimit returns the page size clamped to the accepted range,
+// falling back to DefaultLimit when no limit was informed.
+func (p PageRequest) NormalizedLimit() int64 {
+	if p.Limit <= 0 {
+		return DefaultLimit
+	}
+
+	if p.Limit > MaxLimit {
+		return MaxLimit
+	}
+
+	return p.Limit
+}
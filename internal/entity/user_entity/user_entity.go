@@ -2,16 +2,161 @@ package user_entity
 
 import (
 	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
-	Id   string
-	Name string
+	Id           string
+	Name         string
+	Email        string
+	PasswordHash string
+	Roles        []Role
+	Timestamp    time.Time
+}
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleSeller Role = "seller"
+	RoleBidder Role = "bidder"
+)
+
+// DefaultRoles are given to users that register without choosing roles.
+var DefaultRoles = []Role{RoleBidder}
+
+func CreateUser(name, email, password string) (*User, *internal_error.InternalError) {
+	user := &User{
+		Id:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Email:     NormalizeEmail(email),
+		Roles:     DefaultRoles,
+		Timestamp: time.Now(),
+	}
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *User) Validate() *internal_error.InternalError {
+	if len(u.Name) <= 1 {
+		return internal_error.NewBadRequestError("Name is not a valid value")
+	}
+
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return internal_error.NewBadRequestError("Email is not a valid value")
+	}
+
+	if len(u.Roles) == 0 {
+		return internal_error.NewBadRequestError("User must have at least one role")
+	}
+
+	for _, role := range u.Roles {
+		if role != RoleAdmin && role != RoleSeller && role != RoleBidder {
+			return internal_error.NewBadRequestError(fmt.Sprintf("Role %s is not a valid value", role))
+		}
+	}
+
+	return nil
+}
+
+// SetRoles replaces the user roles, ignoring duplicates.
+func (u *User) SetRoles(roles []Role) *internal_error.InternalError {
+	unique := make([]Role, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+
+	previous := u.Roles
+	u.Roles = unique
+	if err := u.Validate(); err != nil {
+		u.Roles = previous
+		return err
+	}
+
+	return nil
+}
+
+func (u *User) HasRole(role Role) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+// RoleNames returns the roles as plain strings, the format carried by tokens.
+func (u *User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, string(role))
+	}
+
+	return names
+}
+
+// SetPassword stores the bcrypt hash of the password; the plain text is never kept.
+func (u *User) SetPassword(password string) *internal_error.InternalError {
+	if len(password) < 8 || len(password) > 72 {
+		return internal_error.NewBadRequestError("Password must have between 8 and 72 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return internal_error.NewInternalServerError("Error trying to hash password")
+	}
+
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword reports whether password matches the stored hash. Users without
+// a password (created before authentication existed) can never log in.
+func (u *User) CheckPassword(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// NormalizeEmail trims and lowercases an email so uniqueness does not depend on
+// how the user typed it.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 type UserRepositoryInterface interface {
+	CreateUser(
+		ctx context.Context, user *User) *internal_error.InternalError
+
+	UpdateUser(
+		ctx context.Context, user *User) *internal_error.InternalError
+
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	FindUserByEmail(
+		ctx context.Context, email string) (*User, *internal_error.InternalError)
+
+	UpdateUserRoles(
+		ctx context.Context, userId string, roles []Role) *internal_error.InternalError
+
+	FindUsers(
+		ctx context.Context,
+		page pagination_entity.PageRequest) ([]User, *pagination_entity.PageInfo, *internal_error.InternalError)
 }
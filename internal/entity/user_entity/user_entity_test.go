@@ -0,0 +1,78 @@
+package user_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUser(t *testing.T) {
+	t.Run("should normalize the email", func(t *testing.T) {
+		user, err := CreateUser(" Maria ", "  Maria@Example.COM ", "s3cret-password")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Maria", user.Name)
+		assert.Equal(t, "maria@example.com", user.Email)
+	})
+
+	t.Run("should hash the password", func(t *testing.T) {
+		user, err := CreateUser("Maria", "maria@example.com", "s3cret-password")
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, "s3cret-password", user.PasswordHash)
+		assert.True(t, user.CheckPassword("s3cret-password"))
+		assert.False(t, user.CheckPassword("wrong-password"))
+	})
+
+	t.Run("should reject a short password", func(t *testing.T) {
+		_, err := CreateUser("Maria", "maria@example.com", "short")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+
+	t.Run("should reject an invalid email", func(t *testing.T) {
+		for _, email := range []string{"", "maria", "Maria <maria@example.com>"} {
+			_, err := CreateUser("Maria", email, "s3cret-password")
+
+			assert.NotNil(t, err, "email %q", email)
+			assert.Equal(t, "bad_request", err.Err)
+		}
+	})
+
+	t.Run("should reject a short name", func(t *testing.T) {
+		_, err := CreateUser("M", "maria@example.com", "s3cret-password")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestSetRoles(t *testing.T) {
+	t.Run("should default to bidder", func(t *testing.T) {
+		user, err := CreateUser("Maria", "maria@example.com", "s3cret-password")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Role{RoleBidder}, user.Roles)
+	})
+
+	t.Run("should replace roles ignoring duplicates", func(t *testing.T) {
+		user, _ := CreateUser("Maria", "maria@example.com", "s3cret-password")
+
+		err := user.SetRoles([]Role{RoleSeller, RoleBidder, RoleSeller})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Role{RoleSeller, RoleBidder}, user.Roles)
+		assert.True(t, user.HasRole(RoleSeller))
+		assert.False(t, user.HasRole(RoleAdmin))
+	})
+
+	t.Run("should keep the roles when the new ones are invalid", func(t *testing.T) {
+		user, _ := CreateUser("Maria", "maria@example.com", "s3cret-password")
+
+		err := user.SetRoles([]Role{"owner"})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []Role{RoleBidder}, user.Roles)
+	})
+}
@@ -0,0 +1,63 @@
+package watchlist_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+// WatchedAuction is an auction followed by a user, who is not required to bid
+// on it. A user watches an auction at most once.
+type WatchedAuction struct {
+	UserId    string
+	AuctionId string
+	WatchedAt time.Time
+}
+
+func CreateWatchedAuction(userId, auctionId string) (*WatchedAuction, *internal_error.InternalError) {
+	watched := &WatchedAuction{
+		UserId:    userId,
+		AuctionId: auctionId,
+		WatchedAt: time.Now(),
+	}
+
+	if err := watched.Validate(); err != nil {
+		return nil, err
+	}
+
+	return watched, nil
+}
+
+func (w *WatchedAuction) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(w.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	}
+
+	if err := uuid.Validate(w.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	}
+
+	return nil
+}
+
+type WatchlistRepositoryInterface interface {
+	// WatchAuction keeps the original date when the auction is already watched.
+	WatchAuction(
+		ctx context.Context, watched *WatchedAuction) *internal_error.InternalError
+
+	UnwatchAuction(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	// FindWatchlist lists the auctions watched by the user, most recent first.
+	FindWatchlist(
+		ctx context.Context,
+		userId string,
+		page pagination_entity.PageRequest) ([]WatchedAuction, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	// FindWatchers returns the ids of the users watching the auction.
+	FindWatchers(
+		ctx context.Context, auctionId string) ([]string, *internal_error.InternalError)
+}
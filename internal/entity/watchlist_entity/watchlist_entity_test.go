@@ -0,0 +1,24 @@
+package watchlist_entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWatchedAuction(t *testing.T) {
+	userId, auctionId := uuid.New().String(), uuid.New().String()
+
+	watched, err := CreateWatchedAuction(userId, auctionId)
+	assert.Nil(t, err)
+	assert.Equal(t, userId, watched.UserId)
+	assert.Equal(t, auctionId, watched.AuctionId)
+	assert.False(t, watched.WatchedAt.IsZero())
+
+	_, err = CreateWatchedAuction("user", auctionId)
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = CreateWatchedAuction(userId, "auction")
+	assert.Equal(t, "bad_request", err.Err)
+}
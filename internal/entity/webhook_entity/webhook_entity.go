@@ -0,0 +1,235 @@
+package webhook_entity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	AuctionCreated   EventType = "auction.created"
+	BidAccepted      EventType = "bid.accepted"
+	AuctionClosed    EventType = "auction.closed"
+	AuctionCancelled EventType = "auction.cancelled"
+)
+
+var EventTypes = []EventType{AuctionCreated, BidAccepted, AuctionClosed, AuctionCancelled}
+
+// Webhook is a partner endpoint subscribed to some event types. Secret signs
+// every delivery so the partner can check it came from us.
+type Webhook struct {
+	Id          string
+	Url         string
+	Description string
+	Events      []EventType
+	Secret      string
+	CreatedAt   time.Time
+}
+
+const secretPrefix = "whsec_"
+
+func CreateWebhook(
+	webhookUrl, description string, events []EventType) (*Webhook, *internal_error.InternalError) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, internal_error.NewInternalServerError("Error trying to generate webhook secret")
+	}
+
+	webhook := &Webhook{
+		Id:          uuid.New().String(),
+		Url:         webhookUrl,
+		Description: description,
+		Events:      slices.Compact(slices.Sorted(slices.Values(events))),
+		Secret:      secretPrefix + hex.EncodeToString(random),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (w *Webhook) Validate() *internal_error.InternalError {
+	parsed, err := url.Parse(w.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return internal_error.NewBadRequestError("Url is not a valid http or https url")
+	}
+
+	if len(w.Events) == 0 {
+		return internal_error.NewBadRequestError("Webhook must subscribe to at least one event")
+	}
+
+	for _, event := range w.Events {
+		if !slices.Contains(EventTypes, event) {
+			return internal_error.NewBadRequestError(fmt.Sprintf("Event %s is not a valid value", event))
+		}
+	}
+
+	return nil
+}
+
+func (w *Webhook) Subscribes(event EventType) bool {
+	return slices.Contains(w.Events, event)
+}
+
+// Sign is the hex HMAC-SHA256 of "<timestamp>.<payload>" with secret. Signing
+// the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to one webhook, kept as the delivery log. Every
+// delivery of the same event shares EventId, so partners can ignore repeats.
+type Delivery struct {
+	Id             string
+	WebhookId      string
+	EventId        string
+	Event          EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	RedeliveryOf   string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewDelivery(webhookId, eventId string, event EventType, payload []byte) Delivery {
+	now := time.Now()
+
+	return Delivery{
+		Id:            uuid.New().String(),
+		WebhookId:     webhookId,
+		EventId:       eventId,
+		Event:         event,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Redeliver sends the same event again as a new delivery, with its own attempts.
+func (d *Delivery) Redeliver() Delivery {
+	redelivery := NewDelivery(d.WebhookId, d.EventId, d.Event, d.Payload)
+	redelivery.RedeliveryOf = d.Id
+
+	return redelivery
+}
+
+// RetryPolicy spaces the attempts of a delivery with exponential backoff:
+// Base, 2*Base, 4*Base... up to Max, giving up after MaxAttempts.
+type RetryPolicy struct {
+	MaxAttempts int
+	Base        time.Duration
+	Max         time.Duration
+}
+
+// Backoff is the wait after the attempt-th failed attempt.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.Base
+	for i := 1; i < attempt && backoff < p.Max; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, p.Max)
+}
+
+// Record stores the outcome of an attempt, scheduling the next one or giving
+// up once the policy is exhausted.
+func (d *Delivery) Record(statusCode int, attemptErr string, policy RetryPolicy, now time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = attemptErr
+	d.UpdatedAt = now
+
+	switch {
+	case attemptErr == "" && statusCode >= 200 && statusCode < 300:
+		d.Status = DeliverySucceeded
+	case d.Attempts >= policy.MaxAttempts:
+		d.Status = DeliveryFailed
+	default:
+		d.NextAttemptAt = now.Add(policy.Backoff(d.Attempts))
+	}
+}
+
+// SenderInterface posts a delivery to the webhook, returning the status code
+// answered by the receiver.
+type SenderInterface interface {
+	Send(
+		ctx context.Context,
+		webhook Webhook,
+		delivery Delivery) (int, *internal_error.InternalError)
+}
+
+type WebhookRepositoryInterface interface {
+	CreateWebhook(
+		ctx context.Context, webhook *Webhook) *internal_error.InternalError
+
+	FindWebhookById(
+		ctx context.Context, id string) (*Webhook, *internal_error.InternalError)
+
+	FindWebhooks(
+		ctx context.Context,
+		page pagination_entity.PageRequest) ([]Webhook, *pagination_entity.PageInfo, *internal_error.InternalError)
+
+	// FindWebhooksByEvent returns every webhook subscribed to event.
+	FindWebhooksByEvent(
+		ctx context.Context, event EventType) ([]Webhook, *internal_error.InternalError)
+
+	DeleteWebhook(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	CreateDeliveries(
+		ctx context.Context, deliveries []Delivery) *internal_error.InternalError
+
+	// ClaimDueDelivery takes the pending delivery due the longest, pushing its
+	// next attempt by lease so no other dispatcher sends it meanwhile. It
+	// returns nil when no delivery is due.
+	ClaimDueDelivery(
+		ctx context.Context, now time.Time, lease time.Duration) (*Delivery, *internal_error.InternalError)
+
+	UpdateDelivery(
+		ctx context.Context, delivery *Delivery) *internal_error.InternalError
+
+	FindDeliveryById(
+		ctx context.Context, webhookId, id string) (*Delivery, *internal_error.InternalError)
+
+	// FindDeliveries lists the deliveries of the webhook, newest first.
+	FindDeliveries(
+		ctx context.Context,
+		webhookId string,
+		status DeliveryStatus,
+		page pagination_entity.PageRequest) ([]Delivery, *pagination_entity.PageInfo, *internal_error.InternalError)
+}
@@ -0,0 +1,92 @@
+package webhook_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("should create a webhook with a secret", func(t *testing.T) {
+		webhook, err := CreateWebhook(
+			"https://partner.example.com/hooks", "Partner", []EventType{BidAccepted, AuctionCreated, BidAccepted})
+
+		require.Nil(t, err)
+		assert.Equal(t, []EventType{AuctionCreated, BidAccepted}, webhook.Events)
+		assert.Regexp(t, "^whsec_[0-9a-f]{64}$", webhook.Secret)
+		assert.True(t, webhook.Subscribes(BidAccepted))
+		assert.False(t, webhook.Subscribes(AuctionClosed))
+	})
+
+	t.Run("should reject invalid urls and events", func(t *testing.T) {
+		_, err := CreateWebhook("ftp://partner.example.com", "", []EventType{BidAccepted})
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateWebhook("https://partner.example.com", "", nil)
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateWebhook("https://partner.example.com", "", []EventType{"auction.extended"})
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestSign(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+
+	// echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac whsec_test
+	assert.Equal(t,
+		"11bf4466ea17c3df3fd743af0b435368e16b7a05eb8eced85e8c4670767bdec5",
+		Sign("whsec_test", timestamp, []byte(`{"id":"1"}`)))
+}
+
+func TestDeliveryRecord(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Base: time.Second, Max: 3 * time.Second}
+	now := time.Now()
+
+	t.Run("should back off exponentially up to the max", func(t *testing.T) {
+		assert.Equal(t, time.Second, policy.Backoff(1))
+		assert.Equal(t, 2*time.Second, policy.Backoff(2))
+		assert.Equal(t, 3*time.Second, policy.Backoff(3))
+		assert.Equal(t, 3*time.Second, policy.Backoff(10))
+	})
+
+	t.Run("should succeed on 2xx", func(t *testing.T) {
+		delivery := NewDelivery("webhook-1", "event-1", BidAccepted, []byte("{}"))
+
+		delivery.Record(204, "", policy, now)
+
+		assert.Equal(t, DeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+	})
+
+	t.Run("should retry and then give up", func(t *testing.T) {
+		delivery := NewDelivery("webhook-1", "event-1", BidAccepted, []byte("{}"))
+
+		delivery.Record(500, "", policy, now)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, now.Add(time.Second), delivery.NextAttemptAt)
+
+		delivery.Record(0, "connection refused", policy, now)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, now.Add(2*time.Second), delivery.NextAttemptAt)
+
+		delivery.Record(500, "", policy, now)
+		assert.Equal(t, DeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+	})
+
+	t.Run("should redeliver as a new delivery of the same event", func(t *testing.T) {
+		delivery := NewDelivery("webhook-1", "event-1", BidAccepted, []byte("{}"))
+		delivery.Record(500, "", RetryPolicy{MaxAttempts: 1}, now)
+
+		redelivery := delivery.Redeliver()
+
+		assert.NotEqual(t, delivery.Id, redelivery.Id)
+		assert.Equal(t, delivery.EventId, redelivery.EventId)
+		assert.Equal(t, delivery.Id, redelivery.RedeliveryOf)
+		assert.Equal(t, DeliveryPending, redelivery.Status)
+		assert.Zero(t, redelivery.Attempts)
+	})
+}
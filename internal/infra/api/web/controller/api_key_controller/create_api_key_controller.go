@@ -0,0 +1,67 @@
+package api_key_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/api_key_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiKeyController struct {
+	apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface
+}
+
+func NewApiKeyController(apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface) *ApiKeyController {
+	return &ApiKeyController{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (u *ApiKeyController) IssueApiKey(c *gin.Context) {
+	var apiKeyInputDTO api_key_usecase.ApiKeyInputDTO
+
+	if err := c.ShouldBindJSON(&apiKeyInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	apiKeyData, err := u.apiKeyUseCase.IssueApiKey(c.Request.Context(), apiKeyInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	// The response carries the only copy of the secret.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, apiKeyData)
+}
+
+func (u *ApiKeyController) RevokeApiKey(c *gin.Context) {
+	apiKeyId := c.Param("apiKeyId")
+
+	if err := uuid.Validate(apiKeyId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "apiKeyId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.apiKeyUseCase.RevokeApiKey(c.Request.Context(), apiKeyId); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
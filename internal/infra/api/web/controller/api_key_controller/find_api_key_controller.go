@@ -0,0 +1,30 @@
+package api_key_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/api_key_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (u *ApiKeyController) FindApiKeys(c *gin.Context) {
+	var findApiKeysInputDTO api_key_usecase.FindApiKeysInputDTO
+	if err := c.ShouldBindQuery(&findApiKeysInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	apiKeyPage, err := u.apiKeyUseCase.FindApiKeys(c.Request.Context(), findApiKeysInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	apiKeyPage.Links = pagination.Links(c.Request.URL, apiKeyPage.Page)
+	c.JSON(http.StatusOK, apiKeyPage)
+}
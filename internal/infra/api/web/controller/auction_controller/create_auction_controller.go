@@ -1,9 +1,11 @@
 package auction_controller
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
@@ -29,8 +31,10 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
+	auctionInputDTO.SellerId, _ = middleware.UserId(c)
+
 	requestCtx := c.Request.Context()
-	err := u.auctionUseCase.CreateAuction(requestCtx, auctionInputDTO)
+	auctionData, err := u.auctionUseCase.CreateAuction(requestCtx, auctionInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -38,5 +42,6 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.Header("Location", fmt.Sprintf("/auction/%s", auctionData.Id))
+	c.JSON(http.StatusCreated, auctionData)
 }
@@ -1,13 +1,13 @@
 package auction_controller
 
 import (
-	"context"
-
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
 
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,7 +26,14 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindAuctionById(context.Background(), auctionId)
+	var displayCurrencyInputDTO money_usecase.DisplayCurrencyInputDTO
+	if err := c.ShouldBindQuery(&displayCurrencyInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctionData, err := u.auctionUseCase.FindAuctionById(c.Request.Context(), auctionId, displayCurrencyInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -37,30 +44,41 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 }
 
 func (u *AuctionController) FindAuctions(c *gin.Context) {
-	status := c.Query("status")
-	category := c.Query("category")
-	productName := c.Query("productName")
+	var findAuctionsInputDTO auction_usecase.FindAuctionsInputDTO
+	if err := c.ShouldBindQuery(&findAuctionsInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
 
-	if status == "" {
-		status = "-1"
+	auctionPage, err := u.auctionUseCase.FindAuctions(c.Request.Context(), findAuctionsInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
 	}
 
-	statusNumber, errConv := strconv.Atoi(status)
-	if errConv != nil {
-		errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
+	auctionPage.Links = pagination.Links(c.Request.URL, auctionPage.Page)
+	c.JSON(http.StatusOK, auctionPage)
+}
+
+func (u *AuctionController) SearchAuctions(c *gin.Context) {
+	var searchAuctionsInputDTO auction_usecase.SearchAuctionsInputDTO
+	if err := c.ShouldBindQuery(&searchAuctionsInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	auctionPage, err := u.auctionUseCase.SearchAuctions(c.Request.Context(), searchAuctionsInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	c.JSON(http.StatusOK, auctions)
+	auctionPage.Links = pagination.Links(c.Request.URL, auctionPage.Page)
+	c.JSON(http.StatusOK, auctionPage)
 }
 
 func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
@@ -76,7 +94,15 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionId)
+	var displayCurrencyInputDTO money_usecase.DisplayCurrencyInputDTO
+	if err := c.ShouldBindQuery(&displayCurrencyInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(
+		c.Request.Context(), auctionId, displayCurrencyInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
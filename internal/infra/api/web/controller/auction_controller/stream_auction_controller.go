@@ -0,0 +1,109 @@
+package auction_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// heartbeatInterval keeps idle streams from being closed by proxies.
+var heartbeatInterval = 15 * time.Second
+
+// StreamAuctionEvents pushes the events of the auction as server-sent events.
+// Clients resume with the Last-Event-ID header (or the lastEventId query
+// parameter), which EventSource sends when it reconnects. The stream ends after
+// the auction closes; for an auction already over with nothing to replay it
+// answers 204, which makes EventSource stop reconnecting.
+func (u *AuctionController) StreamAuctionEvents(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	lastEventId := c.GetHeader("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = c.Query("lastEventId")
+	}
+
+	stream, err := u.auctionUseCase.StreamAuctionEvents(c.Request.Context(), auctionId, lastEventId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	defer stream.Subscription.Close()
+
+	if !stream.Open && len(stream.Subscription.Replay) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range stream.Subscription.Replay {
+		if !writeEvent(c, event) || event.Final() {
+			return
+		}
+	}
+	if !stream.Open {
+		return
+	}
+	// Sends the headers even when there was nothing to replay.
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-stream.Subscription.Events:
+			// A closed channel means the client fell behind; it resumes from
+			// the last event it received when EventSource reconnects.
+			if !ok || !writeEvent(c, event) || event.Final() {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeEvent(c *gin.Context, event event_entity.Event) bool {
+	data, err := json.Marshal(auction_usecase.NewAuctionEventOutputDTO(event))
+	if err != nil {
+		logger.Error("Error trying to encode auction event", err)
+		return false
+	}
+
+	if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data); err != nil {
+		return false
+	}
+	c.Writer.Flush()
+
+	return true
+}
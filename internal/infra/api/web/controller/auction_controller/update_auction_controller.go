@@ -0,0 +1,37 @@
+package auction_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ChangeAuctionStatus returns the handler of an administrative action, so each
+// action gets its own route (POST /admin/auction/:auctionId/pause, ...).
+func (u *AuctionController) ChangeAuctionStatus(action auction_entity.AuctionAction) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auctionId := c.Param("auctionId")
+
+		if err := uuid.Validate(auctionId); err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "auctionId",
+				Message: "Invalid UUID value",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		auctionData, err := u.auctionUseCase.ChangeAuctionStatus(c.Request.Context(), auctionId, action)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		c.JSON(http.StatusOK, auctionData)
+	}
+}
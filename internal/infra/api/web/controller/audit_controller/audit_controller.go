@@ -0,0 +1,54 @@
+package audit_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/audit_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	auditUseCase audit_usecase.AuditUseCaseInterface
+}
+
+func NewAuditController(auditUseCase audit_usecase.AuditUseCaseInterface) *AuditController {
+	return &AuditController{
+		auditUseCase: auditUseCase,
+	}
+}
+
+func (u *AuditController) FindEntries(c *gin.Context) {
+	var findEntriesInputDTO audit_usecase.FindEntriesInputDTO
+	if err := c.ShouldBindQuery(&findEntriesInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	entryPage, err := u.auditUseCase.FindEntries(c.Request.Context(), findEntriesInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	entryPage.Links = pagination.Links(c.Request.URL, entryPage.Page)
+	c.JSON(http.StatusOK, entryPage)
+}
+
+func (u *AuditController) VerifyChain(c *gin.Context) {
+	verifyData, err := u.auditUseCase.VerifyChain(c.Request.Context())
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, verifyData)
+}
@@ -1,10 +1,10 @@
 package bid_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
@@ -30,7 +30,9 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	bidInputDTO.UserId, _ = middleware.UserId(c)
+
+	bidData, err := u.bidUseCase.CreateBid(c.Request.Context(), bidInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -38,5 +40,5 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, bidData)
 }
@@ -1,10 +1,12 @@
 package bid_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -22,12 +24,39 @@ func (u *BidController) FindBidByAuctionId(c *gin.Context) {
 		return
 	}
 
-	bidOutputList, err := u.bidUseCase.FindBidByAuctionId(context.Background(), auctionId)
+	var findBidsInputDTO bid_usecase.FindBidsInputDTO
+	if err := c.ShouldBindQuery(&findBidsInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	bidPage, err := u.bidUseCase.FindBidByAuctionId(c.Request.Context(), auctionId, findBidsInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	bidPage.Links = pagination.Links(c.Request.URL, bidPage.Page)
+	c.JSON(http.StatusOK, bidPage)
+}
+
+func (u *BidController) FindDeadLetterBids(c *gin.Context) {
+	var findDeadLetterBidsInputDTO bid_usecase.FindDeadLetterBidsInputDTO
+	if err := c.ShouldBindQuery(&findDeadLetterBidsInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	deadLetterPage, err := u.bidUseCase.FindDeadLetterBids(c.Request.Context(), findDeadLetterBidsInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	c.JSON(http.StatusOK, bidOutputList)
+	deadLetterPage.Links = pagination.Links(c.Request.URL, deadLetterPage.Page)
+	c.JSON(http.StatusOK, deadLetterPage)
 }
@@ -0,0 +1,44 @@
+package category_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/category_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (u *CategoryController) FindCategoryById(c *gin.Context) {
+	categoryId, ok := categoryIdParam(c)
+	if !ok {
+		return
+	}
+
+	categoryData, err := u.categoryUseCase.FindCategoryById(c.Request.Context(), categoryId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categoryData)
+}
+
+func (u *CategoryController) FindCategories(c *gin.Context) {
+	var findCategoriesInputDTO category_usecase.FindCategoriesInputDTO
+	if err := c.ShouldBindQuery(&findCategoriesInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	categories, err := u.categoryUseCase.FindCategories(c.Request.Context(), findCategoriesInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
@@ -0,0 +1,87 @@
+package credit_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/credit_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreditController struct {
+	creditUseCase credit_usecase.CreditUseCaseInterface
+}
+
+func NewCreditController(creditUseCase credit_usecase.CreditUseCaseInterface) *CreditController {
+	return &CreditController{
+		creditUseCase: creditUseCase,
+	}
+}
+
+func (u *CreditController) FindCreditAccount(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	authenticatedId, _ := middleware.UserId(c)
+	if authenticatedId != userId && !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		errRest := rest_err.NewForbiddenError("You can only see your own credit")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	accountData, err := u.creditUseCase.FindCreditAccount(c.Request.Context(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, accountData)
+}
+
+func (u *CreditController) SetCreditLimit(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	var creditLimitInputDTO credit_usecase.CreditLimitInputDTO
+	if err := c.ShouldBindJSON(&creditLimitInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	accountData, err := u.creditUseCase.SetCreditLimit(c.Request.Context(), userId, creditLimitInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, accountData)
+}
+
+func userIdParam(c *gin.Context) (string, bool) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
@@ -0,0 +1,36 @@
+package health_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/health_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	healthUseCase health_usecase.HealthUseCaseInterface
+}
+
+func NewHealthController(healthUseCase health_usecase.HealthUseCaseInterface) *HealthController {
+	return &HealthController{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// Liveness only tells that the process serves requests; the components are
+// checked by Readiness.
+func (u *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, health_usecase.ComponentOutputDTO{Status: health_usecase.StatusUp})
+}
+
+// Readiness answers 503 while a component is down or the application is
+// shutting down, with the status of each component in both cases.
+func (u *HealthController) Readiness(c *gin.Context) {
+	readiness := u.healthUseCase.CheckReadiness(c.Request.Context())
+	if readiness.Status != health_usecase.StatusReady {
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+
+	c.JSON(http.StatusOK, readiness)
+}
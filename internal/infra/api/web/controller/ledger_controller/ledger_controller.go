@@ -0,0 +1,131 @@
+package ledger_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/ledger_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LedgerController struct {
+	ledgerUseCase ledger_usecase.LedgerUseCaseInterface
+}
+
+func NewLedgerController(ledgerUseCase ledger_usecase.LedgerUseCaseInterface) *LedgerController {
+	return &LedgerController{
+		ledgerUseCase: ledgerUseCase,
+	}
+}
+
+func (u *LedgerController) RetractBid(c *gin.Context) {
+	bidId, ok := uuidParam(c, "bidId")
+	if !ok {
+		return
+	}
+
+	var retractBidInputDTO ledger_usecase.RetractBidInputDTO
+	if err := c.ShouldBindJSON(&retractBidInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	projectionData, err := u.ledgerUseCase.RetractBid(c.Request.Context(), bidId, retractBidInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, projectionData)
+}
+
+func (u *LedgerController) ExtendAuction(c *gin.Context) {
+	auctionId, ok := uuidParam(c, "auctionId")
+	if !ok {
+		return
+	}
+
+	var extendAuctionInputDTO ledger_usecase.ExtendAuctionInputDTO
+	if err := c.ShouldBindJSON(&extendAuctionInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	projectionData, err := u.ledgerUseCase.ExtendAuction(c.Request.Context(), auctionId, extendAuctionInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, projectionData)
+}
+
+func (u *LedgerController) FindLedger(c *gin.Context) {
+	auctionId, ok := uuidParam(c, "auctionId")
+	if !ok {
+		return
+	}
+
+	var findLedgerInputDTO ledger_usecase.FindLedgerInputDTO
+	if err := c.ShouldBindQuery(&findLedgerInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	ledgerPage, err := u.ledgerUseCase.FindLedger(c.Request.Context(), auctionId, findLedgerInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	ledgerPage.Links = pagination.Links(c.Request.URL, ledgerPage.Page)
+	c.JSON(http.StatusOK, ledgerPage)
+}
+
+func (u *LedgerController) ReplayAuction(c *gin.Context) {
+	auctionId, ok := uuidParam(c, "auctionId")
+	if !ok {
+		return
+	}
+
+	projectionData, err := u.ledgerUseCase.ReplayAuction(c.Request.Context(), auctionId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, projectionData)
+}
+
+func uuidParam(c *gin.Context, name string) (string, bool) {
+	value := c.Param(name)
+
+	if err := uuid.Validate(value); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   name,
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return value, true
+}
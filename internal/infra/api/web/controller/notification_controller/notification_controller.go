@@ -0,0 +1,141 @@
+package notification_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/notification_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationController struct {
+	notificationUseCase notification_usecase.NotificationUseCaseInterface
+}
+
+func NewNotificationController(
+	notificationUseCase notification_usecase.NotificationUseCaseInterface) *NotificationController {
+	return &NotificationController{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+func (u *NotificationController) FindNotifications(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	var findNotificationsInputDTO notification_usecase.FindNotificationsInputDTO
+	if err := c.ShouldBindQuery(&findNotificationsInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	notificationPage, err := u.notificationUseCase.FindNotifications(
+		c.Request.Context(), userId, findNotificationsInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	notificationPage.Links = pagination.Links(c.Request.URL, notificationPage.Page)
+	c.JSON(http.StatusOK, notificationPage)
+}
+
+func (u *NotificationController) MarkAsRead(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	notificationId := c.Param("notificationId")
+	if err := uuid.Validate(notificationId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "notificationId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.notificationUseCase.MarkAsRead(c.Request.Context(), userId, notificationId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *NotificationController) FindPreferences(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	preferencesData, err := u.notificationUseCase.FindPreferences(c.Request.Context(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferencesData)
+}
+
+func (u *NotificationController) UpdatePreferences(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	var preferencesInputDTO notification_usecase.PreferencesInputDTO
+	if err := c.ShouldBindJSON(&preferencesInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	preferencesData, err := u.notificationUseCase.UpdatePreferences(
+		c.Request.Context(), userId, preferencesInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferencesData)
+}
+
+// userIdParam validates the user of the route, who must be the authenticated
+// user or be handled by an admin.
+func userIdParam(c *gin.Context) (string, bool) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	authenticatedId, _ := middleware.UserId(c)
+	if authenticatedId != userId && !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		errRest := rest_err.NewForbiddenError("You can only manage your own notifications")
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
@@ -0,0 +1,90 @@
+package order_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/order_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// FindOrderById only shows the order to its buyer, its seller or an admin.
+func (u *OrderController) FindOrderById(c *gin.Context) {
+	orderId, ok := orderIdParam(c)
+	if !ok {
+		return
+	}
+
+	orderData, err := u.orderUseCase.FindOrderById(c.Request.Context(), orderId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	userId, _ := middleware.UserId(c)
+	if userId != orderData.BuyerId && userId != orderData.SellerId &&
+		!middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		errRest := rest_err.NewForbiddenError("You can only see your own orders")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, orderData)
+}
+
+// FindMyOrders lists the orders of the authenticated user as buyer, or as
+// seller with as=seller.
+func (u *OrderController) FindMyOrders(c *gin.Context) {
+	var findOrdersInputDTO order_usecase.FindOrdersInputDTO
+	if err := c.ShouldBindQuery(&findOrdersInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	userId, _ := middleware.UserId(c)
+	switch c.Query("as") {
+	case "", "buyer":
+		findOrdersInputDTO.BuyerId, findOrdersInputDTO.SellerId = userId, ""
+	case "seller":
+		findOrdersInputDTO.BuyerId, findOrdersInputDTO.SellerId = "", userId
+	default:
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "as",
+			Message: "as must be one of [buyer seller]",
+		})
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	u.findOrders(c, findOrdersInputDTO)
+}
+
+// FindOrders lists every order, optionally filtered by buyer, seller and status.
+func (u *OrderController) FindOrders(c *gin.Context) {
+	var findOrdersInputDTO order_usecase.FindOrdersInputDTO
+	if err := c.ShouldBindQuery(&findOrdersInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	u.findOrders(c, findOrdersInputDTO)
+}
+
+func (u *OrderController) findOrders(c *gin.Context, findOrdersInputDTO order_usecase.FindOrdersInputDTO) {
+	orderPage, err := u.orderUseCase.FindOrders(c.Request.Context(), findOrdersInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	orderPage.Links = pagination.Links(c.Request.URL, orderPage.Page)
+	c.JSON(http.StatusOK, orderPage)
+}
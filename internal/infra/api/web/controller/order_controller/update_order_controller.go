@@ -0,0 +1,108 @@
+package order_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/order_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrderController struct {
+	orderUseCase order_usecase.OrderUseCaseInterface
+}
+
+func NewOrderController(orderUseCase order_usecase.OrderUseCaseInterface) *OrderController {
+	return &OrderController{
+		orderUseCase: orderUseCase,
+	}
+}
+
+func (u *OrderController) PayOrder(c *gin.Context) {
+	orderId, ok := orderIdParam(c)
+	if !ok {
+		return
+	}
+
+	var payOrderInputDTO order_usecase.PayOrderInputDTO
+	if err := c.ShouldBindJSON(&payOrderInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userId, _ := middleware.UserId(c)
+	orderData, err := u.orderUseCase.PayOrder(c.Request.Context(), orderId, userId, payOrderInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, orderData)
+}
+
+func (u *OrderController) ShipOrder(c *gin.Context) {
+	orderId, ok := orderIdParam(c)
+	if !ok {
+		return
+	}
+
+	var shipOrderInputDTO order_usecase.ShipOrderInputDTO
+	if err := c.ShouldBindJSON(&shipOrderInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userId, _ := middleware.UserId(c)
+	orderData, err := u.orderUseCase.ShipOrder(c.Request.Context(), orderId, userId, shipOrderInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, orderData)
+}
+
+func (u *OrderController) CompleteOrder(c *gin.Context) {
+	orderId, ok := orderIdParam(c)
+	if !ok {
+		return
+	}
+
+	userId, _ := middleware.UserId(c)
+	orderData, err := u.orderUseCase.CompleteOrder(c.Request.Context(), orderId, userId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, orderData)
+}
+
+func orderIdParam(c *gin.Context) (string, bool) {
+	orderId := c.Param("orderId")
+
+	if err := uuid.Validate(orderId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "orderId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return orderId, true
+}
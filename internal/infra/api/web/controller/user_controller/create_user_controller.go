@@ -0,0 +1,120 @@
+package user_controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserController struct {
+	userUseCase user_usecase.UserUseCaseInterface
+}
+
+func NewUserController(userUseCase user_usecase.UserUseCaseInterface) *UserController {
+	return &UserController{
+		userUseCase: userUseCase,
+	}
+}
+
+func (u *UserController) CreateUser(c *gin.Context) {
+	var userInputDTO user_usecase.UserInputDTO
+
+	if err := c.ShouldBindJSON(&userInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.CreateUser(c.Request.Context(), userInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/user/%s", userData.Id))
+	c.JSON(http.StatusCreated, userData)
+}
+
+func (u *UserController) UpdateUser(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	authenticatedId, _ := middleware.UserId(c)
+	if authenticatedId != userId && !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		restErr := rest_err.NewForbiddenError("You can only update your own profile")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	var userUpdateInputDTO user_usecase.UserUpdateInputDTO
+	if err := c.ShouldBindJSON(&userUpdateInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.UpdateUser(c.Request.Context(), userId, userUpdateInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
+
+func (u *UserController) UpdateUserRoles(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	var userRolesInputDTO user_usecase.UserRolesInputDTO
+	if err := c.ShouldBindJSON(&userRolesInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.UpdateUserRoles(c.Request.Context(), userId, userRolesInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
+
+func userIdParam(c *gin.Context) (string, bool) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
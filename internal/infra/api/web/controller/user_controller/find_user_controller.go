@@ -1,44 +1,61 @@
 package user_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/user_usecase"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
-type UserController struct {
-	userUseCase user_usecase.UserUseCaseInterface
-}
+// FindUserById shows the email and the roles only to the user and the admins.
+func (u *UserController) FindUserById(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
 
-func NewUserController(userUseCase user_usecase.UserUseCaseInterface) *UserController {
-	return &UserController{
-		userUseCase: userUseCase,
+	userData, err := u.userUseCase.FindUserById(c.Request.Context(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
 	}
-}
 
-func (u *UserController) FindUserById(c *gin.Context) {
-	userId := c.Param("userId")
+	authenticatedId, _ := middleware.UserId(c)
+	if authenticatedId != userId && !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		c.JSON(http.StatusOK, userData.ToPublic())
+		return
+	}
 
-	if err := uuid.Validate(userId); err != nil {
-		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
-			Field:   "userId",
-			Message: "Invalid UUID value",
-		})
+	c.JSON(http.StatusOK, userData)
+}
 
+// FindUsers lists the email and the roles of the users only to the admins.
+func (u *UserController) FindUsers(c *gin.Context) {
+	var findUsersInputDTO user_usecase.FindUsersInputDTO
+	if err := c.ShouldBindQuery(&findUsersInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	userData, err := u.userUseCase.FindUserById(context.Background(), userId)
+	userPage, err := u.userUseCase.FindUsers(c.Request.Context(), findUsersInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	c.JSON(http.StatusOK, userData)
+	userPage.Links = pagination.Links(c.Request.URL, userPage.Page)
+	if !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		c.JSON(http.StatusOK, userPage.ToPublic())
+		return
+	}
+
+	c.JSON(http.StatusOK, userPage)
 }
@@ -0,0 +1,124 @@
+package watchlist_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/watchlist_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WatchlistController struct {
+	watchlistUseCase watchlist_usecase.WatchlistUseCaseInterface
+}
+
+func NewWatchlistController(watchlistUseCase watchlist_usecase.WatchlistUseCaseInterface) *WatchlistController {
+	return &WatchlistController{
+		watchlistUseCase: watchlistUseCase,
+	}
+}
+
+func (u *WatchlistController) WatchAuction(c *gin.Context) {
+	userId, auctionId, ok := watchlistParams(c)
+	if !ok {
+		return
+	}
+
+	watchedData, err := u.watchlistUseCase.WatchAuction(c.Request.Context(), userId, auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, watchedData)
+}
+
+func (u *WatchlistController) UnwatchAuction(c *gin.Context) {
+	userId, auctionId, ok := watchlistParams(c)
+	if !ok {
+		return
+	}
+
+	if err := u.watchlistUseCase.UnwatchAuction(c.Request.Context(), userId, auctionId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *WatchlistController) FindWatchlist(c *gin.Context) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	var findWatchlistInputDTO watchlist_usecase.FindWatchlistInputDTO
+	if err := c.ShouldBindQuery(&findWatchlistInputDTO); err != nil {
+		errRest := validation.ValidateErr(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	watchlistPage, err := u.watchlistUseCase.FindWatchlist(c.Request.Context(), userId, findWatchlistInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	watchlistPage.Links = pagination.Links(c.Request.URL, watchlistPage.Page)
+	c.JSON(http.StatusOK, watchlistPage)
+}
+
+// userIdParam validates the user of the route, who must be the authenticated
+// user or be handled by an admin.
+func userIdParam(c *gin.Context) (string, bool) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	authenticatedId, _ := middleware.UserId(c)
+	if authenticatedId != userId && !middleware.HasRole(c, string(user_entity.RoleAdmin)) {
+		errRest := rest_err.NewForbiddenError("You can only manage your own watchlist")
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
+
+func watchlistParams(c *gin.Context) (string, string, bool) {
+	userId, ok := userIdParam(c)
+	if !ok {
+		return "", "", false
+	}
+
+	auctionId := c.Param("auctionId")
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", "", false
+	}
+
+	return userId, auctionId, true
+}
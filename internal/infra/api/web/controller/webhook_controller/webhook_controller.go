@@ -0,0 +1,163 @@
+package webhook_controller
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/webhook_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookController struct {
+	webhookUseCase webhook_usecase.WebhookUseCaseInterface
+}
+
+func NewWebhookController(webhookUseCase webhook_usecase.WebhookUseCaseInterface) *WebhookController {
+	return &WebhookController{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (u *WebhookController) CreateWebhook(c *gin.Context) {
+	var webhookInputDTO webhook_usecase.WebhookInputDTO
+
+	if err := c.ShouldBindJSON(&webhookInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	webhookData, err := u.webhookUseCase.CreateWebhook(c.Request.Context(), webhookInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	// The response carries the only copy of the secret.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, webhookData)
+}
+
+func (u *WebhookController) FindWebhooks(c *gin.Context) {
+	var findWebhooksInputDTO webhook_usecase.FindWebhooksInputDTO
+	if err := c.ShouldBindQuery(&findWebhooksInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	webhookPage, err := u.webhookUseCase.FindWebhooks(c.Request.Context(), findWebhooksInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	webhookPage.Links = pagination.Links(c.Request.URL, webhookPage.Page)
+	c.JSON(http.StatusOK, webhookPage)
+}
+
+func (u *WebhookController) FindWebhookById(c *gin.Context) {
+	webhookId, ok := uuidParam(c, "webhookId")
+	if !ok {
+		return
+	}
+
+	webhookData, err := u.webhookUseCase.FindWebhookById(c.Request.Context(), webhookId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookData)
+}
+
+func (u *WebhookController) DeleteWebhook(c *gin.Context) {
+	webhookId, ok := uuidParam(c, "webhookId")
+	if !ok {
+		return
+	}
+
+	if err := u.webhookUseCase.DeleteWebhook(c.Request.Context(), webhookId); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *WebhookController) FindDeliveries(c *gin.Context) {
+	webhookId, ok := uuidParam(c, "webhookId")
+	if !ok {
+		return
+	}
+
+	var findDeliveriesInputDTO webhook_usecase.FindDeliveriesInputDTO
+	if err := c.ShouldBindQuery(&findDeliveriesInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	deliveryPage, err := u.webhookUseCase.FindDeliveries(c.Request.Context(), webhookId, findDeliveriesInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	deliveryPage.Links = pagination.Links(c.Request.URL, deliveryPage.Page)
+	c.JSON(http.StatusOK, deliveryPage)
+}
+
+func (u *WebhookController) Redeliver(c *gin.Context) {
+	webhookId, ok := uuidParam(c, "webhookId")
+	if !ok {
+		return
+	}
+
+	deliveryId, ok := uuidParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	deliveryData, err := u.webhookUseCase.Redeliver(c.Request.Context(), webhookId, deliveryId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deliveryData)
+}
+
+func uuidParam(c *gin.Context, name string) (string, bool) {
+	value := c.Param(name)
+
+	if err := uuid.Validate(value); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   name,
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return value, true
+}
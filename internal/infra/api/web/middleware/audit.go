@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIdHeader = "X-Request-Id"
+
+	// maxRequestIdLength bounds the request ids taken from clients.
+	maxRequestIdLength = 100
+)
+
+// redactedFields are never recorded from response bodies, at any depth.
+var redactedFields = map[string]struct{}{
+	"key":      {},
+	"secret":   {},
+	"password": {},
+	"token":    {},
+}
+
+// RequestId takes the request id from the X-Request-Id header, or creates one,
+// echoes it back and keeps it with the client and remote IPs in the request
+// context, where the audit log reads them. The client IP comes from
+// X-Forwarded-For only behind the trusted proxies; the remote IP is always the
+// address the request came from.
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(requestIdHeader)
+		if requestId == "" || len(requestId) > maxRequestIdLength {
+			requestId = uuid.New().String()
+		}
+
+		c.Header(requestIdHeader, requestId)
+		c.Request = c.Request.WithContext(audit_entity.WithRequest(c.Request.Context(), audit_entity.RequestInfo{
+			RequestId: requestId,
+			ClientIP:  c.ClientIP(),
+			RemoteIP:  c.RemoteIP(),
+		}))
+
+		c.Next()
+	}
+}
+
+// SnapshotFunc returns the current state of a target, recorded before and
+// after each change to it.
+type SnapshotFunc func(ctx context.Context, targetId string) (any, *internal_error.InternalError)
+
+// Audit records the successful writes of the routes it wraps.
+type Audit struct {
+	recorder  audit_entity.RecorderInterface
+	snapshots map[string]SnapshotFunc
+}
+
+func NewAudit(recorder audit_entity.RecorderInterface) *Audit {
+	return &Audit{
+		recorder:  recorder,
+		snapshots: map[string]SnapshotFunc{},
+	}
+}
+
+// Snapshot registers how to read targets of targetType. Targets without one are
+// recorded with the response body as their state after the change.
+func (a *Audit) Snapshot(targetType string, snapshot SnapshotFunc) {
+	a.snapshots[targetType] = snapshot
+}
+
+// Record must run after the Auth middleware. The target is named by the param
+// route parameter or, for creations, by the id in the response body. Only
+// requests answered with a 2xx status are recorded.
+func (a *Audit) Record(action, targetType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request, _ := audit_entity.RequestFrom(c.Request.Context())
+		if request.ClientIP == "" {
+			request.ClientIP = c.ClientIP()
+			request.RemoteIP = c.RemoteIP()
+		}
+		request.Actor = requestActor(c)
+		ctx := audit_entity.WithRequest(c.Request.Context(), request)
+		c.Request = c.Request.WithContext(ctx)
+
+		targetId := ""
+		if param != "" {
+			targetId = c.Param(param)
+		}
+		snapshot := a.snapshots[targetType]
+
+		var before any
+		if snapshot != nil && targetId != "" {
+			before = takeSnapshot(ctx, snapshot, targetType, targetId)
+		}
+
+		writer := &bodyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if status < 200 || status >= 300 {
+			return
+		}
+
+		body := writer.body.Bytes()
+		if targetId == "" {
+			targetId = responseId(body)
+		}
+
+		var after any
+		if snapshot != nil && targetId != "" {
+			after = takeSnapshot(ctx, snapshot, targetType, targetId)
+		} else if len(body) > 0 {
+			after = redact(body)
+		}
+
+		entry := audit_entity.NewEntry(ctx, action, targetType, targetId, before, after)
+		entry.Status = status
+		a.recorder.Record(ctx, entry)
+	}
+}
+
+func requestActor(c *gin.Context) audit_entity.Actor {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return audit_entity.Actor{Type: audit_entity.ActorAnonymous}
+	}
+
+	if claims.ApiKeyId != "" {
+		return audit_entity.Actor{Type: audit_entity.ActorApiKey, UserId: claims.UserId, ApiKeyId: claims.ApiKeyId}
+	}
+
+	return audit_entity.Actor{Type: audit_entity.ActorUser, UserId: claims.UserId}
+}
+
+// takeSnapshot returns nil when the target cannot be read, as after a removal.
+func takeSnapshot(ctx context.Context, snapshot SnapshotFunc, targetType, targetId string) any {
+	state, err := snapshot(ctx, targetId)
+	if err != nil {
+		if err.Err != "not_found" {
+			logger.Error(fmt.Sprintf("Error trying to snapshot %s %s", targetType, targetId), err)
+		}
+		return nil
+	}
+
+	return state
+}
+
+func responseId(body []byte) string {
+	var response struct {
+		Id string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+
+	return response.Id
+}
+
+// redact drops the secrets of a JSON body. Bodies that are not JSON are not
+// recorded.
+func redact(body []byte) any {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+
+	return redactValue(value)
+}
+
+func redactValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for field, nested := range value {
+			if _, ok := redactedFields[field]; ok {
+				delete(value, field)
+				continue
+			}
+			value[field] = redactValue(nested)
+		}
+	case []any:
+		for i, nested := range value {
+			value[i] = redactValue(nested)
+		}
+	}
+
+	return value
+}
+
+// bodyWriter keeps a copy of the response body.
+type bodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRecorder struct {
+	entries []audit_entity.Entry
+}
+
+func (r *memoryRecorder) Record(_ context.Context, entry audit_entity.Entry) {
+	r.entries = append(r.entries, entry)
+}
+
+func newAuditedRouter(recorder *memoryRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	status := "active"
+	audit := NewAudit(recorder)
+	audit.Snapshot("auction", func(_ context.Context, auctionId string) (any, *internal_error.InternalError) {
+		if auctionId != "auction-1" {
+			return nil, internal_error.NewNotFoundError("Auction not found")
+		}
+		return map[string]string{"id": auctionId, "status": status}, nil
+	})
+	auth := NewAuth(stubAuthUseCase{})
+
+	r := gin.New()
+	r.Use(RequestId())
+	r.POST("/auction/:auctionId/pause", auth.Required(), audit.Record("auction.pause", "auction", "auctionId"),
+		func(c *gin.Context) {
+			if c.Param("auctionId") != "auction-1" {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			status = "paused"
+			c.Status(http.StatusOK)
+		})
+	r.POST("/api-key", auth.Required(), audit.Record("api_key.issue", "api_key", ""), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": "key-1", "key": "ak_secret", "scopes": []string{"bid"}})
+	})
+
+	return r
+}
+
+func TestAuditRecord(t *testing.T) {
+	send := func(r *gin.Engine, url string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, nil)
+		req.Header = header
+		req.RemoteAddr = "203.0.113.7:4000"
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("should record the actor, the request and both snapshots", func(t *testing.T) {
+		recorder := &memoryRecorder{}
+		r := newAuditedRouter(recorder)
+
+		resp := send(r, "/auction/auction-1/pause", http.Header{
+			"Authorization": {"Bearer valid"},
+			"X-Request-Id":  {"request-1"},
+		})
+
+		assert.Equal(t, "request-1", resp.Header().Get("X-Request-Id"))
+		require.Len(t, recorder.entries, 1)
+		entry := recorder.entries[0]
+		assert.Equal(t, audit_entity.Actor{Type: audit_entity.ActorUser, UserId: "user-1"}, entry.Actor)
+		assert.Equal(t, "auction-1", entry.TargetId)
+		assert.Equal(t, "request-1", entry.RequestId)
+		assert.Equal(t, "203.0.113.7", entry.ClientIP)
+		assert.Equal(t, "203.0.113.7", entry.RemoteIP)
+		assert.Equal(t, http.StatusOK, entry.Status)
+		assert.JSONEq(t, `{"id":"auction-1","status":"active"}`, entry.Before)
+		assert.JSONEq(t, `{"id":"auction-1","status":"paused"}`, entry.After)
+	})
+
+	t.Run("should record the remote IP next to a forwarded client IP", func(t *testing.T) {
+		recorder := &memoryRecorder{}
+		r := newAuditedRouter(recorder)
+
+		send(r, "/auction/auction-1/pause", http.Header{
+			"Authorization":   {"Bearer valid"},
+			"X-Forwarded-For": {"198.51.100.9"},
+		})
+
+		require.Len(t, recorder.entries, 1)
+		assert.Equal(t, "198.51.100.9", recorder.entries[0].ClientIP)
+		assert.Equal(t, "203.0.113.7", recorder.entries[0].RemoteIP)
+	})
+
+	t.Run("should not record failed requests", func(t *testing.T) {
+		recorder := &memoryRecorder{}
+		r := newAuditedRouter(recorder)
+
+		resp := send(r, "/auction/auction-2/pause", http.Header{"Authorization": {"Bearer valid"}})
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.NotEmpty(t, resp.Header().Get("X-Request-Id"))
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("should take the created target from the response without its secrets", func(t *testing.T) {
+		recorder := &memoryRecorder{}
+		r := newAuditedRouter(recorder)
+
+		send(r, "/api-key", http.Header{"X-Api-Key": {"ak_valid"}})
+
+		require.Len(t, recorder.entries, 0, "API keys are not accepted on routes without a scope")
+
+		send(r, "/api-key", http.Header{"Authorization": {"Bearer valid"}})
+
+		require.Len(t, recorder.entries, 1)
+		entry := recorder.entries[0]
+		assert.Equal(t, "key-1", entry.TargetId)
+		assert.Empty(t, entry.Before)
+		assert.JSONEq(t, `{"id":"key-1","scopes":["bid"]}`, entry.After)
+	})
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auth_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auth_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	claimsKey    = "auth.claims"
+	apiKeyHeader = "X-API-Key"
+)
+
+type Auth struct {
+	authUseCase auth_usecase.AuthUseCaseInterface
+}
+
+func NewAuth(authUseCase auth_usecase.AuthUseCaseInterface) *Auth {
+	return &Auth{
+		authUseCase: authUseCase,
+	}
+}
+
+// Required rejects requests without a valid bearer token. API keys (X-API-Key)
+// are only accepted when the route names the scope they need.
+func (a *Auth) Required(apiKeyScope ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasCredentials(c) {
+			abortUnauthorized(c, rest_err.NewUnauthorizedError("Missing bearer token"))
+			return
+		}
+
+		a.authenticate(c, apiKeyScope)
+	}
+}
+
+// Optional lets anonymous requests through but still rejects invalid
+// credentials, so a client never gets anonymous results by mistake.
+func (a *Auth) Optional(apiKeyScope ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasCredentials(c) {
+			c.Next()
+			return
+		}
+
+		a.authenticate(c, apiKeyScope)
+	}
+}
+
+// RequireRole must run after Required and rejects users without any of roles.
+func (a *Auth) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := requestClaims(c)
+		if !ok {
+			abortUnauthorized(c, rest_err.NewUnauthorizedError("Missing bearer token"))
+			return
+		}
+
+		if !claims.HasAnyRole(roles...) {
+			restErr := rest_err.NewForbiddenError(
+				fmt.Sprintf("This action requires one of the roles: %s", strings.Join(roles, ", ")))
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (a *Auth) authenticate(c *gin.Context, apiKeyScope []string) {
+	if key := strings.TrimSpace(c.GetHeader(apiKeyHeader)); key != "" {
+		a.authenticateApiKey(c, key, apiKeyScope)
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		abortUnauthorized(c, rest_err.NewUnauthorizedError("Missing bearer token"))
+		return
+	}
+
+	claims, err := a.authUseCase.Authenticate(token)
+	if err != nil {
+		abortUnauthorized(c, rest_err.ConvertError(err))
+		return
+	}
+
+	c.Set(claimsKey, claims)
+	c.Next()
+}
+
+func (a *Auth) authenticateApiKey(c *gin.Context, key string, apiKeyScope []string) {
+	claims, err := a.authUseCase.AuthenticateApiKey(c.Request.Context(), key)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+		if err.Err == "unauthorized" {
+			abortUnauthorized(c, restErr)
+			return
+		}
+		c.AbortWithStatusJSON(restErr.Code, restErr)
+		return
+	}
+
+	if len(apiKeyScope) == 0 {
+		restErr := rest_err.NewForbiddenError("API keys cannot be used on this route")
+		c.AbortWithStatusJSON(restErr.Code, restErr)
+		return
+	}
+
+	for _, scope := range apiKeyScope {
+		if !claims.HasScope(scope) {
+			restErr := rest_err.NewForbiddenError(fmt.Sprintf("API key is missing the %s scope", scope))
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+	}
+
+	c.Set(claimsKey, claims)
+	c.Next()
+}
+
+// UserId returns the authenticated user of the request, if any.
+func UserId(c *gin.Context) (string, bool) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return "", false
+	}
+
+	return claims.UserId, true
+}
+
+// HasRole reports whether the authenticated user has role.
+func HasRole(c *gin.Context, role string) bool {
+	claims, ok := requestClaims(c)
+	return ok && claims.HasAnyRole(role)
+}
+
+func requestClaims(c *gin.Context) (*auth_entity.Claims, bool) {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := value.(*auth_entity.Claims)
+	return claims, ok
+}
+
+func hasCredentials(c *gin.Context) bool {
+	if c.GetHeader(apiKeyHeader) != "" {
+		return true
+	}
+
+	_, ok := bearerToken(c)
+	return ok
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+func abortUnauthorized(c *gin.Context, restErr *rest_err.RestErr) {
+	c.Header("WWW-Authenticate", `Bearer realm="auction"`)
+	c.AbortWithStatusJSON(restErr.Code, restErr)
+}
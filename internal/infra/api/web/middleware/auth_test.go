@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auth_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auth_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthUseCase struct{}
+
+func (stubAuthUseCase) Login(
+	context.Context, auth_usecase.LoginInputDTO) (*auth_usecase.TokenOutputDTO, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func (stubAuthUseCase) Authenticate(token string) (*auth_entity.Claims, *internal_error.InternalError) {
+	if token != "valid" {
+		return nil, internal_error.NewUnauthorizedError("Invalid or expired token")
+	}
+
+	return &auth_entity.Claims{UserId: "user-1", Roles: []string{"bidder"}}, nil
+}
+
+func (stubAuthUseCase) AuthenticateApiKey(
+	_ context.Context, key string) (*auth_entity.Claims, *internal_error.InternalError) {
+	if key != "ak_valid" {
+		return nil, internal_error.NewUnauthorizedError("Invalid, expired or revoked API key")
+	}
+
+	return &auth_entity.Claims{
+		UserId:   "user-2",
+		Roles:    []string{"bidder"},
+		ApiKeyId: "key-1",
+		Scopes:   []string{"read", "bid"},
+	}, nil
+}
+
+func newTestRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/", handler, func(c *gin.Context) {
+		userId, _ := UserId(c)
+		c.String(http.StatusOK, userId)
+	})
+
+	return r
+}
+
+func doRequest(r *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRequired(t *testing.T) {
+	r := newTestRouter(NewAuth(stubAuthUseCase{}).Required())
+
+	resp := doRequest(r, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("WWW-Authenticate"))
+
+	resp = doRequest(r, "Bearer invalid")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = doRequest(r, "bearer valid")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "user-1", resp.Body.String())
+}
+
+func TestOptional(t *testing.T) {
+	r := newTestRouter(NewAuth(stubAuthUseCase{}).Optional())
+
+	resp := doRequest(r, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	resp = doRequest(r, "Bearer invalid")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = doRequest(r, "Bearer valid")
+	assert.Equal(t, "user-1", resp.Body.String())
+}
+
+func TestRequireRole(t *testing.T) {
+	auth := NewAuth(stubAuthUseCase{})
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/bid", auth.Required(), auth.RequireRole("bidder"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/admin", auth.Required(), auth.RequireRole("admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/bid", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestApiKey(t *testing.T) {
+	auth := NewAuth(stubAuthUseCase{})
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	handler := func(c *gin.Context) {
+		userId, _ := UserId(c)
+		c.String(http.StatusOK, userId)
+	}
+	r.GET("/read", auth.Optional("read"), handler)
+	r.POST("/bid", auth.Required("bid"), handler)
+	r.POST("/auction", auth.Required("create-auction"), handler)
+	r.PATCH("/user", auth.Required(), handler)
+
+	send := func(method, url, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set("X-API-Key", key)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	resp := send(http.MethodGet, "/read", "ak_valid")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "user-2", resp.Body.String())
+
+	resp = send(http.MethodPost, "/bid", "ak_valid")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = send(http.MethodPost, "/bid", "ak_revoked")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = send(http.MethodPost, "/auction", "ak_valid")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = send(http.MethodPatch, "/user", "ak_valid")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
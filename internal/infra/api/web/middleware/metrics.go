@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels the requests to unknown paths, which would otherwise
+// add a label value per path.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the rate and the latency of the requests per route pattern.
+func Metrics(metrics metrics_entity.MetricsInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type requestMetrics struct {
+	metrics_entity.Discard
+
+	requests []observedRequest
+}
+
+func (m *requestMetrics) ObserveRequest(method, route string, status int, _ time.Duration) {
+	m.requests = append(m.requests, observedRequest{method: method, route: route, status: status})
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorded := &requestMetrics{}
+	r := gin.New()
+	r.Use(Metrics(recorded))
+	r.GET("/auction/:auctionId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, url := range []string{"/auction/1", "/auction/2", "/unknown/path"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	assert.Equal(t, []observedRequest{
+		{method: http.MethodGet, route: "/auction/:auctionId", status: http.StatusOK},
+		{method: http.MethodGet, route: "/auction/:auctionId", status: http.StatusOK},
+		{method: http.MethodGet, route: unmatchedRoute, status: http.StatusNotFound},
+	}, recorded.requests)
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// Route groups with their own rate limit budget.
+const (
+	RateLimitRead = "read"
+	RateLimitBid  = "bid"
+	RateLimitAuth = "auth"
+)
+
+// RateLimiter applies a token bucket per client and route group. The client is
+// the API key or user of the request claims, or the client IP for anonymous
+// requests, so it must run after the Auth middleware. The client IP is only
+// read from X-Forwarded-For behind the proxies trusted by the router.
+type RateLimiter struct {
+	store  ratelimit.Store
+	limits map[string]ratelimit.Limit
+	now    func() time.Time
+}
+
+func NewRateLimiter(store ratelimit.Store, limits map[string]ratelimit.Limit) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		limits: limits,
+		now:    time.Now,
+	}
+}
+
+// Limit takes a token from the group budget of the client and answers 429 when
+// it is exhausted. Groups without an enabled limit are not limited.
+func (rl *RateLimiter) Limit(group string) gin.HandlerFunc {
+	limit := rl.limits[group]
+	if !limit.Enabled() {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		result, err := rl.store.Take(c.Request.Context(), group+":"+clientKey(c), limit, rl.now())
+		if err != nil {
+			// A store outage must not take the API down with it.
+			logger.Error("Error trying to take a rate limit token", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			restErr := rest_err.NewTooManyRequestsError("Too many requests, try again later")
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	claims, ok := requestClaims(c)
+	switch {
+	case ok && claims.ApiKeyId != "":
+		return "key:" + claims.ApiKeyId
+	case ok:
+		return "user:" + claims.UserId
+	default:
+		return "ip:" + c.ClientIP()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
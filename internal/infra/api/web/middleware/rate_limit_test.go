@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func newRateLimitedRouter(store ratelimit.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(store, map[string]ratelimit.Limit{
+		RateLimitBid: {Rate: 1, Burst: 2},
+	})
+	auth := NewAuth(stubAuthUseCase{})
+
+	r := gin.New()
+	r.POST("/bid", auth.Optional(), limiter.Limit(RateLimitBid), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/auction", limiter.Limit(RateLimitRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return r
+}
+
+func TestRateLimit(t *testing.T) {
+	r := newRateLimitedRouter(ratelimit.NewMemoryStore())
+
+	send := func(method, url, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	resp := send(http.MethodPost, "/bid", "Bearer valid")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header().Get("RateLimit-Remaining"))
+
+	send(http.MethodPost, "/bid", "Bearer valid")
+	resp = send(http.MethodPost, "/bid", "Bearer valid")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+
+	// Anonymous clients are limited by IP, apart from the user budget.
+	resp = send(http.MethodPost, "/bid", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// Groups without a limit are not limited.
+	for range 5 {
+		resp = send(http.MethodGet, "/auction", "")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	r := newRateLimitedRouter(failingStore{})
+
+	req := httptest.NewRequest(http.MethodPost, "/bid", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	send := func(r *gin.Engine, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/bid", nil)
+		req.RemoteAddr = "10.0.0.2:4321"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Run("should ignore X-Forwarded-For from untrusted clients", func(t *testing.T) {
+		r := newRateLimitedRouter(ratelimit.NewMemoryStore())
+		assert.NoError(t, r.SetTrustedProxies(nil))
+
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, send(r, "203.0.113.3"))
+	})
+
+	t.Run("should limit each forwarded client behind a trusted proxy", func(t *testing.T) {
+		r := newRateLimitedRouter(ratelimit.NewMemoryStore())
+		assert.NoError(t, r.SetTrustedProxies([]string{"10.0.0.0/8"}))
+
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, send(r, "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.2"))
+	})
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled by the infrastructure and would flood the traces.
+var untracedPaths = map[string]bool{"/metrics": true, "/healthz": true, "/readyz": true}
+
+// Tracing starts the server span of each request, continuing the trace of the
+// caller when it sends a traceparent header. Metrics scrapes and health
+// probes are not traced.
+func Tracing() gin.HandlerFunc {
+	return otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
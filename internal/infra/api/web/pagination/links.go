@@ -0,0 +1,25 @@
+package pagination
+
+import (
+	"net/url"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+// Links builds the paging links of a listing from the request URL, keeping
+// every filter and replacing only the cursor for the next page.
+func Links(requestURL *url.URL, page pagination_usecase.PageOutputDTO) pagination_usecase.LinksOutputDTO {
+	links := pagination_usecase.LinksOutputDTO{
+		Self: requestURL.RequestURI(),
+	}
+
+	if page.HasMore && page.NextCursor != "" {
+		next := *requestURL
+		query := next.Query()
+		query.Set("cursor", page.NextCursor)
+		next.RawQuery = query.Encode()
+		links.Next = next.RequestURI()
+	}
+
+	return links
+}
@@ -1,25 +1,117 @@
 package router
 
 import (
-	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
-	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
-	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/api_key_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/dependencies"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(
-	router *gin.Engine,
-	userController *user_controller.UserController,
-	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController,
-) {
-	router.GET("/auction", auctionController.FindAuctions)
-	router.GET("/auction/:auctionId", auctionController.FindAuctionById)
-	router.POST("/auction", auctionController.CreateAuction)
-	router.GET("/auction/winner/:auctionId", auctionController.FindWinningBidByAuctionId)
-
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
-
-	router.GET("/user/:userId", userController.FindUserById)
+// RegisterRoutes wires the HTTP routes. Reads stay public (a token, when sent,
+// is still validated), every write requires an authenticated user and the
+// routes under /admin require the admin role. API keys are only accepted on the
+// routes that name the scope they need. Reads and bids are rate limited per
+// client, each with its own budget, and so are logins, per client IP. Every
+// write is recorded in the audit log. Requests are traced and measured.
+func RegisterRoutes(router *gin.Engine, deps *dependencies.Dependencies) {
+	auctionController := deps.AuctionController
+	bidController := deps.BidController
+	userController := deps.UserController
+	categoryController := deps.CategoryController
+	audit := deps.Audit
+
+	router.Use(middleware.Tracing(), middleware.RequestId(), middleware.Metrics(deps.Metrics))
+
+	router.GET("/metrics", gin.WrapH(deps.MetricsHandler))
+	router.GET("/healthz", deps.HealthController.Liveness)
+	router.GET("/readyz", deps.HealthController.Readiness)
+
+	router.POST("/auth/token", deps.RateLimit.Limit(middleware.RateLimitAuth), deps.AuthController.Login)
+
+	public := router.Group("",
+		deps.Auth.Optional(string(api_key_entity.ScopeRead)), deps.RateLimit.Limit(middleware.RateLimitRead))
+	private := router.Group("", deps.Auth.Required())
+	adminRole := deps.Auth.RequireRole(string(user_entity.RoleAdmin))
+	admin := router.Group("/admin", deps.Auth.Required(), adminRole)
+	seller := deps.Auth.RequireRole(string(user_entity.RoleSeller))
+	bidder := deps.Auth.RequireRole(string(user_entity.RoleBidder))
+
+	public.GET("/auction", auctionController.FindAuctions)
+	public.GET("/auction/search", auctionController.SearchAuctions)
+	public.GET("/auction/:auctionId", auctionController.FindAuctionById)
+	public.GET("/auction/:auctionId/stream", auctionController.StreamAuctionEvents)
+	router.POST("/auction", deps.Auth.Required(string(api_key_entity.ScopeCreateAuction)), seller,
+		audit.Record("auction.create", "auction", ""), auctionController.CreateAuction)
+	public.GET("/auction/winner/:auctionId", auctionController.FindWinningBidByAuctionId)
+
+	router.POST("/bid", deps.Auth.Required(string(api_key_entity.ScopeBid)),
+		deps.RateLimit.Limit(middleware.RateLimitBid), bidder, audit.Record("bid.create", "bid", ""), bidController.CreateBid)
+	public.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+
+	public.GET("/user", userController.FindUsers)
+	public.GET("/user/:userId", userController.FindUserById)
+	router.POST("/user", audit.Record("user.create", "user", ""), userController.CreateUser)
+	private.PATCH("/user/:userId", audit.Record("user.update", "user", "userId"), userController.UpdateUser)
+	private.GET("/user/:userId/credit", deps.CreditController.FindCreditAccount)
+	private.GET("/user/:userId/watchlist", deps.WatchlistController.FindWatchlist)
+	private.POST("/user/:userId/watchlist/:auctionId",
+		audit.Record("watchlist.add", "watchlist", "auctionId"), deps.WatchlistController.WatchAuction)
+	private.DELETE("/user/:userId/watchlist/:auctionId",
+		audit.Record("watchlist.remove", "watchlist", "auctionId"), deps.WatchlistController.UnwatchAuction)
+	private.GET("/user/:userId/notifications", deps.NotificationController.FindNotifications)
+	private.POST("/user/:userId/notifications/:notificationId/read",
+		audit.Record("notification.read", "notification", "notificationId"), deps.NotificationController.MarkAsRead)
+	private.GET("/user/:userId/notification-preferences", deps.NotificationController.FindPreferences)
+	private.PUT("/user/:userId/notification-preferences",
+		audit.Record("notification_preferences.update", "notification_preferences", "userId"),
+		deps.NotificationController.UpdatePreferences)
+
+	private.GET("/order", deps.OrderController.FindMyOrders)
+	private.GET("/order/:orderId", deps.OrderController.FindOrderById)
+	private.POST("/order/:orderId/pay", audit.Record("order.pay", "order", "orderId"), deps.OrderController.PayOrder)
+	private.POST("/order/:orderId/ship", audit.Record("order.ship", "order", "orderId"), deps.OrderController.ShipOrder)
+	private.POST("/order/:orderId/complete",
+		audit.Record("order.complete", "order", "orderId"), deps.OrderController.CompleteOrder)
+
+	public.GET("/category", categoryController.FindCategories)
+	public.GET("/category/:categoryId", categoryController.FindCategoryById)
+	private.POST("/category", adminRole,
+		audit.Record("category.create", "category", ""), categoryController.CreateCategory)
+	private.PATCH("/category/:categoryId", adminRole,
+		audit.Record("category.update", "category", "categoryId"), categoryController.UpdateCategory)
+	private.DELETE("/category/:categoryId", adminRole,
+		audit.Record("category.delete", "category", "categoryId"), categoryController.DeleteCategory)
+
+	admin.POST("/auction/:auctionId/pause", audit.Record("auction.pause", "auction", "auctionId"),
+		auctionController.ChangeAuctionStatus(auction_entity.ActionPause))
+	admin.POST("/auction/:auctionId/resume", audit.Record("auction.resume", "auction", "auctionId"),
+		auctionController.ChangeAuctionStatus(auction_entity.ActionResume))
+	admin.POST("/auction/:auctionId/cancel", audit.Record("auction.cancel", "auction", "auctionId"),
+		auctionController.ChangeAuctionStatus(auction_entity.ActionCancel))
+	admin.POST("/auction/:auctionId/close", audit.Record("auction.close", "auction", "auctionId"),
+		auctionController.ChangeAuctionStatus(auction_entity.ActionClose))
+	admin.POST("/auction/:auctionId/extend",
+		audit.Record("auction.extend", "auction", "auctionId"), deps.LedgerController.ExtendAuction)
+	admin.GET("/auction/:auctionId/ledger", deps.LedgerController.FindLedger)
+	admin.POST("/auction/:auctionId/replay",
+		audit.Record("auction.replay", "auction", "auctionId"), deps.LedgerController.ReplayAuction)
+	admin.GET("/bid/dead-letter", bidController.FindDeadLetterBids)
+	admin.POST("/bid/:bidId/retract", audit.Record("bid.retract", "bid", "bidId"), deps.LedgerController.RetractBid)
+	admin.GET("/order", deps.OrderController.FindOrders)
+	admin.PUT("/user/:userId/roles", audit.Record("user.roles.update", "user", "userId"), userController.UpdateUserRoles)
+	admin.PUT("/user/:userId/credit", audit.Record("credit.limit.set", "credit", "userId"), deps.CreditController.SetCreditLimit)
+	admin.POST("/api-key", audit.Record("api_key.issue", "api_key", ""), deps.ApiKeyController.IssueApiKey)
+	admin.GET("/api-key", deps.ApiKeyController.FindApiKeys)
+	admin.DELETE("/api-key/:apiKeyId", audit.Record("api_key.revoke", "api_key", "apiKeyId"), deps.ApiKeyController.RevokeApiKey)
+	admin.POST("/webhook", audit.Record("webhook.create", "webhook", ""), deps.WebhookController.CreateWebhook)
+	admin.GET("/webhook", deps.WebhookController.FindWebhooks)
+	admin.GET("/webhook/:webhookId", deps.WebhookController.FindWebhookById)
+	admin.DELETE("/webhook/:webhookId", audit.Record("webhook.delete", "webhook", "webhookId"), deps.WebhookController.DeleteWebhook)
+	admin.GET("/webhook/:webhookId/delivery", deps.WebhookController.FindDeliveries)
+	admin.POST("/webhook/:webhookId/delivery/:deliveryId/redeliver",
+		audit.Record("webhook.redeliver", "webhook_delivery", "deliveryId"), deps.WebhookController.Redeliver)
+	admin.GET("/audit", deps.AuditController.FindEntries)
+	admin.GET("/audit/verify", deps.AuditController.VerifyChain)
 }
@@ -3,8 +3,11 @@ package validation
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
@@ -23,6 +26,78 @@ func init() {
 		enTransl := ut.New(en, en)
 		transl, _ = enTransl.GetTranslator("en")
 		validator_en.RegisterDefaultTranslations(value, transl)
+		registerComparisonValidations(value)
+		registerCurrencyValidation(value)
+	}
+}
+
+// registerCurrencyValidation adds the currency tag, accepting the ISO 4217 codes
+// the money type knows the minor units of.
+func registerCurrencyValidation(validate *validator.Validate) {
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		_, err := money_entity.ParseCurrency(fl.Field().String())
+		return err == nil
+	})
+
+	validate.RegisterTranslation("currency", transl, func(ut ut.Translator) error {
+		return ut.Add("currency", "{0} must be a supported ISO 4217 currency code", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		message, _ := ut.T("currency", fe.Field())
+		return message
+	})
+}
+
+// registerComparisonValidations adds tags comparing two text fields by their
+// numeric or RFC3339 value, used by query DTOs that keep every value as text.
+// The comparison is skipped when either side is empty or not parseable, which
+// is already reported by the numeric/datetime tags of the fields.
+func registerComparisonValidations(validate *validator.Validate) {
+	comparisons := map[string]struct {
+		translation string
+		parse       func(string) (float64, error)
+	}{
+		"numeric_gtefield": {
+			translation: "{0} must be greater than or equal to {1}",
+			parse: func(value string) (float64, error) {
+				return strconv.ParseFloat(value, 64)
+			},
+		},
+		"datetime_gtefield": {
+			translation: "{0} must be later than or equal to {1}",
+			parse: func(value string) (float64, error) {
+				parsed, err := time.Parse(time.RFC3339, value)
+				return float64(parsed.UnixNano()), err
+			},
+		},
+	}
+
+	for tag, comparison := range comparisons {
+		parse := comparison.parse
+		validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			other, _, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+			if !ok || fl.Field().String() == "" || other.String() == "" {
+				return true
+			}
+
+			current, err := parse(fl.Field().String())
+			if err != nil {
+				return true
+			}
+			reference, err := parse(other.String())
+			if err != nil {
+				return true
+			}
+
+			return current >= reference
+		})
+
+		tagName, translation := tag, comparison.translation
+		validate.RegisterTranslation(tagName, transl, func(ut ut.Translator) error {
+			return ut.Add(tagName, translation, true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			message, _ := ut.T(tagName, fe.Field(), fe.Param())
+			return message
+		})
 	}
 }
 
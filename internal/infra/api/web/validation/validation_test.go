@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bindAuctionQuery(t *testing.T, rawQuery string) (auction_usecase.FindAuctionsInputDTO, error) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/auction?"+rawQuery, nil)
+
+	var input auction_usecase.FindAuctionsInputDTO
+	err := c.ShouldBindQuery(&input)
+	return input, err
+}
+
+func TestValidateErrForQueryFilters(t *testing.T) {
+	t.Run("should accept repeated values and valid ranges", func(t *testing.T) {
+		input, err := bindAuctionQuery(t,
+			"category=a&category=b&condition=1&condition=3&minPrice=10&maxPrice=20.5"+
+				"&createdFrom=2025-01-01T00:00:00Z&createdTo=2025-02-01T00:00:00Z")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, input.Category)
+		assert.Equal(t, []string{"1", "3"}, input.Condition)
+	})
+
+	t.Run("should report every invalid value with its field", func(t *testing.T) {
+		_, err := bindAuctionQuery(t, "condition=9&minPrice=abc&createdFrom=yesterday")
+		require.Error(t, err)
+
+		restErr := ValidateErr(err)
+		assert.Equal(t, http.StatusBadRequest, restErr.Code)
+		assert.ElementsMatch(t, []string{"Condition[0]", "MinPrice", "CreatedFrom"}, causeFields(restErr))
+	})
+
+	t.Run("should report inverted ranges on the upper bound field", func(t *testing.T) {
+		_, err := bindAuctionQuery(t,
+			"minPrice=30&maxPrice=20&endingAfter=2025-02-01T00:00:00Z&endingBefore=2025-01-01T00:00:00Z")
+		require.Error(t, err)
+
+		restErr := ValidateErr(err)
+		assert.ElementsMatch(t, []string{"MaxPrice", "EndingBefore"}, causeFields(restErr))
+		for _, cause := range restErr.Causes {
+			if cause.Field == "MaxPrice" {
+				assert.Equal(t, "MaxPrice must be greater than or equal to MinPrice", cause.Message)
+			}
+		}
+	})
+}
+
+func TestCurrencyValidation(t *testing.T) {
+	t.Run("should accept supported currencies in any case", func(t *testing.T) {
+		input, err := bindAuctionQuery(t, "currency=usd&minPrice=10.50")
+
+		require.NoError(t, err)
+		assert.Equal(t, "usd", input.Currency)
+	})
+
+	t.Run("should reject unknown currencies", func(t *testing.T) {
+		_, err := bindAuctionQuery(t, "currency=XYZ")
+		require.Error(t, err)
+
+		restErr := ValidateErr(err)
+		require.Len(t, restErr.Causes, 1)
+		assert.Equal(t, "Currency", restErr.Causes[0].Field)
+		assert.Equal(t, "Currency must be a supported ISO 4217 currency code", restErr.Causes[0].Message)
+	})
+}
+
+func causeFields(restErr *rest_err.RestErr) []string {
+	var fields []string
+	for _, cause := range restErr.Causes {
+		fields = append(fields, cause.Field)
+	}
+
+	return fields
+}
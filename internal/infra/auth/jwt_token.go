@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auth_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultTokenTTL = time.Hour
+	minSecretLength = 32
+	tokenIssuer     = "fullcycle-auction"
+)
+
+// tokenClaims adds the user roles to the registered claims, so authorization
+// does not need a database lookup. Role changes apply to the next token.
+type tokenClaims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// JWTTokenService signs and validates HS256 tokens with a locally configured key.
+type JWTTokenService struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewJWTTokenService(secret []byte, ttl time.Duration) (*JWTTokenService, error) {
+	if len(secret) < minSecretLength {
+		return nil, errors.New("JWT_SECRET must have at least 32 bytes")
+	}
+
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+
+	return &JWTTokenService{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}, nil
+}
+
+// NewJWTTokenServiceFromEnv reads JWT_SECRET and JWT_TTL (default 1h).
+func NewJWTTokenServiceFromEnv() (*JWTTokenService, error) {
+	ttl, err := time.ParseDuration(os.Getenv("JWT_TTL"))
+	if err != nil {
+		ttl = defaultTokenTTL
+	}
+
+	return NewJWTTokenService([]byte(os.Getenv("JWT_SECRET")), ttl)
+}
+
+func (s *JWTTokenService) IssueToken(
+	userId string, roles []string) (string, *auth_entity.Claims, *internal_error.InternalError) {
+	issuedAt := s.now().Truncate(time.Second)
+	expiresAt := issuedAt.Add(s.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   userId,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		logger.Error("Error trying to sign token", err)
+		return "", nil, internal_error.NewInternalServerError("Error trying to sign token")
+	}
+
+	return signed, &auth_entity.Claims{
+		UserId:    userId,
+		Roles:     roles,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *JWTTokenService) ParseToken(token string) (*auth_entity.Claims, *internal_error.InternalError) {
+	var claims tokenClaims
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now))
+	if err != nil || claims.Subject == "" {
+		return nil, internal_error.NewUnauthorizedError("Invalid or expired token")
+	}
+
+	parsed := &auth_entity.Claims{
+		UserId:    claims.Subject,
+		Roles:     claims.Roles,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		parsed.IssuedAt = claims.IssuedAt.Time
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestNewJWTTokenService(t *testing.T) {
+	_, err := NewJWTTokenService([]byte("short"), time.Hour)
+
+	assert.Error(t, err)
+}
+
+func TestJWTTokenService(t *testing.T) {
+	service, err := NewJWTTokenService(testSecret, time.Minute)
+	require.NoError(t, err)
+
+	t.Run("should parse an issued token", func(t *testing.T) {
+		token, issued, issueErr := service.IssueToken("e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", []string{"seller"})
+		require.Nil(t, issueErr)
+
+		claims, parseErr := service.ParseToken(token)
+
+		require.Nil(t, parseErr)
+		assert.Equal(t, "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", claims.UserId)
+		assert.Equal(t, []string{"seller"}, claims.Roles)
+		assert.True(t, issued.ExpiresAt.Equal(claims.ExpiresAt))
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		token, _, issueErr := service.IssueToken("e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", nil)
+		require.Nil(t, issueErr)
+
+		expired := *service
+		expired.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		_, parseErr := expired.ParseToken(token)
+
+		require.NotNil(t, parseErr)
+		assert.Equal(t, "unauthorized", parseErr.Err)
+	})
+
+	t.Run("should reject a token signed with another key", func(t *testing.T) {
+		other, err := NewJWTTokenService([]byte("fedcba9876543210fedcba9876543210"), time.Minute)
+		require.NoError(t, err)
+		token, _, _ := other.IssueToken("e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7", nil)
+
+		_, parseErr := service.ParseToken(token)
+
+		assert.NotNil(t, parseErr)
+	})
+
+	t.Run("should reject unsigned tokens", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, parseErr := service.ParseToken(token)
+
+		assert.NotNil(t, parseErr)
+	})
+}
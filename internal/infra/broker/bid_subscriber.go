@@ -0,0 +1,155 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/validation"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// BidQueueGroup spreads the bids of the subject across the instances, so each
+// bid is placed once.
+const BidQueueGroup = "auction-bids"
+
+// BidMessage is a bid sent to the bid subject. Unlike POST /bid, the bidder
+// comes in the message, so the subject must only be open to trusted
+// publishers.
+type BidMessage struct {
+	UserId    string               `json:"user_id" binding:"required,uuid"`
+	AuctionId string               `json:"auction_id" binding:"required"`
+	Amount    money_entity.Decimal `json:"amount" binding:"required"`
+	Currency  string               `json:"currency" binding:"omitempty,currency"`
+}
+
+// BidSubscriber places the bids received from the broker as POST /bid does.
+// When the message has a reply subject, the reply is the placed bid or the
+// error, with the same bodies as the route.
+type BidSubscriber struct {
+	bidUseCase     bid_usecase.BidUseCaseInterface
+	userRepository user_entity.UserRepositoryInterface
+	recorder       audit_entity.RecorderInterface
+}
+
+func NewBidSubscriber(
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	userRepository user_entity.UserRepositoryInterface,
+	recorder audit_entity.RecorderInterface) *BidSubscriber {
+	return &BidSubscriber{
+		bidUseCase:     bidUseCase,
+		userRepository: userRepository,
+		recorder:       recorder,
+	}
+}
+
+// SubscribeFromEnv subscribes to NATS_BID_SUBJECT. Without it bids are only
+// placed through the API and it returns nil.
+func (s *BidSubscriber) SubscribeFromEnv(conn *nats.Conn) (*nats.Subscription, error) {
+	subject := os.Getenv("NATS_BID_SUBJECT")
+	if subject == "" {
+		return nil, nil
+	}
+
+	return s.Subscribe(conn, subject)
+}
+
+func (s *BidSubscriber) Subscribe(conn *nats.Conn, subject string) (*nats.Subscription, error) {
+	return conn.QueueSubscribe(subject, BidQueueGroup, s.handle)
+}
+
+func (s *BidSubscriber) handle(msg *nats.Msg) {
+	ctx, span := startBidSpan(msg)
+	defer span.End()
+
+	reply, restErr := s.createBid(ctx, msg.Data)
+	if restErr != nil {
+		span.SetStatus(codes.Error, restErr.Message)
+		logger.Info("Bid from broker was not placed",
+			zap.String("subject", msg.Subject), zap.String("reason", restErr.Message))
+		reply = restErr
+	}
+
+	if msg.Reply == "" {
+		return
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		logger.Error("Error trying to encode bid reply", err)
+		return
+	}
+
+	if err := msg.Respond(data); err != nil {
+		logger.Error("Error trying to reply to bid from broker", err)
+	}
+}
+
+// startBidSpan starts the trace of a bid received from the broker, linked to
+// the trace of its publisher when the message headers carry one.
+func startBidSpan(msg *nats.Msg) (context.Context, trace.Span) {
+	carrier := propagation.MapCarrier{}
+	for key := range msg.Header {
+		carrier[strings.ToLower(key)] = msg.Header.Get(key)
+	}
+	publisher := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	return tracing.StartRoot(context.Background(), msg.Subject+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.LinkFromContext(publisher)),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(msg.Subject)))
+}
+
+func (s *BidSubscriber) createBid(ctx context.Context, data []byte) (interface{}, *rest_err.RestErr) {
+	var bidMessage BidMessage
+	if err := binding.JSON.BindBody(data, &bidMessage); err != nil {
+		return nil, validation.ValidateErr(err)
+	}
+
+	user, err := s.userRepository.FindUserById(ctx, bidMessage.UserId)
+	if err != nil {
+		return nil, rest_err.ConvertError(err)
+	}
+
+	if !user.HasRole(user_entity.RoleBidder) {
+		return nil, rest_err.NewForbiddenError(
+			"This action requires one of the roles: " + string(user_entity.RoleBidder))
+	}
+
+	bidOutputDTO, err := s.bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:    bidMessage.UserId,
+		AuctionId: bidMessage.AuctionId,
+		Amount:    bidMessage.Amount,
+		Currency:  bidMessage.Currency,
+	})
+	if err != nil {
+		return nil, rest_err.ConvertError(err)
+	}
+
+	// Like POST /bid, the bid is audited as placed by its bidder.
+	ctx = audit_entity.WithRequest(ctx, audit_entity.RequestInfo{
+		Actor: audit_entity.Actor{Type: audit_entity.ActorUser, UserId: user.Id},
+	})
+	s.recorder.Record(ctx, audit_entity.NewEntry(ctx, "bid.create", "bid", bidOutputDTO.Id, nil, bidOutputDTO))
+
+	return bidOutputDTO, nil
+}
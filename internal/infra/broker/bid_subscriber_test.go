@@ -0,0 +1,189 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/rest_err"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBidUseCase struct {
+	bid_usecase.BidUseCaseInterface
+
+	placed chan bid_usecase.BidInputDTO
+}
+
+func (f *fakeBidUseCase) CreateBid(
+	_ context.Context, input bid_usecase.BidInputDTO) (*bid_usecase.BidOutputDTO, *internal_error.InternalError) {
+	f.placed <- input
+
+	return &bid_usecase.BidOutputDTO{
+		Id:        "bid-1",
+		UserId:    input.UserId,
+		AuctionId: input.AuctionId,
+		Amount:    input.Amount,
+		Currency:  string(money_entity.DefaultCurrency),
+	}, nil
+}
+
+type fakeUserRepository struct {
+	user_entity.UserRepositoryInterface
+
+	users map[string]*user_entity.User
+}
+
+func (f *fakeUserRepository) FindUserById(
+	_ context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	user, ok := f.users[userId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("User not found")
+	}
+
+	return user, nil
+}
+
+type fakeRecorder struct {
+	mutex   sync.Mutex
+	entries []audit_entity.Entry
+}
+
+func (f *fakeRecorder) Record(_ context.Context, entry audit_entity.Entry) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.entries = append(f.entries, entry)
+}
+
+func (f *fakeRecorder) recorded() []audit_entity.Entry {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]audit_entity.Entry(nil), f.entries...)
+}
+
+func subscribeBids(t *testing.T, users ...*user_entity.User) (*nats.Conn, *fakeBidUseCase, *fakeRecorder) {
+	url, _ := startNATSServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	userRepository := &fakeUserRepository{users: map[string]*user_entity.User{}}
+	for _, user := range users {
+		userRepository.users[user.Id] = user
+	}
+	bidUseCase := &fakeBidUseCase{placed: make(chan bid_usecase.BidInputDTO, 1)}
+
+	recorder := &fakeRecorder{}
+
+	_, err = NewBidSubscriber(bidUseCase, userRepository, recorder).Subscribe(conn, "bids")
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+
+	return conn, bidUseCase, recorder
+}
+
+func TestBidSubscriber(t *testing.T) {
+	bidder := &user_entity.User{Id: uuid.New().String(), Roles: []user_entity.Role{user_entity.RoleBidder}}
+	seller := &user_entity.User{Id: uuid.New().String(), Roles: []user_entity.Role{user_entity.RoleSeller}}
+	auctionId := uuid.New().String()
+
+	t.Run("should place the bid and reply with it", func(t *testing.T) {
+		conn, bidUseCase, recorder := subscribeBids(t, bidder)
+
+		reply, err := conn.Request("bids",
+			[]byte(`{"user_id":"`+bidder.Id+`","auction_id":"`+auctionId+`","amount":"1500.00"}`), time.Second)
+		require.NoError(t, err)
+
+		placed := <-bidUseCase.placed
+		assert.Equal(t, bid_usecase.BidInputDTO{
+			UserId: bidder.Id, AuctionId: auctionId, Amount: money_entity.Decimal("1500.00"),
+		}, placed)
+
+		var bid bid_usecase.BidOutputDTO
+		require.NoError(t, json.Unmarshal(reply.Data, &bid))
+		assert.Equal(t, "bid-1", bid.Id)
+		assert.Equal(t, bidder.Id, bid.UserId)
+
+		entries := recorder.recorded()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "bid.create", entries[0].Action)
+		assert.Equal(t, "bid-1", entries[0].TargetId)
+		assert.Equal(t, audit_entity.Actor{Type: audit_entity.ActorUser, UserId: bidder.Id}, entries[0].Actor)
+	})
+
+	t.Run("should place bids without a reply subject", func(t *testing.T) {
+		conn, bidUseCase, _ := subscribeBids(t, bidder)
+
+		require.NoError(t, conn.Publish("bids",
+			[]byte(`{"user_id":"`+bidder.Id+`","auction_id":"`+auctionId+`","amount":10}`)))
+
+		select {
+		case placed := <-bidUseCase.placed:
+			assert.Equal(t, money_entity.Decimal("10"), placed.Amount)
+		case <-time.After(time.Second):
+			t.Fatal("bid was not placed")
+		}
+	})
+
+	t.Run("should reply with bad request when the message is invalid", func(t *testing.T) {
+		conn, bidUseCase, _ := subscribeBids(t, bidder)
+
+		reply, err := conn.Request("bids", []byte(`{"user_id":"not-a-uuid","auction_id":"`+auctionId+`"}`), time.Second)
+		require.NoError(t, err)
+
+		var restErr rest_err.RestErr
+		require.NoError(t, json.Unmarshal(reply.Data, &restErr))
+		assert.Equal(t, "bad_request", restErr.Err)
+		assert.Len(t, restErr.Causes, 2)
+		assert.Empty(t, bidUseCase.placed)
+	})
+
+	t.Run("should reply with forbidden when the user is not a bidder", func(t *testing.T) {
+		conn, bidUseCase, _ := subscribeBids(t, seller)
+
+		reply, err := conn.Request("bids",
+			[]byte(`{"user_id":"`+seller.Id+`","auction_id":"`+auctionId+`","amount":"10"}`), time.Second)
+		require.NoError(t, err)
+
+		var restErr rest_err.RestErr
+		require.NoError(t, json.Unmarshal(reply.Data, &restErr))
+		assert.Equal(t, "forbidden", restErr.Err)
+		assert.Empty(t, bidUseCase.placed)
+	})
+
+	t.Run("should reply with not found when the user does not exist", func(t *testing.T) {
+		conn, _, _ := subscribeBids(t)
+
+		reply, err := conn.Request("bids",
+			[]byte(`{"user_id":"`+uuid.New().String()+`","auction_id":"`+auctionId+`","amount":"10"}`), time.Second)
+		require.NoError(t, err)
+
+		var restErr rest_err.RestErr
+		require.NoError(t, json.Unmarshal(reply.Data, &restErr))
+		assert.Equal(t, "not_found", restErr.Err)
+	})
+}
+
+func TestSubscribeFromEnv(t *testing.T) {
+	t.Run("should not subscribe when NATS_BID_SUBJECT is not set", func(t *testing.T) {
+		t.Setenv("NATS_BID_SUBJECT", "")
+
+		subscription, err := NewBidSubscriber(nil, nil, nil).SubscribeFromEnv(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, subscription)
+	})
+}
@@ -0,0 +1,57 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// connectionName identifies the application in the monitoring of the server.
+const connectionName = "auction"
+
+// ConnectFromEnv connects to the NATS server at NATS_URL, such as the local
+// server of docker compose. Without NATS_URL there is no broker and it returns
+// nil.
+//
+// The connection is retried in the background while the server is down, so the
+// application starts anyway; the events wait in the outbox until it is up.
+func ConnectFromEnv() (*nats.Conn, error) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		return nil, nil
+	}
+
+	conn, err := nats.Connect(url,
+		nats.Name(connectionName),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS_URL: %w", err)
+	}
+
+	return conn, nil
+}
+
+// Drain lets the subscriptions handle the messages they already received, then
+// closes conn. It waits until conn is closed or ctx is done.
+func Drain(ctx context.Context, conn *nats.Conn) error {
+	if err := conn.Drain(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !conn.IsClosed() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type publishedMessage struct {
+	subject string
+	reply   string
+	header  nats.Header
+	data    []byte
+}
+
+type subscription struct {
+	subject string
+	sid     string
+	conn    net.Conn
+}
+
+// natsServer speaks enough of the NATS protocol for the clients of a test:
+// publishes, with or without headers, are recorded and routed to the matching
+// subscriptions of every connection.
+type natsServer struct {
+	published chan publishedMessage
+
+	mutex         sync.Mutex
+	conns         []net.Conn
+	subscriptions []subscription
+}
+
+func startNATSServer(t *testing.T) (string, *natsServer) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &natsServer{published: make(chan publishedMessage, 16)}
+	t.Cleanup(func() {
+		listener.Close()
+
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		for _, conn := range server.conns {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			server.mutex.Lock()
+			server.conns = append(server.conns, conn)
+			server.mutex.Unlock()
+
+			go server.serve(conn)
+		}
+	}()
+
+	return "nats://" + listener.Addr().String(), server
+}
+
+func (s *natsServer) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	text := textproto.NewReader(reader)
+
+	fmt.Fprint(conn, `INFO {"server_id":"test","version":"2.10.0","proto":1,"headers":true,"max_payload":1048576}`+"\r\n")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "SUB":
+			// SUB <subject> [queue] <sid>
+			s.mutex.Lock()
+			s.subscriptions = append(s.subscriptions,
+				subscription{subject: fields[1], sid: fields[len(fields)-1], conn: conn})
+			s.mutex.Unlock()
+		case "PUB", "HPUB":
+			msg, err := readPublished(reader, fields)
+			if err != nil {
+				return
+			}
+			s.published <- msg
+			s.route(msg)
+		}
+	}
+}
+
+// readPublished reads PUB <subject> [reply] <size> and
+// HPUB <subject> [reply] <header size> <total size>, followed by the payload.
+func readPublished(reader *bufio.Reader, fields []string) (publishedMessage, error) {
+	msg := publishedMessage{subject: fields[1]}
+
+	sizes := 1
+	if strings.ToUpper(fields[0]) == "HPUB" {
+		sizes = 2
+	}
+	if len(fields) == 2+sizes+1 {
+		msg.reply = fields[2]
+	}
+
+	total, _ := strconv.Atoi(fields[len(fields)-1])
+	headerSize := 0
+	if sizes == 2 {
+		headerSize, _ = strconv.Atoi(fields[len(fields)-2])
+	}
+
+	payload := make([]byte, total+2)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return msg, err
+	}
+
+	if headerSize > 0 {
+		header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(
+			strings.SplitN(string(payload[:headerSize]), "\r\n", 2)[1]))).ReadMIMEHeader()
+		if err != nil && err != io.EOF {
+			return msg, err
+		}
+		msg.header = nats.Header(header)
+	}
+	msg.data = payload[headerSize:total]
+
+	return msg, nil
+}
+
+func (s *natsServer) route(msg publishedMessage) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, sub := range s.subscriptions {
+		if !subjectMatches(sub.subject, msg.subject) {
+			continue
+		}
+
+		if msg.reply != "" {
+			fmt.Fprintf(sub.conn, "MSG %s %s %s %d\r\n%s\r\n", msg.subject, sub.sid, msg.reply, len(msg.data), msg.data)
+		} else {
+			fmt.Fprintf(sub.conn, "MSG %s %s %d\r\n%s\r\n", msg.subject, sub.sid, len(msg.data), msg.data)
+		}
+	}
+}
+
+func subjectMatches(pattern, subject string) bool {
+	patternTokens, subjectTokens := strings.Split(pattern, "."), strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
+
+func TestConnectFromEnv(t *testing.T) {
+	t.Run("should return nil when NATS_URL is not set", func(t *testing.T) {
+		t.Setenv("NATS_URL", "")
+
+		conn, err := ConnectFromEnv()
+
+		assert.NoError(t, err)
+		assert.Nil(t, conn)
+	})
+
+	t.Run("should connect to the server at NATS_URL", func(t *testing.T) {
+		url, _ := startNATSServer(t)
+		t.Setenv("NATS_URL", url)
+
+		conn, err := ConnectFromEnv()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assert.NoError(t, conn.Flush())
+	})
+}
+
+func TestDrain(t *testing.T) {
+	url, _ := startNATSServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	_, err = conn.Subscribe("events.>", func(*nats.Msg) {})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, Drain(ctx, conn))
+	assert.True(t, conn.IsClosed())
+}
@@ -0,0 +1,122 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// MessageVersion is the version of Message, also part of the subjects. It is
+// bumped when a change would break consumers; new fields are added to Data
+// without a new version.
+const MessageVersion = 1
+
+// DefaultSubjectPrefix is used when NATS_SUBJECT_PREFIX is not set.
+const DefaultSubjectPrefix = "events"
+
+// DefaultFlushTimeout bounds the wait for the server to confirm each message.
+const DefaultFlushTimeout = 5 * time.Second
+
+// Message is the JSON body of every event published to the broker. Data is
+// the payload of the outbox event, as documented for each type.
+type Message struct {
+	Version    int             `json:"version"`
+	Id         string          `json:"id"`
+	Sequence   int64           `json:"sequence"`
+	Type       string          `json:"type"`
+	AuctionId  string          `json:"auction_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// publishedEvents are the accepted bids and the auction lifecycle changes. The
+// changes of leader are already told by the accepted bids.
+var publishedEvents = map[outbox_entity.EventType]bool{
+	outbox_entity.AuctionCreated:   true,
+	outbox_entity.AuctionPaused:    true,
+	outbox_entity.AuctionResumed:   true,
+	outbox_entity.AuctionClosed:    true,
+	outbox_entity.AuctionCancelled: true,
+	outbox_entity.BidAccepted:      true,
+}
+
+// NATSPublisher publishes the outbox events to <prefix>.v<version>.<type>, for
+// example events.v1.bid.accepted. The event id goes in the Nats-Msg-Id header,
+// so a JetStream stream on the subjects drops the events published again after
+// a failed relay.
+type NATSPublisher struct {
+	conn         *nats.Conn
+	prefix       string
+	flushTimeout time.Duration
+}
+
+// NewNATSPublisherFromEnv reads the subject prefix from NATS_SUBJECT_PREFIX.
+func NewNATSPublisherFromEnv(conn *nats.Conn) *NATSPublisher {
+	prefix := os.Getenv("NATS_SUBJECT_PREFIX")
+	if prefix == "" {
+		prefix = DefaultSubjectPrefix
+	}
+
+	return NewNATSPublisher(conn, prefix, DefaultFlushTimeout)
+}
+
+func NewNATSPublisher(conn *nats.Conn, prefix string, flushTimeout time.Duration) *NATSPublisher {
+	return &NATSPublisher{
+		conn:         conn,
+		prefix:       prefix,
+		flushTimeout: flushTimeout,
+	}
+}
+
+// Subject returns the subject the events of eventType are published to.
+func (p *NATSPublisher) Subject(eventType outbox_entity.EventType) string {
+	return fmt.Sprintf("%s.v%d.%s", p.prefix, MessageVersion, eventType)
+}
+
+// Publish returns only after the server got the message, so the outbox does
+// not move past an event the server never saw.
+func (p *NATSPublisher) Publish(
+	_ context.Context, event outbox_entity.Event) *internal_error.InternalError {
+	if !publishedEvents[event.Type] {
+		return nil
+	}
+
+	data, err := json.Marshal(Message{
+		Version:    MessageVersion,
+		Id:         event.Id,
+		Sequence:   event.Sequence,
+		Type:       string(event.Type),
+		AuctionId:  event.AuctionId,
+		OccurredAt: event.OccurredAt,
+		Data:       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		logger.Error("Error trying to encode broker message", err)
+		return internal_error.NewInternalServerError("Error trying to encode broker message")
+	}
+
+	msg := nats.NewMsg(p.Subject(event.Type))
+	msg.Header.Set(nats.MsgIdHdr, event.Id)
+	msg.Header.Set("Content-Type", "application/json")
+	msg.Data = data
+
+	if err := p.conn.PublishMsg(msg); err != nil {
+		logger.Error("Error trying to publish broker message", err, zap.String("subject", msg.Subject))
+		return internal_error.NewInternalServerError("Error trying to publish broker message")
+	}
+
+	if err := p.conn.FlushTimeout(p.flushTimeout); err != nil {
+		logger.Error("Error trying to flush broker messages", err, zap.String("subject", msg.Subject))
+		return internal_error.NewInternalServerError("Error trying to publish broker message")
+	}
+
+	return nil
+}
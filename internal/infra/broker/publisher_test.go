@@ -0,0 +1,109 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func connectPublisher(t *testing.T) (*NATSPublisher, *natsServer) {
+	url, server := startNATSServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	return NewNATSPublisher(conn, DefaultSubjectPrefix, time.Second), server
+}
+
+func TestNATSPublisher(t *testing.T) {
+	t.Run("should publish accepted bids as versioned messages", func(t *testing.T) {
+		publisher, server := connectPublisher(t)
+
+		bid := bid_entity.Bid{
+			Id:        uuid.New().String(),
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			Amount:    money_entity.New(150000, money_entity.DefaultCurrency),
+			Timestamp: time.Now(),
+		}
+		event := outbox_entity.NewBidAccepted(bid, true, nil)
+		event.Sequence = 42
+
+		err := publisher.Publish(context.Background(), event)
+		require.Nil(t, err)
+
+		msg := <-server.published
+		assert.Equal(t, "events.v1.bid.accepted", msg.subject)
+		assert.Equal(t, event.Id, msg.header.Get(nats.MsgIdHdr))
+		assert.Equal(t, "application/json", msg.header.Get("Content-Type"))
+
+		var message Message
+		require.NoError(t, json.Unmarshal(msg.data, &message))
+		assert.Equal(t, MessageVersion, message.Version)
+		assert.Equal(t, event.Id, message.Id)
+		assert.Equal(t, int64(42), message.Sequence)
+		assert.Equal(t, "bid.accepted", message.Type)
+		assert.Equal(t, bid.AuctionId, message.AuctionId)
+		assert.JSONEq(t, string(event.Payload), string(message.Data))
+	})
+
+	t.Run("should publish the auction lifecycle changes", func(t *testing.T) {
+		publisher, server := connectPublisher(t)
+
+		event, _ := outbox_entity.NewStatusChanged(uuid.New().String(), auction_entity.Completed)
+		require.Nil(t, publisher.Publish(context.Background(), event))
+
+		msg := <-server.published
+		assert.Equal(t, "events.v1.auction.closed", msg.subject)
+	})
+
+	t.Run("should skip the events that are not published", func(t *testing.T) {
+		publisher, server := connectPublisher(t)
+
+		bid := bid_entity.Bid{Id: uuid.New().String(), AuctionId: uuid.New().String(), Timestamp: time.Now()}
+		event := outbox_entity.NewBidRejected(bid, bid_entity.ReasonAuctionEnded, "auction ended")
+
+		require.Nil(t, publisher.Publish(context.Background(), event))
+		assert.Empty(t, server.published)
+	})
+
+	t.Run("should return internal error when the connection is closed", func(t *testing.T) {
+		publisher, _ := connectPublisher(t)
+		publisher.conn.Close()
+
+		event, _ := outbox_entity.NewStatusChanged(uuid.New().String(), auction_entity.Cancelled)
+		err := publisher.Publish(context.Background(), event)
+
+		require.NotNil(t, err)
+		assert.Equal(t, "Error trying to publish broker message", err.Message)
+	})
+}
+
+func TestNewNATSPublisherFromEnv(t *testing.T) {
+	t.Run("should publish under the default prefix", func(t *testing.T) {
+		t.Setenv("NATS_SUBJECT_PREFIX", "")
+
+		publisher := NewNATSPublisherFromEnv(nil)
+
+		assert.Equal(t, "events.v1.auction.created", publisher.Subject(outbox_entity.AuctionCreated))
+	})
+
+	t.Run("should publish under NATS_SUBJECT_PREFIX", func(t *testing.T) {
+		t.Setenv("NATS_SUBJECT_PREFIX", "auction.platform")
+
+		publisher := NewNATSPublisherFromEnv(nil)
+
+		assert.Equal(t, "auction.platform.v1.bid.accepted", publisher.Subject(outbox_entity.BidAccepted))
+	})
+}
@@ -0,0 +1,157 @@
+package api_key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/api_key_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ApiKeyEntityMongo struct {
+	Id         string   `bson:"_id"`
+	Name       string   `bson:"name"`
+	OwnerId    string   `bson:"owner_id"`
+	Prefix     string   `bson:"prefix"`
+	Hash       string   `bson:"hash"`
+	Scopes     []string `bson:"scopes"`
+	ExpiresAt  *int64   `bson:"expires_at,omitempty"`
+	LastUsedAt *int64   `bson:"last_used_at,omitempty"`
+	RevokedAt  *int64   `bson:"revoked_at,omitempty"`
+	CreatedAt  int64    `bson:"created_at"`
+}
+
+type ApiKeyRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewApiKeyRepository(database *mongo.Database) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Collection: database.Collection("api_keys"),
+	}
+}
+
+func (ar *ApiKeyRepository) CreateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "ApiKeyRepository.CreateApiKey")
+	defer span.End()
+
+	apiKeyEntityMongo := &ApiKeyEntityMongo{
+		Id:        apiKey.Id,
+		Name:      apiKey.Name,
+		OwnerId:   apiKey.OwnerId,
+		Prefix:    apiKey.Prefix,
+		Hash:      apiKey.Hash,
+		Scopes:    apiKey.ScopeNames(),
+		ExpiresAt: toUnix(apiKey.ExpiresAt),
+		CreatedAt: apiKey.CreatedAt.Unix(),
+	}
+
+	if _, err := ar.Collection.InsertOne(ctx, apiKeyEntityMongo); err != nil {
+		logger.Error("Error trying to insert API key", err)
+		return internal_error.NewInternalServerError("Error trying to insert API key")
+	}
+
+	return nil
+}
+
+// RevokeApiKey marks the key as revoked; revoking it again keeps the first date.
+func (ar *ApiKeyRepository) RevokeApiKey(
+	ctx context.Context, id string, revokedAt time.Time) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "ApiKeyRepository.RevokeApiKey")
+	defer span.End()
+
+	result, err := ar.Collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.A{bson.M{"$set": bson.M{
+			"revoked_at": bson.M{"$ifNull": bson.A{"$revoked_at", revokedAt.Unix()}},
+		}}})
+	if err != nil {
+		logger.Error("Error trying to revoke API key", err)
+		return internal_error.NewInternalServerError("Error trying to revoke API key")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(fmt.Sprintf("API key not found with this id = %s", id))
+	}
+
+	return nil
+}
+
+func (ar *ApiKeyRepository) TouchApiKey(
+	ctx context.Context, id string, usedAt time.Time) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "ApiKeyRepository.TouchApiKey")
+	defer span.End()
+
+	if _, err := ar.Collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$max": bson.M{"last_used_at": usedAt.Unix()}}); err != nil {
+		logger.Error("Error trying to update API key last use", err)
+		return internal_error.NewInternalServerError("Error trying to update API key last use")
+	}
+
+	return nil
+}
+
+func (ar *ApiKeyRepository) FindApiKeyByHash(
+	ctx context.Context, hash string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "ApiKeyRepository.FindApiKeyByHash")
+	defer span.End()
+
+	var apiKeyEntityMongo ApiKeyEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&apiKeyEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError("API key not found")
+	}
+	if err != nil {
+		logger.Error("Error trying to find API key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find API key")
+	}
+
+	return toApiKeyEntity(apiKeyEntityMongo), nil
+}
+
+func toApiKeyEntity(apiKeyEntityMongo ApiKeyEntityMongo) *api_key_entity.ApiKey {
+	scopes := make([]api_key_entity.Scope, 0, len(apiKeyEntityMongo.Scopes))
+	for _, scope := range apiKeyEntityMongo.Scopes {
+		scopes = append(scopes, api_key_entity.Scope(scope))
+	}
+
+	return &api_key_entity.ApiKey{
+		Id:         apiKeyEntityMongo.Id,
+		Name:       apiKeyEntityMongo.Name,
+		OwnerId:    apiKeyEntityMongo.OwnerId,
+		Prefix:     apiKeyEntityMongo.Prefix,
+		Hash:       apiKeyEntityMongo.Hash,
+		Scopes:     scopes,
+		ExpiresAt:  fromUnix(apiKeyEntityMongo.ExpiresAt),
+		LastUsedAt: fromUnix(apiKeyEntityMongo.LastUsedAt),
+		RevokedAt:  fromUnix(apiKeyEntityMongo.RevokedAt),
+		CreatedAt:  time.Unix(apiKeyEntityMongo.CreatedAt, 0),
+	}
+}
+
+func toUnix(value *time.Time) *int64 {
+	if value == nil {
+		return nil
+	}
+
+	unix := value.Unix()
+	return &unix
+}
+
+func fromUnix(value *int64) *time.Time {
+	if value == nil {
+		return nil
+	}
+
+	parsed := time.Unix(*value, 0)
+	return &parsed
+}
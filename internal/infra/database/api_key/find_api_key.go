@@ -0,0 +1,76 @@
+package api_key
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/api_key_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindApiKeys lists the keys, newest first, including revoked and expired ones.
+func (ar *ApiKeyRepository) FindApiKeys(
+	ctx context.Context,
+	page pagination_entity.PageRequest) ([]api_key_entity.ApiKey, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "ApiKeyRepository.FindApiKeys")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "created_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := ar.Collection.Find(ctx, pagination.WithCursor(bson.M{}, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find API keys", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find API keys")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var apiKeysMongo []ApiKeyEntityMongo
+	if err := mongoCursor.All(ctx, &apiKeysMongo); err != nil {
+		logger.Error("Error trying to decode API keys", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find API keys")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(apiKeysMongo)) > limit {
+		apiKeysMongo = apiKeysMongo[:limit]
+		last := apiKeysMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.CreatedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := ar.Collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			logger.Error("Error trying to count API keys", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count API keys")
+		}
+		pageInfo.Total = &total
+	}
+
+	var apiKeys []api_key_entity.ApiKey
+	for _, apiKeyEntityMongo := range apiKeysMongo {
+		apiKeys = append(apiKeys, *toApiKeyEntity(apiKeyEntityMongo))
+	}
+
+	return apiKeys, pageInfo, nil
+}
@@ -3,81 +3,251 @@ package auction
 import (
 	"context"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type AuctionEntityMongo struct {
-	Id          string                          `bson:"_id"`
-	ProductName string                          `bson:"product_name"`
-	Category    string                          `bson:"category"`
-	Description string                          `bson:"description"`
-	Condition   auction_entity.ProductCondition `bson:"condition"`
-	Status      auction_entity.AuctionStatus    `bson:"status"`
-	Timestamp   int64                           `bson:"timestamp"`
+	Id           string                          `bson:"_id"`
+	SellerId     string                          `bson:"seller_id,omitempty"`
+	ProductName  string                          `bson:"product_name"`
+	Category     string                          `bson:"category"`
+	Description  string                          `bson:"description"`
+	Condition    auction_entity.ProductCondition `bson:"condition"`
+	Status       auction_entity.AuctionStatus    `bson:"status"`
+	CurrentPrice int64                           `bson:"current_price"`
+	Currency     string                          `bson:"currency"`
+	LeadingBidId string                          `bson:"leading_bid_id,omitempty"`
+	LeaderId     string                          `bson:"leader_id,omitempty"`
+	Timestamp    int64                           `bson:"timestamp"`
+	EndTime      int64                           `bson:"end_time,omitempty"`
 }
 type AuctionRepository struct {
 	Collection *mongo.Collection
+	Outbox     outbox_entity.OutboxRepositoryInterface
+
+	statusListeners      []StatusListener
+	statusListenersMutex sync.RWMutex
+
+	endTimeListeners      []EndTimeListener
+	endTimeListenersMutex sync.RWMutex
+
+	closeListeners      []CloseListener
+	closeListenersMutex sync.RWMutex
+
+	// pendingCloses holds the end of each auction waiting for its automatic
+	// close, which CheckHealth watches.
+	pendingCloses      map[string]time.Time
+	pendingClosesMutex sync.Mutex
 }
 
-func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
+// StatusListener is notified after an auction status is changed by this repository.
+type StatusListener func(auctionId string, status auction_entity.AuctionStatus)
+
+// EndTimeListener is notified after the end of an auction is moved.
+type EndTimeListener func(auctionId string, endTime time.Time)
+
+// CloseListener is notified after an auction is completed at its end, as
+// opposed to closed by an admin.
+type CloseListener func(auctionId string)
+
+func NewAuctionRepository(
+	database *mongo.Database, outboxRepository outbox_entity.OutboxRepositoryInterface) *AuctionRepository {
 	return &AuctionRepository{
 		Collection: database.Collection("auctions"),
+		Outbox:     outboxRepository,
+	}
+}
+
+// OnStatusChange registers listener, letting caches of the auction status
+// (like the one used by the bid batches) follow pauses and closures.
+func (ar *AuctionRepository) OnStatusChange(listener StatusListener) {
+	ar.statusListenersMutex.Lock()
+	defer ar.statusListenersMutex.Unlock()
+
+	ar.statusListeners = append(ar.statusListeners, listener)
+}
+
+// OnEndTimeChange registers listener, letting caches of the auction end follow
+// the extensions.
+func (ar *AuctionRepository) OnEndTimeChange(listener EndTimeListener) {
+	ar.endTimeListenersMutex.Lock()
+	defer ar.endTimeListenersMutex.Unlock()
+
+	ar.endTimeListeners = append(ar.endTimeListeners, listener)
+}
+
+// OnAutomaticClose registers listener, letting the closures made without a
+// request be audited.
+func (ar *AuctionRepository) OnAutomaticClose(listener CloseListener) {
+	ar.closeListenersMutex.Lock()
+	defer ar.closeListenersMutex.Unlock()
+
+	ar.closeListeners = append(ar.closeListeners, listener)
+}
+
+func (ar *AuctionRepository) notifyAutomaticClose(auctionId string) {
+	ar.closeListenersMutex.RLock()
+	defer ar.closeListenersMutex.RUnlock()
+
+	for _, listener := range ar.closeListeners {
+		listener(auctionId)
+	}
+}
+
+func (ar *AuctionRepository) notifyEndTimeChange(auctionId string, endTime time.Time) {
+	ar.endTimeListenersMutex.RLock()
+	defer ar.endTimeListenersMutex.RUnlock()
+
+	for _, listener := range ar.endTimeListeners {
+		listener(auctionId, endTime)
+	}
+}
+
+func (ar *AuctionRepository) notifyStatusChange(auctionId string, status auction_entity.AuctionStatus) {
+	ar.statusListenersMutex.RLock()
+	defer ar.statusListenersMutex.RUnlock()
+
+	for _, listener := range ar.statusListeners {
+		listener(auctionId, status)
 	}
 }
 
 func (ar *AuctionRepository) CreateAuction(
 	requestCtx context.Context,
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	auctionInterval := getAuctioInterval()
+	auctionEntity.EndTime = auctionEntity.Timestamp.Add(auctionInterval)
+
 	auctionEntityMongo := &AuctionEntityMongo{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   auctionEntity.Condition,
-		Status:      auctionEntity.Status,
-		Timestamp:   auctionEntity.Timestamp.Unix(),
+		Id:           auctionEntity.Id,
+		ProductName:  auctionEntity.ProductName,
+		Category:     auctionEntity.Category,
+		Description:  auctionEntity.Description,
+		Condition:    auctionEntity.Condition,
+		Status:       auctionEntity.Status,
+		SellerId:     auctionEntity.SellerId,
+		CurrentPrice: auctionEntity.CurrentPrice.Amount,
+		Currency:     string(auctionEntity.Currency()),
+		Timestamp:    auctionEntity.Timestamp.Unix(),
+		EndTime:      auctionEntity.EndTime.Unix(),
 	}
 
+	// The auction outlives the request: its storage is not cancelled with the
+	// request, but stays in the request trace.
 	ctx := context.Background()
+	if requestCtx != nil {
+		ctx = context.WithoutCancel(requestCtx)
+	}
+	ctx, span := tracing.Start(ctx, "AuctionRepository.CreateAuction")
+	defer span.End()
+
 	if os.Getenv("APP_MODE") != "test" || requestCtx == nil {
-		requestCtx = ctx
+		requestCtx = context.Background()
 	}
 
-	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
+	err := ar.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		if _, err := ar.Collection.InsertOne(ctx, auctionEntityMongo); err != nil {
+			logger.Error("Error trying to insert auction", err)
+			return nil, internal_error.NewInternalServerError("Error trying to insert auction")
+		}
+		return []outbox_entity.Event{outbox_entity.NewAuctionCreated(*auctionEntity)}, nil
+	})
 	if err != nil {
-		logger.Error("Error trying to insert auction", err)
-		return internal_error.NewInternalServerError("Error trying to insert auction")
+		return err
 	}
 
 	go func() {
-		select {
-		case <-time.After(getAuctioInterval()):
-			closeAuction(ctx, ar.Collection, auctionEntityMongo.Id)
-		case <-requestCtx.Done():
-			logger.Error("Error to close auction, context cancelled", requestCtx.Err())
-			return
+		defer ar.unscheduleClose(auctionEntityMongo.Id)
+
+		wait := auctionInterval
+		for {
+			ar.scheduleClose(auctionEntityMongo.Id, time.Now().Add(wait))
+
+			select {
+			case <-time.After(wait):
+			case <-requestCtx.Done():
+				logger.Error("Error to close auction, context cancelled", requestCtx.Err())
+				return
+			}
+
+			closeCtx, closeSpan := tracing.StartRoot(context.Background(), "AuctionRepository.closeAuction",
+				trace.WithLinks(trace.LinkFromContext(ctx)),
+				trace.WithAttributes(attribute.String("auction.id", auctionEntityMongo.Id)))
+			endTime, extended := ar.closeAuction(closeCtx, auctionEntityMongo.Id)
+			closeSpan.End()
+			if !extended {
+				return
+			}
+			wait = time.Until(endTime)
 		}
 	}()
 
 	return nil
 }
 
-func closeAuction(ctx context.Context, collection *mongo.Collection, auctionId string) {
+// closeAuction completes the auction once it ends, unless an admin already
+// cancelled or closed it. When the auction was extended meanwhile, it returns
+// the new end instead.
+func (ar *AuctionRepository) closeAuction(ctx context.Context, auctionId string) (time.Time, bool) {
+	now := time.Now()
 	update := bson.M{"$set": bson.M{"status": auction_entity.Completed}}
-	filter := bson.M{"_id": auctionId}
+	filter := bson.M{
+		"_id":    auctionId,
+		"status": bson.M{"$in": auction_entity.OpenStatuses},
+		"$or": bson.A{
+			bson.M{"end_time": bson.M{"$exists": false}},
+			bson.M{"end_time": bson.M{"$lte": now.Unix()}},
+		},
+	}
+
+	completed := false
+	err := ar.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			logger.Error("Error trying to update auction status to completed", err)
+			return nil, internal_error.NewInternalServerError("Error trying to update auction status to completed")
+		}
 
-	_, err := collection.UpdateOne(ctx, filter, update)
+		completed = result.ModifiedCount > 0
+		if !completed {
+			return nil, nil
+		}
+		event, _ := outbox_entity.NewStatusChanged(auctionId, auction_entity.Completed)
+		return []outbox_entity.Event{event}, nil
+	})
 	if err != nil {
-		logger.Error("Error trying to update auction status to completed", err)
-		return
+		return time.Time{}, false
+	}
+
+	if completed {
+		ar.notifyStatusChange(auctionId, auction_entity.Completed)
+		ar.notifyAutomaticClose(auctionId)
+		return time.Time{}, false
 	}
+
+	auction, findErr := ar.FindAuctionById(ctx, auctionId)
+	if findErr != nil {
+		return time.Time{}, false
+	}
+
+	open := auction.Status == auction_entity.Active || auction.Status == auction_entity.Paused
+	if !open || !auction.EndTime.After(now) {
+		return time.Time{}, false
+	}
+
+	return auction.EndTime, true
 }
 
 func getAuctioInterval() time.Duration {
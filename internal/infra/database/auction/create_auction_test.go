@@ -3,14 +3,17 @@ package auction
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
@@ -19,7 +22,7 @@ func TestNewAuctionRepository(t *testing.T) {
 
 	mt.Run("should return repository with auctions collection when database is valid", func(mt *mtest.T) {
 		db := mt.Client.Database("testdb")
-		repo := NewAuctionRepository(db)
+		repo := NewAuctionRepository(db, &recordingOutbox{})
 
 		require.NotNil(mt, repo, "repository should not be nil")
 		require.NotNil(mt, repo.Collection, "collection should not be nil")
@@ -29,7 +32,7 @@ func TestNewAuctionRepository(t *testing.T) {
 
 	mt.Run("should panic when database is nil", func(mt *mtest.T) {
 		assert.Panics(mt, func() {
-			NewAuctionRepository(nil)
+			NewAuctionRepository(nil, &recordingOutbox{})
 		}, "expected panic when database is nil")
 	})
 }
@@ -39,7 +42,7 @@ func TestCreateAuction(t *testing.T) {
 
 	mt.Run("should return internal error when InsertOne fails", func(mt *mtest.T) {
 		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "insert error"}))
-		repo := &AuctionRepository{Collection: mt.Coll}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
 
 		auction := &auction_entity.Auction{
 			Id:          "1",
@@ -59,7 +62,7 @@ func TestCreateAuction(t *testing.T) {
 
 	mt.Run("should not return error when InsertOne succeeds and context is cancelled", func(mt *mtest.T) {
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
-		repo := &AuctionRepository{Collection: mt.Coll}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 		defer cancel()
@@ -84,11 +87,34 @@ func TestCreateAuction(t *testing.T) {
 		time.Sleep(30 * time.Millisecond)
 	})
 
+	mt.Run("should record the created auction only after the insert", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "insert error"}),
+			mtest.CreateSuccessResponse())
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		originalAuctionInterval := os.Getenv("AUCTION_INTERVAL")
+		os.Setenv("AUCTION_INTERVAL", "1h")
+		defer os.Setenv("AUCTION_INTERVAL", originalAuctionInterval)
+
+		failed := &auction_entity.Auction{Id: "4", Status: auction_entity.Active, Timestamp: time.Now()}
+		assert.NotNil(mt, repo.CreateAuction(context.Background(), failed))
+
+		created := &auction_entity.Auction{Id: "5", Status: auction_entity.Active, Timestamp: time.Now()}
+		assert.Nil(mt, repo.CreateAuction(context.Background(), created))
+
+		events := outbox.recorded()
+		require.Len(mt, events, 1)
+		assert.Equal(mt, outbox_entity.AuctionCreated, events[0].Type)
+		assert.Equal(mt, "5", events[0].AuctionId)
+	})
+
 	mt.Run("should call closeAuction successfully when InsertOne and UpdateOne succeed", func(mt *mtest.T) {
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
 
-		repo := &AuctionRepository{Collection: mt.Coll}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
 
 		originalAuctionInterval := os.Getenv("AUCTION_INTERVAL")
 		os.Setenv("AUCTION_INTERVAL", "10ms")
@@ -115,7 +141,7 @@ func TestCreateAuction(t *testing.T) {
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
 		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "update error"}))
 
-		repo := &AuctionRepository{Collection: mt.Coll}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
 
 		originalAuctionInterval := os.Getenv("AUCTION_INTERVAL")
 		os.Setenv("AUCTION_INTERVAL", "10ms")
@@ -155,7 +181,7 @@ func TestCreateAuction(t *testing.T) {
 		defer os.Setenv("AUCTION_INTERVAL", originalAuctionInterval)
 
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
-		repo := &AuctionRepository{Collection: mt.Coll}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
 
 		// Create an external context that will be cancelled before the auction interval
 		ctx, cancel := context.WithCancel(context.Background())
@@ -183,6 +209,41 @@ func TestCreateAuction(t *testing.T) {
 
 }
 
+// recordingOutbox keeps the events appended by the repository.
+type recordingOutbox struct {
+	outbox_entity.OutboxRepositoryInterface
+
+	mutex  sync.Mutex
+	events []outbox_entity.Event
+}
+
+func (o *recordingOutbox) AppendEvents(
+	ctx context.Context, events ...outbox_entity.Event) *internal_error.InternalError {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.events = append(o.events, events...)
+	return nil
+}
+
+// WithEvents records the events of write without a transaction.
+func (o *recordingOutbox) WithEvents(
+	ctx context.Context, write outbox_entity.WriteFunc) *internal_error.InternalError {
+	events, err := write(ctx)
+	if err != nil {
+		return err
+	}
+
+	return o.AppendEvents(ctx, events...)
+}
+
+func (o *recordingOutbox) recorded() []outbox_entity.Event {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return append([]outbox_entity.Event(nil), o.events...)
+}
+
 func TestGetAuctionInterval(t *testing.T) {
 
 	t.Run("should return the duration from environment when AUCTION_INTERVAL is valid", func(t *testing.T) {
@@ -208,3 +269,56 @@ func TestGetAuctionInterval(t *testing.T) {
 		assert.Equal(t, 2*time.Minute, interval)
 	})
 }
+
+func TestCloseAuction(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the new end when the auction was extended", func(mt *mtest.T) {
+		endTime := time.Now().Add(time.Hour).Truncate(time.Second)
+		extended := append(auctionDocument("1", time.Now().Unix()), bson.E{Key: "end_time", Value: endTime.Unix()})
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, extended),
+		)
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		next, ok := repo.closeAuction(context.Background(), "1")
+
+		assert.True(mt, ok)
+		assert.Equal(mt, endTime, next)
+		assert.Empty(mt, outbox.recorded())
+	})
+
+	mt.Run("should stop when the auction is no longer open", func(mt *mtest.T) {
+		cancelled := auctionDocument("1", time.Now().Unix())
+		cancelled[5] = bson.E{Key: "status", Value: int32(auction_entity.Cancelled)}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, cancelled),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		_, ok := repo.closeAuction(context.Background(), "1")
+
+		assert.False(mt, ok)
+	})
+
+	mt.Run("should complete the auction once it ends", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+		var closed []string
+		repo.OnAutomaticClose(func(auctionId string) {
+			closed = append(closed, auctionId)
+		})
+
+		_, ok := repo.closeAuction(context.Background(), "1")
+
+		assert.False(mt, ok)
+		events := outbox.recorded()
+		require.Len(mt, events, 1)
+		assert.Equal(mt, outbox_entity.AuctionClosed, events[0].Type)
+		assert.Equal(mt, []string{"1"}, closed)
+	})
+}
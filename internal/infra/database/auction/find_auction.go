@@ -2,82 +2,276 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (ar *AuctionRepository) FindAuctionById(
 	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.FindAuctionById")
+	defer span.End()
+
 	filter := bson.M{"_id": id}
 
 	var auctionEntityMongo AuctionEntityMongo
-	if err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
+	err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("Auction not found with this id = %s", id))
+	}
+	if err != nil {
 		logger.Error(fmt.Sprintf("Error trying to find auction by id = %s", id), err)
 		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
 	}
 
-	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-	}, nil
+	return toAuctionEntity(auctionEntityMongo), nil
+}
+
+func (ar *AuctionRepository) FindAuctionsByIds(
+	ctx context.Context, ids []string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.FindAuctionsByIds")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []auction_entity.Auction{}, nil
+	}
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		logger.Error("Error trying to find auctions by ids", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions by ids")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error trying to decode auctions by ids", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions by ids")
+	}
+
+	return toAuctionEntities(auctionsMongo), nil
 }
 
 func (repo *AuctionRepository) FindAuctions(
 	ctx context.Context,
-	status auction_entity.AuctionStatus,
-	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	query auction_entity.AuctionQuery,
+	page pagination_entity.PageRequest) ([]auction_entity.Auction, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.FindAuctions")
+	defer span.End()
+
+	filter := buildAuctionFilter(query.AuctionFilter)
+
+	sortName, sortField, direction := auctionSortKey(query.Sort)
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := repo.Collection.Find(ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error finding auctions", err)
+		return nil, nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := mongoCursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions", err)
+		return nil, nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(auctionsMongo)) > limit {
+		auctionsMongo = auctionsMongo[:limit]
+		last := auctionsMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.sortValue(sortField), last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := repo.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error counting auctions", err)
+			return nil, nil, internal_error.NewInternalServerError("Error counting auctions")
+		}
+		pageInfo.Total = &total
+	}
+
+	return toAuctionEntities(auctionsMongo), pageInfo, nil
+}
+
+func buildAuctionFilter(auctionFilter auction_entity.AuctionFilter) bson.M {
 	filter := bson.M{}
 
-	if status != -1 {
-		filter["status"] = status
+	if len(auctionFilter.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": auctionFilter.Statuses}
+	}
+
+	if len(auctionFilter.Categories) > 0 {
+		filter["category"] = bson.M{"$in": auctionFilter.Categories}
+	}
+
+	if len(auctionFilter.Conditions) > 0 {
+		filter["condition"] = bson.M{"$in": auctionFilter.Conditions}
 	}
 
-	if category != "" {
-		filter["category"] = category
+	if auctionFilter.ProductName != "" {
+		filter["product_name"] = primitive.Regex{
+			Pattern: regexp.QuoteMeta(auctionFilter.ProductName),
+			Options: "i",
+		}
 	}
 
-	if productName != "" {
-		filter["product_name"] = primitive.Regex{Pattern: productName, Options: "i"}
+	if auctionFilter.Currency != "" {
+		filter["currency"] = auctionFilter.Currency
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	// Minor units are only comparable within a currency, so price bounds also
+	// restrict the auctions to the currency they were given in.
+	price := bson.M{}
+	if auctionFilter.MinPrice != nil {
+		price["$gte"] = auctionFilter.MinPrice.Amount
+		filter["currency"] = auctionFilter.MinPrice.Currency
+	}
+	if auctionFilter.MaxPrice != nil {
+		price["$lte"] = auctionFilter.MaxPrice.Amount
+		filter["currency"] = auctionFilter.MaxPrice.Currency
+	}
+	if len(price) > 0 {
+		filter["current_price"] = price
+	}
+
+	timestamp := bson.M{}
+	if auctionFilter.CreatedFrom != nil {
+		timestamp["$gte"] = auctionFilter.CreatedFrom.Unix()
+	}
+	if auctionFilter.CreatedTo != nil {
+		timestamp["$lte"] = auctionFilter.CreatedTo.Unix()
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+
+	// end_time follows the extensions, so extended auctions match by their
+	// current end.
+	endTime := bson.M{}
+	if auctionFilter.EndingAfter != nil {
+		endTime["$gte"] = auctionFilter.EndingAfter.Unix()
+	}
+	if auctionFilter.EndingBefore != nil {
+		endTime["$lte"] = auctionFilter.EndingBefore.Unix()
+	}
+	if len(endTime) > 0 {
+		filter["end_time"] = endTime
+	}
+
+	return filter
+}
+
+func toAuctionEntities(auctionsMongo []AuctionEntityMongo) []auction_entity.Auction {
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, *toAuctionEntity(auction))
+	}
+
+	return auctionsEntity
+}
+
+func toAuctionEntity(auction AuctionEntityMongo) *auction_entity.Auction {
+	return &auction_entity.Auction{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Status:       auction.Status,
+		Description:  auction.Description,
+		Condition:    auction.Condition,
+		SellerId:     auction.SellerId,
+		CurrentPrice: money_entity.New(auction.CurrentPrice, money_entity.Currency(auction.Currency)),
+		LeadingBidId: auction.LeadingBidId,
+		LeaderId:     auction.LeaderId,
+		Timestamp:    time.Unix(auction.Timestamp, 0),
+		EndTime:      endTime(auction.EndTime),
+	}
+}
+
+func endTime(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(unix, 0)
+}
+
+func (ar *AuctionRepository) FindAuctionsAfter(
+	ctx context.Context, afterId string, limit int64) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.FindAuctionsAfter")
+	defer span.End()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"_id": bson.M{"$gt": afterId}}, opts)
 	if err != nil {
-		logger.Error("Error finding auctions", err)
-		return nil, internal_error.NewInternalServerError("Error finding auctions")
+		logger.Error("Error trying to find auctions", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions")
 	}
 	defer cursor.Close(ctx)
 
 	var auctionsMongo []AuctionEntityMongo
 	if err := cursor.All(ctx, &auctionsMongo); err != nil {
-		logger.Error("Error decoding auctions", err)
-		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+		logger.Error("Error trying to decode auctions", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions")
 	}
 
-	var auctionsEntity []auction_entity.Auction
-	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
-	}
-
-	return auctionsEntity, nil
+	return toAuctionEntities(auctionsMongo), nil
+}
+
+// auctionSortKey maps the requested ordering to the sort field and direction.
+// The highest bid order is only meaningful within a currency, which the use
+// case requires for it.
+func auctionSortKey(sort auction_entity.AuctionSort) (string, string, int) {
+	switch sort {
+	case auction_entity.SortEndingSoonest:
+		return string(sort), "end_time", 1
+	case auction_entity.SortHighestBid:
+		return string(sort), "current_price", -1
+	default:
+		return string(auction_entity.SortNewest), "timestamp", -1
+	}
+}
+
+func (am AuctionEntityMongo) sortValue(field string) interface{} {
+	switch field {
+	case "current_price":
+		return am.CurrentPrice
+	case "end_time":
+		return am.EndTime
+	}
+
+	return am.Timestamp
 }
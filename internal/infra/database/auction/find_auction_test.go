@@ -0,0 +1,172 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func auctionDocument(id string, timestamp int64) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "product_name", Value: "Product " + id},
+		{Key: "category", Value: "Tech"},
+		{Key: "description", Value: "A product description"},
+		{Key: "condition", Value: int32(auction_entity.New)},
+		{Key: "status", Value: int32(auction_entity.Active)},
+		{Key: "current_price", Value: int64(0)},
+		{Key: "currency", Value: "BRL"},
+		{Key: "timestamp", Value: timestamp},
+	}
+}
+
+func TestFindAuctions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return a next cursor when there are more auctions than the limit", func(mt *mtest.T) {
+		now := time.Now().Unix()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch,
+				auctionDocument("3", now),
+				auctionDocument("2", now-1),
+				auctionDocument("1", now-2)),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		auctions, pageInfo, err := repo.FindAuctions(context.Background(),
+			auction_entity.AuctionQuery{}, pagination_entity.PageRequest{Limit: 2})
+
+		require.Nil(mt, err)
+		require.Len(mt, auctions, 2)
+		assert.Equal(mt, "3", auctions[0].Id)
+		assert.Equal(mt, "2", auctions[1].Id)
+		assert.True(mt, pageInfo.HasMore)
+		assert.NotEmpty(mt, pageInfo.NextCursor)
+		assert.Nil(mt, pageInfo.Total)
+	})
+
+	mt.Run("should not return a next cursor on the last page", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch,
+				auctionDocument("1", time.Now().Unix())),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		auctions, pageInfo, err := repo.FindAuctions(context.Background(),
+			auction_entity.AuctionQuery{}, pagination_entity.PageRequest{})
+
+		require.Nil(mt, err)
+		assert.Len(mt, auctions, 1)
+		assert.False(mt, pageInfo.HasMore)
+		assert.Empty(mt, pageInfo.NextCursor)
+		assert.Equal(mt, pagination_entity.DefaultLimit, pageInfo.Limit)
+	})
+
+	mt.Run("should return bad request when the cursor is invalid", func(mt *mtest.T) {
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		_, _, err := repo.FindAuctions(context.Background(),
+			auction_entity.AuctionQuery{}, pagination_entity.PageRequest{Cursor: "invalid"})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "bad_request", err.Err)
+	})
+
+	mt.Run("should return internal error when Find fails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "find error"}))
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		_, _, err := repo.FindAuctions(context.Background(),
+			auction_entity.AuctionQuery{}, pagination_entity.PageRequest{})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "Error finding auctions", err.Message)
+	})
+}
+
+func TestFindAuctionById(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the price in its currency and the leading bid", func(mt *mtest.T) {
+		document := append(auctionDocument("1", time.Now().Unix()),
+			bson.E{Key: "leading_bid_id", Value: "bid-1"})
+		document[6] = bson.E{Key: "current_price", Value: int64(4990)}
+		document[7] = bson.E{Key: "currency", Value: "USD"}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, document))
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		auction, err := repo.FindAuctionById(context.Background(), "1")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, money_entity.New(4990, "USD"), auction.CurrentPrice)
+		assert.Equal(mt, "bid-1", auction.LeadingBidId)
+	})
+
+	mt.Run("should return not found when the auction does not exist", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch))
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		_, err := repo.FindAuctionById(context.Background(), "1")
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
+
+func TestFindAuctionsAfter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the auctions with their projected end", func(mt *mtest.T) {
+		extended := append(auctionDocument("2", 1700000000), bson.E{Key: "end_time", Value: int64(1700003600)})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch,
+			extended, auctionDocument("3", 1700000000)))
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		auctions, err := repo.FindAuctionsAfter(context.Background(), "1", 2)
+
+		require.Nil(mt, err)
+		require.Len(mt, auctions, 2)
+		assert.Equal(mt, time.Unix(1700003600, 0), auctions[0].EndsAt(time.Minute))
+		assert.Equal(mt, time.Unix(1700000060, 0), auctions[1].EndsAt(time.Minute))
+	})
+}
+
+func TestBuildAuctionFilter(t *testing.T) {
+	t.Run("should match the ending window on the current end of the auctions", func(t *testing.T) {
+		after := time.Unix(1700000000, 0)
+		before := after.Add(time.Hour)
+
+		filter := buildAuctionFilter(auction_entity.AuctionFilter{EndingAfter: &after, EndingBefore: &before})
+
+		assert.Equal(t, bson.M{"$gte": after.Unix(), "$lte": before.Unix()}, filter["end_time"])
+		assert.NotContains(t, filter, "timestamp")
+	})
+
+	t.Run("should keep the creation bounds on the timestamp", func(t *testing.T) {
+		from := time.Unix(1700000000, 0)
+
+		filter := buildAuctionFilter(auction_entity.AuctionFilter{CreatedFrom: &from})
+
+		assert.Equal(t, bson.M{"$gte": from.Unix()}, filter["timestamp"])
+		assert.NotContains(t, filter, "end_time")
+	})
+}
+
+func TestAuctionSortKey(t *testing.T) {
+	t.Run("should order the auctions ending soonest by their current end", func(t *testing.T) {
+		name, field, direction := auctionSortKey(auction_entity.SortEndingSoonest)
+
+		assert.Equal(t, "ending_soonest", name)
+		assert.Equal(t, "end_time", field)
+		assert.Equal(t, 1, direction)
+	})
+}
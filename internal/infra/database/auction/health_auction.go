@@ -0,0 +1,55 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// scheduleClose records that auctionId is due to close at endTime.
+func (ar *AuctionRepository) scheduleClose(auctionId string, endTime time.Time) {
+	ar.pendingClosesMutex.Lock()
+	defer ar.pendingClosesMutex.Unlock()
+
+	if ar.pendingCloses == nil {
+		ar.pendingCloses = make(map[string]time.Time)
+	}
+	ar.pendingCloses[auctionId] = endTime
+}
+
+func (ar *AuctionRepository) unscheduleClose(auctionId string) {
+	ar.pendingClosesMutex.Lock()
+	defer ar.pendingClosesMutex.Unlock()
+
+	delete(ar.pendingCloses, auctionId)
+}
+
+// CheckHealth reports the automatic closing as stalled once an auction is
+// still waiting for its close HEALTH_STALL_TIMEOUT after its end.
+func (ar *AuctionRepository) CheckHealth(ctx context.Context) *internal_error.InternalError {
+	deadline := time.Now().Add(-getStallTimeout())
+
+	ar.pendingClosesMutex.Lock()
+	defer ar.pendingClosesMutex.Unlock()
+
+	for auctionId, endTime := range ar.pendingCloses {
+		if endTime.Before(deadline) {
+			return internal_error.NewInternalServerError(fmt.Sprintf(
+				"closing stalled: auction %s was due to close at %s", auctionId, endTime.Format(time.RFC3339)))
+		}
+	}
+
+	return nil
+}
+
+func getStallTimeout() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("HEALTH_STALL_TIMEOUT"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
@@ -0,0 +1,35 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHealth(t *testing.T) {
+	t.Setenv("HEALTH_STALL_TIMEOUT", "1m")
+
+	t.Run("should pass while the auctions wait for their end", func(t *testing.T) {
+		repo := &AuctionRepository{}
+		assert.Nil(t, repo.CheckHealth(context.Background()))
+
+		repo.scheduleClose("auction-1", time.Now().Add(time.Minute))
+		repo.scheduleClose("auction-2", time.Now().Add(-30*time.Second))
+		assert.Nil(t, repo.CheckHealth(context.Background()))
+	})
+
+	t.Run("should fail once a close is overdue past the stall timeout", func(t *testing.T) {
+		repo := &AuctionRepository{}
+		repo.scheduleClose("auction-1", time.Now().Add(-2*time.Minute))
+
+		err := repo.CheckHealth(context.Background())
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Message, "auction auction-1 was due to close")
+		}
+
+		repo.unscheduleClose("auction-1")
+		assert.Nil(t, repo.CheckHealth(context.Background()))
+	})
+}
@@ -0,0 +1,126 @@
+package auction
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const relevanceSort = "relevance"
+
+type auctionSearchResultMongo struct {
+	AuctionEntityMongo `bson:",inline"`
+	Score              float64 `bson:"score"`
+}
+
+// SearchAuctions runs a $text query over the auctions text index and returns the
+// matches ordered by relevance. The score is projected into the documents so the
+// keyset cursor can resume after the last (score, _id) pair of the page.
+func (ar *AuctionRepository) SearchAuctions(
+	ctx context.Context,
+	search auction_entity.AuctionSearch,
+	page pagination_entity.PageRequest) ([]auction_entity.Auction, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.SearchAuctions")
+	defer span.End()
+
+	expression := textSearchExpression(search.Text, search.Phrase)
+	if expression == "" {
+		return nil, nil, internal_error.NewBadRequestError("Search text must contain at least one word")
+	}
+
+	filter := buildAuctionFilter(search.AuctionFilter)
+	filter["$text"] = bson.M{"$search": expression}
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, relevanceSort)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	pipeline := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+	}
+	if cursor != nil {
+		pipeline = append(pipeline, bson.M{"$match": pagination.KeysetFilter("score", -1, cursor)})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$sort": pagination.SortOptions("score", -1)},
+		bson.M{"$limit": limit + 1},
+	)
+
+	mongoCursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error searching auctions", err)
+		return nil, nil, internal_error.NewInternalServerError("Error searching auctions")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var results []auctionSearchResultMongo
+	if err := mongoCursor.All(ctx, &results); err != nil {
+		logger.Error("Error decoding auctions", err)
+		return nil, nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(results)) > limit {
+		results = results[:limit]
+		last := results[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(relevanceSort, last.Score, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := ar.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error counting auctions", err)
+			return nil, nil, internal_error.NewInternalServerError("Error counting auctions")
+		}
+		pageInfo.Total = &total
+	}
+
+	auctionsMongo := make([]AuctionEntityMongo, 0, len(results))
+	for _, result := range results {
+		auctionsMongo = append(auctionsMongo, result.AuctionEntityMongo)
+	}
+
+	return toAuctionEntities(auctionsMongo), pageInfo, nil
+}
+
+// textSearchExpression turns raw user input into a $search string that is always
+// matched literally: quotes and backslashes are dropped and leading hyphens are
+// removed, so users cannot build phrases or negations by themselves. Phrase
+// searches wrap the whole text in quotes to match it as a single sentence.
+func textSearchExpression(text string, phrase bool) string {
+	cleaned := strings.NewReplacer(`"`, " ", `\`, " ").Replace(text)
+
+	var words []string
+	for _, word := range strings.Fields(cleaned) {
+		word = strings.TrimLeft(word, "-")
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	if len(words) == 0 {
+		return ""
+	}
+
+	if phrase {
+		return `"` + strings.Join(words, " ") + `"`
+	}
+
+	return strings.Join(words, " ")
+}
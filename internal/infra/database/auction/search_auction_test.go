@@ -0,0 +1,66 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestTextSearchExpression(t *testing.T) {
+	t.Run("should keep plain words", func(t *testing.T) {
+		assert.Equal(t, "mola maluca", textSearchExpression("  mola   maluca ", false))
+	})
+
+	t.Run("should drop quotes, backslashes and negations from user input", func(t *testing.T) {
+		assert.Equal(t, "mola maluca", textSearchExpression(`"mola" -maluca \`, false))
+	})
+
+	t.Run("should wrap phrase searches in quotes", func(t *testing.T) {
+		assert.Equal(t, `"mola maluca"`, textSearchExpression(`mola "maluca"`, true))
+	})
+
+	t.Run("should return empty when there are no words left", func(t *testing.T) {
+		assert.Empty(t, textSearchExpression(`" - \`, true))
+	})
+}
+
+func TestSearchAuctions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return matches and a relevance cursor when there are more results", func(mt *mtest.T) {
+		now := time.Now().Unix()
+		first := append(auctionDocument("1", now), bson.E{Key: "score", Value: 2.5})
+		second := append(auctionDocument("2", now), bson.E{Key: "score", Value: 1.5})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, first, second),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		auctions, pageInfo, err := repo.SearchAuctions(context.Background(),
+			auction_entity.AuctionSearch{Text: "product"}, pagination_entity.PageRequest{Limit: 1})
+
+		require.Nil(mt, err)
+		require.Len(mt, auctions, 1)
+		assert.Equal(mt, "1", auctions[0].Id)
+		assert.True(mt, pageInfo.HasMore)
+		assert.NotEmpty(mt, pageInfo.NextCursor)
+	})
+
+	mt.Run("should return bad request when the search has no words", func(mt *mtest.T) {
+		repo := &AuctionRepository{Collection: mt.Coll}
+
+		_, _, err := repo.SearchAuctions(context.Background(),
+			auction_entity.AuctionSearch{Text: `""`}, pagination_entity.PageRequest{})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "bad_request", err.Err)
+	})
+}
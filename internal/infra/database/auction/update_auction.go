@@ -0,0 +1,215 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UpdateLeadingBid makes the bid the leader of the auction when it beats the
+// current price in the auction currency, keeping the field usable for sorting
+// by highest bid. It returns the bid that led before, if any, and whether the
+// bid is now the leader.
+//
+// A bid already made the leader by a projection of the ledger stays the leader,
+// without a previous bid, as the one it replaced is no longer known.
+func (ar *AuctionRepository) UpdateLeadingBid(
+	ctx context.Context,
+	auctionId string,
+	leadingBid auction_entity.LeadingBid) (*auction_entity.LeadingBid, bool, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.UpdateLeadingBid")
+	defer span.End()
+
+	filter := bson.M{
+		"_id":      auctionId,
+		"currency": leadingBid.Amount.Currency,
+		"$or": bson.A{
+			bson.M{"current_price": bson.M{"$lt": leadingBid.Amount.Amount}},
+			bson.M{"leading_bid_id": leadingBid.BidId},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"current_price":  leadingBid.Amount.Amount,
+		"leading_bid_id": leadingBid.BidId,
+		"leader_id":      leadingBid.UserId,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previousLeader *auction_entity.LeadingBid
+	leading := false
+	err := ar.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		var previous AuctionEntityMongo
+		err := ar.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		if err != nil {
+			logger.Error("Error trying to update auction leading bid", err)
+			return nil, internal_error.NewInternalServerError("Error trying to update auction leading bid")
+		}
+
+		leading = true
+		if previous.LeadingBidId != "" && previous.LeadingBidId != leadingBid.BidId {
+			previousLeader = &auction_entity.LeadingBid{
+				BidId:  previous.LeadingBidId,
+				UserId: previous.LeaderId,
+				Amount: money_entity.New(previous.CurrentPrice, money_entity.Currency(previous.Currency)),
+			}
+		}
+		return []outbox_entity.Event{outbox_entity.NewLeaderChanged(auctionId, leadingBid, previousLeader)}, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return previousLeader, leading, nil
+}
+
+func (ar *AuctionRepository) UpdateProjection(
+	ctx context.Context,
+	id string,
+	expectedLeaderId string,
+	leader *auction_entity.LeadingBid,
+	endTime time.Time) (bool, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.UpdateProjection")
+	defer span.End()
+
+	// A null leader also matches auctions that never had one.
+	filter := bson.M{"_id": id, "leading_bid_id": expectedLeaderId}
+	if expectedLeaderId == "" {
+		filter["leading_bid_id"] = nil
+	}
+
+	var price int64
+	var leaderId string
+	set := bson.M{"end_time": endTime.Unix()}
+	update := bson.M{"$set": set}
+	if leader != nil {
+		price, leaderId = leader.Amount.Amount, leader.BidId
+		set["leading_bid_id"] = leader.BidId
+		set["leader_id"] = leader.UserId
+	} else {
+		update["$unset"] = bson.M{"leading_bid_id": "", "leader_id": ""}
+	}
+	set["current_price"] = price
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previous AuctionEntityMongo
+	matched := true
+	err := ar.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		err := ar.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			matched = false
+			return nil, nil
+		}
+		if err != nil {
+			logger.Error("Error trying to update auction projection", err)
+			return nil, internal_error.NewInternalServerError("Error trying to update auction projection")
+		}
+
+		if leader == nil || previous.LeadingBidId == leaderId {
+			return nil, nil
+		}
+		var previousLeader *auction_entity.LeadingBid
+		if previous.LeadingBidId != "" {
+			previousLeader = &auction_entity.LeadingBid{
+				BidId:  previous.LeadingBidId,
+				UserId: previous.LeaderId,
+				Amount: money_entity.New(previous.CurrentPrice, money_entity.Currency(previous.Currency)),
+			}
+		}
+		return []outbox_entity.Event{outbox_entity.NewLeaderChanged(id, *leader, previousLeader)}, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if !matched {
+		if _, findErr := ar.FindAuctionById(ctx, id); findErr != nil {
+			return false, findErr
+		}
+		return false, internal_error.NewConflictError(
+			fmt.Sprintf("Auction %s got a new leader while it was projected", id))
+	}
+
+	leaderChanged := previous.LeadingBidId != leaderId
+
+	endTimeChanged := previous.EndTime != endTime.Unix()
+	if endTimeChanged {
+		ar.notifyEndTimeChange(id, endTime)
+	}
+
+	return leaderChanged || endTimeChanged || previous.CurrentPrice != price, nil
+}
+
+// UpdateAuctionStatus moves the auction to status to only when it currently is in
+// one of the from statuses, so concurrent changes cannot skip a transition.
+func (ar *AuctionRepository) UpdateAuctionStatus(
+	ctx context.Context,
+	id string,
+	from []auction_entity.AuctionStatus,
+	to auction_entity.AuctionStatus) (*auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionRepository.UpdateAuctionStatus")
+	defer span.End()
+
+	filter := bson.M{"_id": id, "status": bson.M{"$in": from}}
+	update := bson.M{"$set": bson.M{"status": to}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var auctionEntityMongo AuctionEntityMongo
+	matched := true
+	err := ar.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		err := ar.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&auctionEntityMongo)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			matched = false
+			return nil, nil
+		}
+		if err != nil {
+			logger.Error("Error trying to update auction status", err)
+			return nil, internal_error.NewInternalServerError("Error trying to update auction status")
+		}
+
+		if event, ok := outbox_entity.NewStatusChanged(id, to); ok {
+			return []outbox_entity.Event{event}, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !matched {
+		return nil, ar.statusConflict(ctx, id, to)
+	}
+
+	ar.notifyStatusChange(id, to)
+
+	return toAuctionEntity(auctionEntityMongo), nil
+}
+
+// statusConflict explains why a status change did not match any auction.
+func (ar *AuctionRepository) statusConflict(
+	ctx context.Context, id string, to auction_entity.AuctionStatus) *internal_error.InternalError {
+	var current AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&current)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return internal_error.NewNotFoundError(fmt.Sprintf("Auction not found with this id = %s", id))
+	}
+	if err != nil {
+		logger.Error("Error trying to find auction by id", err)
+		return internal_error.NewInternalServerError("Error trying to find auction by id")
+	}
+
+	return internal_error.NewConflictError(fmt.Sprintf(
+		"Auction cannot become %s while it is %s", to, current.Status))
+}
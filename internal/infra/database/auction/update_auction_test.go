@@ -0,0 +1,209 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUpdateAuctionStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the updated auction, record it and notify listeners", func(mt *mtest.T) {
+		paused := auctionDocument("1", time.Now().Unix())
+		paused[5] = bson.E{Key: "status", Value: int32(auction_entity.Paused)}
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: paused},
+		})
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		var notified auction_entity.AuctionStatus = -1
+		repo.OnStatusChange(func(auctionId string, status auction_entity.AuctionStatus) {
+			notified = status
+		})
+
+		from, to, _ := auction_entity.ActionPause.Transition()
+		auction, err := repo.UpdateAuctionStatus(context.Background(), "1", from, to)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, auction_entity.Paused, auction.Status)
+		assert.Equal(mt, auction_entity.Paused, notified)
+
+		events := outbox.recorded()
+		require.Len(mt, events, 1)
+		assert.Equal(mt, outbox_entity.AuctionPaused, events[0].Type)
+	})
+
+	mt.Run("should return conflict when the auction is not in an allowed status", func(mt *mtest.T) {
+		completed := auctionDocument("1", time.Now().Unix())
+		completed[5] = bson.E{Key: "status", Value: int32(auction_entity.Completed)}
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, completed),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		from, to, _ := auction_entity.ActionPause.Transition()
+		_, err := repo.UpdateAuctionStatus(context.Background(), "1", from, to)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "conflict", err.Err)
+		assert.Equal(mt, "Auction cannot become paused while it is completed", err.Message)
+	})
+
+	mt.Run("should return not found when the auction does not exist", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		from, to, _ := auction_entity.ActionCancel.Transition()
+		_, err := repo.UpdateAuctionStatus(context.Background(), "1", from, to)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
+
+func TestUpdateLeadingBid(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	bid := auction_entity.LeadingBid{BidId: "bid-2", UserId: "user-2", Amount: money_entity.New(15000, "BRL")}
+
+	mt.Run("should return the bid that led before", func(mt *mtest.T) {
+		previous := append(auctionDocument("1", time.Now().Unix()),
+			bson.E{Key: "leading_bid_id", Value: "bid-1"},
+			bson.E{Key: "leader_id", Value: "user-1"})
+		previous[6] = bson.E{Key: "current_price", Value: int64(10000)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: previous}})
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		leader, leading, err := repo.UpdateLeadingBid(context.Background(), "1", bid)
+
+		require.Nil(mt, err)
+		assert.True(mt, leading)
+		assert.Equal(mt, &auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1", Amount: money_entity.New(10000, "BRL")}, leader)
+
+		events := outbox.recorded()
+		require.Len(mt, events, 1)
+		var data outbox_entity.LeaderData
+		require.Nil(mt, events[0].Decode(&data))
+		assert.Equal(mt, outbox_entity.LeaderChanged, events[0].Type)
+		assert.Equal(mt, "bid-1", data.PreviousBidId)
+	})
+
+	mt.Run("should lead without a previous bid", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: auctionDocument("1", time.Now().Unix())}})
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		leader, leading, err := repo.UpdateLeadingBid(context.Background(), "1", bid)
+
+		require.Nil(mt, err)
+		assert.True(mt, leading)
+		assert.Nil(mt, leader)
+	})
+
+	mt.Run("should not lead when the current price is not beaten", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		leader, leading, err := repo.UpdateLeadingBid(context.Background(), "1", bid)
+
+		require.Nil(mt, err)
+		assert.False(mt, leading)
+		assert.Nil(mt, leader)
+		assert.Empty(mt, outbox.recorded())
+	})
+}
+
+func TestUpdateProjection(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	endTime := time.Unix(1700003600, 0)
+
+	mt.Run("should record the new leader and notify the new end", func(mt *mtest.T) {
+		previous := append(auctionDocument("1", 1700000000),
+			bson.E{Key: "leading_bid_id", Value: "bid-2"},
+			bson.E{Key: "leader_id", Value: "user-2"},
+			bson.E{Key: "end_time", Value: int64(1700000120)})
+		previous[6] = bson.E{Key: "current_price", Value: int64(15000)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: previous}})
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		var notified time.Time
+		repo.OnEndTimeChange(func(auctionId string, endTime time.Time) {
+			notified = endTime
+		})
+
+		leader := &auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1", Amount: money_entity.New(10000, "BRL")}
+		changed, err := repo.UpdateProjection(context.Background(), "1", "bid-2", leader, endTime)
+
+		require.Nil(mt, err)
+		assert.True(mt, changed)
+		assert.Equal(mt, endTime, notified)
+
+		events := outbox.recorded()
+		require.Len(mt, events, 1)
+		var data outbox_entity.LeaderData
+		require.Nil(mt, events[0].Decode(&data))
+		assert.Equal(mt, outbox_entity.LeaderChanged, events[0].Type)
+		assert.Equal(mt, "bid-2", data.PreviousBidId)
+	})
+
+	mt.Run("should report no change when the auction already matches", func(mt *mtest.T) {
+		previous := append(auctionDocument("1", 1700000000),
+			bson.E{Key: "leading_bid_id", Value: "bid-1"},
+			bson.E{Key: "leader_id", Value: "user-1"},
+			bson.E{Key: "end_time", Value: endTime.Unix()})
+		previous[6] = bson.E{Key: "current_price", Value: int64(10000)}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: previous}})
+		outbox := &recordingOutbox{}
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: outbox}
+
+		leader := &auction_entity.LeadingBid{BidId: "bid-1", UserId: "user-1", Amount: money_entity.New(10000, "BRL")}
+		changed, err := repo.UpdateProjection(context.Background(), "1", "bid-1", leader, endTime)
+
+		require.Nil(mt, err)
+		assert.False(mt, changed)
+		assert.Empty(mt, outbox.recorded())
+	})
+
+	mt.Run("should return conflict when another bid took the lead", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch, auctionDocument("1", 1700000000)),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		_, err := repo.UpdateProjection(context.Background(), "1", "bid-1", nil, endTime)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "conflict", err.Err)
+	})
+
+	mt.Run("should return not found when the auction does not exist", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "testdb.auctions", mtest.FirstBatch),
+		)
+		repo := &AuctionRepository{Collection: mt.Coll, Outbox: &recordingOutbox{}}
+
+		_, err := repo.UpdateProjection(context.Background(), "1", "", nil, endTime)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
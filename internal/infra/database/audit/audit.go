@@ -0,0 +1,283 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appendAttempts bounds the retries of an append that lost its sequence to
+// another instance.
+const appendAttempts = 20
+
+type EntryEntityMongo struct {
+	Id           string `bson:"_id"`
+	Sequence     int64  `bson:"sequence"`
+	ActorType    string `bson:"actor_type"`
+	UserId       string `bson:"user_id,omitempty"`
+	ApiKeyId     string `bson:"api_key_id,omitempty"`
+	Action       string `bson:"action"`
+	TargetType   string `bson:"target_type"`
+	TargetId     string `bson:"target_id,omitempty"`
+	Before       string `bson:"before,omitempty"`
+	After        string `bson:"after,omitempty"`
+	RequestId    string `bson:"request_id,omitempty"`
+	ClientIP     string `bson:"client_ip,omitempty"`
+	RemoteIP     string `bson:"remote_ip,omitempty"`
+	Status       int    `bson:"status,omitempty"`
+	OccurredAt   int64  `bson:"occurred_at"`
+	PreviousHash string `bson:"previous_hash"`
+	Hash         string `bson:"hash"`
+}
+
+func (em EntryEntityMongo) toEntryEntity() audit_entity.Entry {
+	return audit_entity.Entry{
+		Id:       em.Id,
+		Sequence: em.Sequence,
+		Actor: audit_entity.Actor{
+			Type:     audit_entity.ActorType(em.ActorType),
+			UserId:   em.UserId,
+			ApiKeyId: em.ApiKeyId,
+		},
+		Action:       em.Action,
+		TargetType:   em.TargetType,
+		TargetId:     em.TargetId,
+		Before:       em.Before,
+		After:        em.After,
+		RequestId:    em.RequestId,
+		ClientIP:     em.ClientIP,
+		RemoteIP:     em.RemoteIP,
+		Status:       em.Status,
+		OccurredAt:   time.Unix(em.OccurredAt, 0),
+		PreviousHash: em.PreviousHash,
+		Hash:         em.Hash,
+	}
+}
+
+// AuditRepository keeps the audit log in one collection, with a unique
+// sequence. It only inserts: the log is never updated nor pruned.
+type AuditRepository struct {
+	Collection *mongo.Collection
+
+	// appendMutex serializes the appends of this instance, which would
+	// otherwise race for the same sequence.
+	appendMutex sync.Mutex
+}
+
+func NewAuditRepository(database *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		Collection: database.Collection("audit_log"),
+	}
+}
+
+func (ar *AuditRepository) AppendEntry(
+	ctx context.Context, entry audit_entity.Entry) (*audit_entity.Entry, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuditRepository.AppendEntry")
+	defer span.End()
+
+	ar.appendMutex.Lock()
+	defer ar.appendMutex.Unlock()
+
+	for attempt := 0; attempt < appendAttempts; attempt++ {
+		sequence, previousHash, err := ar.last(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entry.Seal(sequence+1, previousHash)
+
+		_, insertErr := ar.Collection.InsertOne(ctx, toEntryEntityMongo(entry))
+		if mongo.IsDuplicateKeyError(insertErr) {
+			continue
+		}
+		if insertErr != nil {
+			logger.Error("Error trying to append audit entry", insertErr)
+			return nil, internal_error.NewInternalServerError("Error trying to append audit entry")
+		}
+
+		return &entry, nil
+	}
+
+	logger.Error(fmt.Sprintf("Error trying to append audit entry %s", entry.Action),
+		errors.New("too many concurrent appends"))
+	return nil, internal_error.NewInternalServerError("Error trying to append audit entry")
+}
+
+func toEntryEntityMongo(entry audit_entity.Entry) *EntryEntityMongo {
+	return &EntryEntityMongo{
+		Id:           entry.Id,
+		Sequence:     entry.Sequence,
+		ActorType:    string(entry.Actor.Type),
+		UserId:       entry.Actor.UserId,
+		ApiKeyId:     entry.Actor.ApiKeyId,
+		Action:       entry.Action,
+		TargetType:   entry.TargetType,
+		TargetId:     entry.TargetId,
+		Before:       entry.Before,
+		After:        entry.After,
+		RequestId:    entry.RequestId,
+		ClientIP:     entry.ClientIP,
+		RemoteIP:     entry.RemoteIP,
+		Status:       entry.Status,
+		OccurredAt:   entry.OccurredAt.Unix(),
+		PreviousHash: entry.PreviousHash,
+		Hash:         entry.Hash,
+	}
+}
+
+// last returns the sequence and the hash of the last entry of the chain.
+func (ar *AuditRepository) last(ctx context.Context) (int64, string, *internal_error.InternalError) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "sequence", Value: -1}}).
+		SetProjection(bson.M{"sequence": 1, "hash": 1})
+
+	var entryMongo EntryEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{}, opts).Decode(&entryMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, "", nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find the last audit entry", err)
+		return 0, "", internal_error.NewInternalServerError("Error trying to find the last audit entry")
+	}
+
+	return entryMongo.Sequence, entryMongo.Hash, nil
+}
+
+func (ar *AuditRepository) FindEntries(
+	ctx context.Context,
+	filter audit_entity.EntryFilter,
+	page pagination_entity.PageRequest) ([]audit_entity.Entry, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuditRepository.FindEntries")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "sequence", -1
+
+	mongoFilter := buildEntryFilter(filter)
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := ar.Collection.Find(
+		ctx, pagination.WithCursor(mongoFilter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find audit entries", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find audit entries")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var entriesMongo []EntryEntityMongo
+	if err := mongoCursor.All(ctx, &entriesMongo); err != nil {
+		logger.Error("Error trying to decode audit entries", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find audit entries")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(entriesMongo)) > limit {
+		entriesMongo = entriesMongo[:limit]
+		last := entriesMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.Sequence, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := ar.Collection.CountDocuments(ctx, mongoFilter)
+		if err != nil {
+			logger.Error("Error trying to count audit entries", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count audit entries")
+		}
+		pageInfo.Total = &total
+	}
+
+	entries := make([]audit_entity.Entry, 0, len(entriesMongo))
+	for _, entryMongo := range entriesMongo {
+		entries = append(entries, entryMongo.toEntryEntity())
+	}
+
+	return entries, pageInfo, nil
+}
+
+func buildEntryFilter(filter audit_entity.EntryFilter) bson.M {
+	mongoFilter := bson.M{}
+	if filter.UserId != "" {
+		mongoFilter["user_id"] = filter.UserId
+	}
+	if filter.Action != "" {
+		mongoFilter["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		mongoFilter["target_type"] = filter.TargetType
+	}
+	if filter.TargetId != "" {
+		mongoFilter["target_id"] = filter.TargetId
+	}
+	if filter.RequestId != "" {
+		mongoFilter["request_id"] = filter.RequestId
+	}
+
+	occurredAt := bson.M{}
+	if filter.From != nil {
+		occurredAt["$gte"] = filter.From.Unix()
+	}
+	if filter.To != nil {
+		occurredAt["$lte"] = filter.To.Unix()
+	}
+	if len(occurredAt) > 0 {
+		mongoFilter["occurred_at"] = occurredAt
+	}
+
+	return mongoFilter
+}
+
+func (ar *AuditRepository) FindChain(
+	ctx context.Context, afterSequence int64, limit int64) ([]audit_entity.Entry, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuditRepository.FindChain")
+	defer span.End()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"sequence": bson.M{"$gt": afterSequence}}, opts)
+	if err != nil {
+		logger.Error("Error trying to find the audit chain", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the audit chain")
+	}
+	defer cursor.Close(ctx)
+
+	var entriesMongo []EntryEntityMongo
+	if err := cursor.All(ctx, &entriesMongo); err != nil {
+		logger.Error("Error trying to decode the audit chain", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the audit chain")
+	}
+
+	entries := make([]audit_entity.Entry, 0, len(entriesMongo))
+	for _, entryMongo := range entriesMongo {
+		entries = append(entries, entryMongo.toEntryEntity())
+	}
+
+	return entries, nil
+}
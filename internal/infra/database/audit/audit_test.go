@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAppendEntry(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	entry := audit_entity.NewEntry(context.Background(), "auction.close", "auction", "auction-1", nil, nil)
+
+	mt.Run("should chain the entry after the last one", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.audit_log", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "entry-7"}, {Key: "sequence", Value: int64(7)}, {Key: "hash", Value: "abc"}}),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &AuditRepository{Collection: mt.Coll}
+
+		appended, err := repo.AppendEntry(context.Background(), entry)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, int64(8), appended.Sequence)
+		assert.Equal(mt, "abc", appended.PreviousHash)
+		assert.Nil(mt, audit_entity.VerifyChain(7, "abc", []audit_entity.Entry{*appended}))
+	})
+
+	mt.Run("should start the chain", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.audit_log", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &AuditRepository{Collection: mt.Coll}
+
+		appended, err := repo.AppendEntry(context.Background(), entry)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, int64(1), appended.Sequence)
+		assert.Empty(mt, appended.PreviousHash)
+	})
+
+	mt.Run("should chain again after another instance took the sequence", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.audit_log", mtest.FirstBatch),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"}),
+			mtest.CreateCursorResponse(0, "testdb.audit_log", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "entry-1"}, {Key: "sequence", Value: int64(1)}, {Key: "hash", Value: "def"}}),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &AuditRepository{Collection: mt.Coll}
+
+		appended, err := repo.AppendEntry(context.Background(), entry)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, int64(2), appended.Sequence)
+		assert.Equal(mt, "def", appended.PreviousHash)
+	})
+}
+
+func TestFindEntries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	entry := func(id string, sequence int64) bson.D {
+		return bson.D{
+			{Key: "_id", Value: id},
+			{Key: "sequence", Value: sequence},
+			{Key: "actor_type", Value: "user"},
+			{Key: "user_id", Value: "user-1"},
+			{Key: "action", Value: "bid.create"},
+			{Key: "target_type", Value: "bid"},
+			{Key: "occurred_at", Value: int64(1700000000)},
+		}
+	}
+
+	mt.Run("should return a next cursor when there are more entries than the limit", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.audit_log", mtest.FirstBatch,
+			entry("entry-3", 3), entry("entry-2", 2), entry("entry-1", 1)))
+		repo := &AuditRepository{Collection: mt.Coll}
+
+		entries, pageInfo, err := repo.FindEntries(context.Background(),
+			audit_entity.EntryFilter{UserId: "user-1"}, pagination_entity.PageRequest{Limit: 2})
+
+		require.Nil(mt, err)
+		require.Len(mt, entries, 2)
+		assert.Equal(mt, audit_entity.Actor{Type: audit_entity.ActorUser, UserId: "user-1"}, entries[0].Actor)
+		assert.True(mt, pageInfo.HasMore)
+		assert.NotEmpty(mt, pageInfo.NextCursor)
+	})
+}
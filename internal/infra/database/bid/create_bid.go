@@ -2,115 +2,347 @@ package bid
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/auction"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	Amount    int64  `bson:"amount"`
+	Currency  string `bson:"currency"`
+	Timestamp int64  `bson:"timestamp"`
+
+	// RetractedAt is set once the bid is retracted from its auction; retracted
+	// bids are kept but no longer listed nor considered for the lead.
+	RetractedAt int64 `bson:"retracted_at,omitempty"`
+}
+
+func (bm BidEntityMongo) amount() money_entity.Money {
+	return money_entity.New(bm.Amount, money_entity.Currency(bm.Currency))
+}
+
+func (bm BidEntityMongo) toBidEntity() bid_entity.Bid {
+	return bid_entity.Bid{
+		Id:        bm.Id,
+		UserId:    bm.UserId,
+		AuctionId: bm.AuctionId,
+		Amount:    bm.amount(),
+		Timestamp: time.Unix(bm.Timestamp, 0),
+	}
 }
 
 type BidRepository struct {
 	Collection            *mongo.Collection
+	DeadLetterCollection  *mongo.Collection
 	AuctionRepository     *auction.AuctionRepository
+	CreditRepository      credit_entity.CreditRepositoryInterface
+	Outbox                outbox_entity.OutboxRepositoryInterface
+	Ledger                ledger_entity.LedgerRepositoryInterface
+	Metrics               metrics_entity.MetricsInterface
 	auctionInterval       time.Duration
 	auctionStatusMap      map[string]auction_entity.AuctionStatus
 	auctionEndTimeMap     map[string]time.Time
 	auctionStatusMapMutex *sync.Mutex
 	auctionEndTimeMutex   *sync.Mutex
+
+	bidListeners      []BidListener
+	bidListenersMutex sync.RWMutex
 }
 
-func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
-	return &BidRepository{
+// BidListener is notified after a bid is stored. leading tells whether the bid
+// took the lead of the auction from previous, which is nil for the first bid.
+type BidListener func(bid bid_entity.Bid, leading bool, previous *auction_entity.LeadingBid)
+
+func NewBidRepository(
+	database *mongo.Database,
+	auctionRepository *auction.AuctionRepository,
+	creditRepository credit_entity.CreditRepositoryInterface,
+	outboxRepository outbox_entity.OutboxRepositoryInterface,
+	ledgerRepository ledger_entity.LedgerRepositoryInterface,
+	metrics metrics_entity.MetricsInterface) *BidRepository {
+	bidRepository := &BidRepository{
 		auctionInterval:       getAuctionInterval(),
 		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
 		auctionEndTimeMap:     make(map[string]time.Time),
 		auctionStatusMapMutex: &sync.Mutex{},
 		auctionEndTimeMutex:   &sync.Mutex{},
 		Collection:            database.Collection("bids"),
+		DeadLetterCollection:  database.Collection("bids_dead_letter"),
 		AuctionRepository:     auctionRepository,
+		CreditRepository:      creditRepository,
+		Outbox:                outboxRepository,
+		Ledger:                ledgerRepository,
+		Metrics:               metrics,
+	}
+
+	auctionRepository.OnStatusChange(bidRepository.updateAuctionStatusCache)
+	auctionRepository.OnStatusChange(bidRepository.releaseCreditHolds)
+	auctionRepository.OnEndTimeChange(bidRepository.updateAuctionEndTimeCache)
+
+	return bidRepository
+}
+
+// OnBidAccepted registers listener for the bids that passed every check and
+// were stored.
+func (bd *BidRepository) OnBidAccepted(listener BidListener) {
+	bd.bidListenersMutex.Lock()
+	defer bd.bidListenersMutex.Unlock()
+
+	bd.bidListeners = append(bd.bidListeners, listener)
+}
+
+// acceptBid notifies the listeners of a stored bid, once its transaction is
+// committed.
+func (bd *BidRepository) acceptBid(
+	bidEntityMongo *BidEntityMongo, leading bool, previous *auction_entity.LeadingBid) {
+	bid := bidEntityMongo.toBidEntity()
+	bd.Metrics.BidAccepted()
+
+	bd.bidListenersMutex.RLock()
+	defer bd.bidListenersMutex.RUnlock()
+
+	for _, listener := range bd.bidListeners {
+		listener(bid, leading, previous)
+	}
+}
+
+// updateAuctionStatusCache keeps the cached status of auctions already seen by
+// a batch in sync with pauses, resumes and closures.
+func (bd *BidRepository) updateAuctionStatusCache(auctionId string, status auction_entity.AuctionStatus) {
+	bd.auctionStatusMapMutex.Lock()
+	defer bd.auctionStatusMapMutex.Unlock()
+
+	if _, ok := bd.auctionStatusMap[auctionId]; ok {
+		bd.auctionStatusMap[auctionId] = status
+	}
+}
+
+// updateAuctionEndTimeCache keeps the cached end of auctions already seen by a
+// batch in sync with extensions.
+func (bd *BidRepository) updateAuctionEndTimeCache(auctionId string, endTime time.Time) {
+	bd.auctionEndTimeMutex.Lock()
+	defer bd.auctionEndTimeMutex.Unlock()
+
+	if _, ok := bd.auctionEndTimeMap[auctionId]; ok {
+		bd.auctionEndTimeMap[auctionId] = endTime
 	}
 }
 
 func (bd *BidRepository) CreateBid(
 	ctx context.Context,
 	bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "BidRepository.CreateBid")
+	defer span.End()
+
+	// A bid is lost when neither it nor its rejection could be stored; the
+	// others of the batch are still processed.
+	var lost atomic.Int32
 	var wg sync.WaitGroup
 	for _, bid := range bidEntities {
 		wg.Add(1)
 		go func(bidValue bid_entity.Bid) {
 			defer wg.Done()
 
-			bd.auctionStatusMapMutex.Lock()
-			auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
-			bd.auctionStatusMapMutex.Unlock()
+			if err := bd.processBid(ctx, bidValue); err != nil {
+				lost.Add(1)
+			}
+		}(bid)
+	}
+	wg.Wait()
 
-			bd.auctionEndTimeMutex.Lock()
-			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
-			bd.auctionEndTimeMutex.Unlock()
+	if lost := lost.Load(); lost > 0 {
+		return internal_error.NewInternalServerError(
+			fmt.Sprintf("%d of %d bids could not be stored nor dead-lettered", lost, len(bidEntities)))
+	}
+	return nil
+}
 
-			bidEntityMongo := &BidEntityMongo{
-				Id:        bidValue.Id,
-				UserId:    bidValue.UserId,
-				AuctionId: bidValue.AuctionId,
-				Amount:    bidValue.Amount,
-				Timestamp: bidValue.Timestamp.Unix(),
-			}
+// processBid stores the bid, or its rejection when the auction does not take
+// it.
+func (bd *BidRepository) processBid(ctx context.Context, bidValue bid_entity.Bid) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "BidRepository.processBid", trace.WithAttributes(
+		attribute.String("bid.id", bidValue.Id),
+		attribute.String("auction.id", bidValue.AuctionId)))
+	defer span.End()
 
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
+	bd.auctionStatusMapMutex.Lock()
+	auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
+	bd.auctionStatusMapMutex.Unlock()
 
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
+	bd.auctionEndTimeMutex.Lock()
+	auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
+	bd.auctionEndTimeMutex.Unlock()
 
-				return
-			}
+	bidEntityMongo := &BidEntityMongo{
+		Id:        bidValue.Id,
+		UserId:    bidValue.UserId,
+		AuctionId: bidValue.AuctionId,
+		Amount:    bidValue.Amount.Amount,
+		Currency:  string(bidValue.Amount.Currency),
+		Timestamp: bidValue.Timestamp.Unix(),
+	}
 
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
-			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
-				return
-			}
-			if auctionEntity.Status == auction_entity.Completed {
-				return
-			}
+	if okEndTime && okStatus {
+		if reason, detail := rejectBid(auctionStatus, auctionEndTime); reason != "" {
+			return bd.deadLetter(ctx, bidEntityMongo, reason, detail)
+		}
 
-			bd.auctionStatusMapMutex.Lock()
-			bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
-			bd.auctionStatusMapMutex.Unlock()
+		return bd.insertBid(ctx, bidEntityMongo)
+	}
 
-			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
-			bd.auctionEndTimeMutex.Unlock()
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
+	if err != nil {
+		logger.Error("Error trying to find auction by id", err)
+		return bd.deadLetter(ctx, bidEntityMongo, bid_entity.ReasonAuctionLookupFailed, err.Error())
+	}
 
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
-			}
-		}(bid)
+	bd.auctionStatusMapMutex.Lock()
+	bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
+	bd.auctionStatusMapMutex.Unlock()
+
+	auctionEndTime = auctionEntity.EndsAt(bd.auctionInterval)
+	bd.auctionEndTimeMutex.Lock()
+	bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEndTime
+	bd.auctionEndTimeMutex.Unlock()
+
+	if reason, detail := rejectBid(auctionEntity.Status, auctionEndTime); reason != "" {
+		return bd.deadLetter(ctx, bidEntityMongo, reason, detail)
 	}
-	wg.Wait()
+
+	return bd.insertBid(ctx, bidEntityMongo)
+}
+
+// rejectBid returns why a bid cannot be accepted by an auction, or an empty
+// reason when it can.
+func rejectBid(
+	status auction_entity.AuctionStatus, endTime time.Time) (bid_entity.DeadLetterReason, string) {
+	if status != auction_entity.Active {
+		return bid_entity.ReasonAuctionNotActive, fmt.Sprintf("auction is %s", status)
+	}
+
+	if time.Now().After(endTime) {
+		return bid_entity.ReasonAuctionEnded, fmt.Sprintf("auction ended at %s", endTime.Format(time.RFC3339))
+	}
+
+	return "", ""
+}
+
+// insertBid holds the bidder credit before storing the bid, and keeps the hold
+// only while the bid leads the auction. A bid that cannot be stored is
+// dead-lettered instead.
+func (bd *BidRepository) insertBid(ctx context.Context, bidEntityMongo *BidEntityMongo) *internal_error.InternalError {
+	hold := credit_entity.Hold{
+		AuctionId: bidEntityMongo.AuctionId,
+		BidId:     bidEntityMongo.Id,
+		Bid:       bidEntityMongo.amount(),
+	}
+	if err := bd.CreditRepository.HoldCredit(ctx, bidEntityMongo.UserId, hold); err != nil {
+		reason := bid_entity.ReasonCreditHoldFailed
+		switch err.Err {
+		case "conflict":
+			reason = bid_entity.ReasonCreditLimitExceeded
+		case "unprocessable_entity":
+			reason = bid_entity.ReasonExchangeRateMissing
+		}
+		return bd.deadLetter(ctx, bidEntityMongo, reason, err.Error())
+	}
+
+	// The bid, its placement in the ledger, its lead and their events are
+	// stored together, so the ledger never holds a bid that was not stored nor
+	// misses one that was; the ledger and the leader update join the
+	// transaction of the bid.
+	var previous *auction_entity.LeadingBid
+	leading := false
+	err := bd.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
+			logger.Error("Error trying to insert bid", err)
+			return nil, internal_error.NewInternalServerError("Error trying to insert bid")
+		}
+
+		if _, err := bd.Ledger.AppendEvent(ctx, ledger_entity.NewBidPlaced(bidEntityMongo.toBidEntity())); err != nil {
+			return nil, err
+		}
+
+		var err *internal_error.InternalError
+		previous, leading, err = bd.AuctionRepository.UpdateLeadingBid(ctx, bidEntityMongo.AuctionId,
+			auction_entity.LeadingBid{
+				BidId:  bidEntityMongo.Id,
+				UserId: bidEntityMongo.UserId,
+				Amount: bidEntityMongo.amount(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		return []outbox_entity.Event{
+			outbox_entity.NewBidAccepted(bidEntityMongo.toBidEntity(), leading, previous),
+		}, nil
+	})
+	if err != nil {
+		bd.releaseHold(ctx, bidEntityMongo.UserId, bidEntityMongo.AuctionId, bidEntityMongo.Id)
+		return bd.deadLetter(ctx, bidEntityMongo, bid_entity.ReasonInsertFailed, err.Error())
+	}
+
+	if !leading {
+		bd.releaseHold(ctx, bidEntityMongo.UserId, bidEntityMongo.AuctionId, bidEntityMongo.Id)
+	} else if previous != nil {
+		bd.releaseHold(ctx, previous.UserId, bidEntityMongo.AuctionId, previous.BidId)
+	}
+	bd.acceptBid(bidEntityMongo, leading, previous)
 	return nil
 }
 
+// releaseHold frees the credit held for bidId. A failure leaves the credit
+// held until the auction ends, so it is logged with the bid.
+func (bd *BidRepository) releaseHold(ctx context.Context, userId, auctionId, bidId string) {
+	if err := bd.CreditRepository.ReleaseHold(ctx, userId, auctionId, bidId); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to release the credit held for bid %s", bidId), err)
+	}
+}
+
+// releaseCreditHolds frees the credit held in an auction once it ends, except
+// the hold of the winning bid of a completed auction.
+func (bd *BidRepository) releaseCreditHolds(auctionId string, status auction_entity.AuctionStatus) {
+	ctx := context.Background()
+
+	var err *internal_error.InternalError
+	switch status {
+	case auction_entity.Cancelled:
+		err = bd.CreditRepository.ReleaseAuctionHolds(ctx, auctionId, "")
+	case auction_entity.Completed:
+		auctionEntity, findErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+		if findErr != nil {
+			err = findErr
+			break
+		}
+		err = bd.CreditRepository.ReleaseAuctionHolds(ctx, auctionId, auctionEntity.LeadingBidId)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to release the credit held in auction %s", auctionId), err)
+	}
+}
+
 func getAuctionInterval() time.Duration {
 	auctionInterval := os.Getenv("AUCTION_INTERVAL")
 	duration, err := time.ParseDuration(auctionInterval)
@@ -0,0 +1,159 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type DeadLetterBidEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	Amount    int64  `bson:"amount"`
+	Currency  string `bson:"currency"`
+	Timestamp int64  `bson:"timestamp"`
+	Reason    string `bson:"reason"`
+	Detail    string `bson:"detail"`
+	FailedAt  int64  `bson:"failed_at"`
+}
+
+// deadLetter keeps a bid rejected while processing its batch and records the
+// rejection in the ledger, in the outbox and on the span of the bid. The
+// error tells the batch the bid was lost.
+func (bd *BidRepository) deadLetter(
+	ctx context.Context,
+	bidEntityMongo *BidEntityMongo,
+	reason bid_entity.DeadLetterReason,
+	detail string) *internal_error.InternalError {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("bid.dead_letter_reason", string(reason)))
+	span.SetStatus(codes.Error, detail)
+
+	deadLetterMongo := &DeadLetterBidEntityMongo{
+		Id:        bidEntityMongo.Id,
+		UserId:    bidEntityMongo.UserId,
+		AuctionId: bidEntityMongo.AuctionId,
+		Amount:    bidEntityMongo.Amount,
+		Currency:  bidEntityMongo.Currency,
+		Timestamp: bidEntityMongo.Timestamp,
+		Reason:    string(reason),
+		Detail:    detail,
+		FailedAt:  time.Now().Unix(),
+	}
+
+	bid := bidEntityMongo.toBidEntity()
+	err := bd.Outbox.WithEvents(ctx, func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+		if _, err := bd.DeadLetterCollection.InsertOne(ctx, deadLetterMongo); err != nil {
+			logger.Error("Error trying to insert dead-lettered bid", err)
+			return nil, internal_error.NewInternalServerError("Error trying to insert dead-lettered bid")
+		}
+
+		if _, err := bd.Ledger.AppendEvent(ctx, ledger_entity.NewBidRejected(bid, reason, detail)); err != nil {
+			return nil, err
+		}
+
+		return []outbox_entity.Event{outbox_entity.NewBidRejected(bid, reason, detail)}, nil
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to dead-letter bid %s", bid.Id), err)
+		return err
+	}
+
+	bd.Metrics.BidRejected(string(reason))
+	return nil
+}
+
+func (bd *BidRepository) FindDeadLetterBids(
+	ctx context.Context,
+	auctionId string,
+	page pagination_entity.PageRequest) ([]bid_entity.DeadLetterBid, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidRepository.FindDeadLetterBids")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "failed_at", -1
+
+	filter := bson.M{}
+	if auctionId != "" {
+		filter["auction_id"] = auctionId
+	}
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := bd.DeadLetterCollection.Find(
+		ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find dead-lettered bids", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find dead-lettered bids")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var deadLettersMongo []DeadLetterBidEntityMongo
+	if err := mongoCursor.All(ctx, &deadLettersMongo); err != nil {
+		logger.Error("Error trying to decode dead-lettered bids", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find dead-lettered bids")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(deadLettersMongo)) > limit {
+		deadLettersMongo = deadLettersMongo[:limit]
+		last := deadLettersMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.FailedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := bd.DeadLetterCollection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error trying to count dead-lettered bids", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count dead-lettered bids")
+		}
+		pageInfo.Total = &total
+	}
+
+	var deadLetters []bid_entity.DeadLetterBid
+	for _, deadLetterMongo := range deadLettersMongo {
+		deadLetters = append(deadLetters, bid_entity.DeadLetterBid{
+			Bid: bid_entity.Bid{
+				Id:        deadLetterMongo.Id,
+				UserId:    deadLetterMongo.UserId,
+				AuctionId: deadLetterMongo.AuctionId,
+				Amount:    money_entity.New(deadLetterMongo.Amount, money_entity.Currency(deadLetterMongo.Currency)),
+				Timestamp: time.Unix(deadLetterMongo.Timestamp, 0),
+			},
+			Reason:   bid_entity.DeadLetterReason(deadLetterMongo.Reason),
+			Detail:   deadLetterMongo.Detail,
+			FailedAt: time.Unix(deadLetterMongo.FailedAt, 0),
+		})
+	}
+
+	return deadLetters, pageInfo, nil
+}
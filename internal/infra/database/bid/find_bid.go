@@ -2,58 +2,130 @@ package bid
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (bd *BidRepository) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auction_id": auctionId}
+	ctx context.Context,
+	auctionId string,
+	sort bid_entity.BidSort,
+	page pagination_entity.PageRequest) ([]bid_entity.Bid, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidRepository.FindBidByAuctionId")
+	defer span.End()
+
+	filter := bson.M{"auction_id": auctionId, "retracted_at": bson.M{"$exists": false}}
+
+	sortName, sortField, direction := bidSortKey(sort)
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
 
-	cursor, err := bd.Collection.Find(ctx, filter)
+	mongoCursor, err := bd.Collection.Find(ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
 	if err != nil {
 		logger.Error(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
-		return nil, internal_error.NewInternalServerError(
+		return nil, nil, internal_error.NewInternalServerError(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
 	}
+	defer mongoCursor.Close(ctx)
 
 	var bidEntitiesMongo []BidEntityMongo
-	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+	if err := mongoCursor.All(ctx, &bidEntitiesMongo); err != nil {
 		logger.Error(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
-		return nil, internal_error.NewInternalServerError(
+		return nil, nil, internal_error.NewInternalServerError(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
 	}
 
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(bidEntitiesMongo)) > limit {
+		bidEntitiesMongo = bidEntitiesMongo[:limit]
+		last := bidEntitiesMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.sortValue(sortField), last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := bd.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error(
+				fmt.Sprintf("Error trying to count bids by auctionId %s", auctionId), err)
+			return nil, nil, internal_error.NewInternalServerError(
+				fmt.Sprintf("Error trying to count bids by auctionId %s", auctionId))
+		}
+		pageInfo.Total = &total
+	}
+
 	var bidEntities []bid_entity.Bid
 	for _, bidEntityMongo := range bidEntitiesMongo {
 		bidEntities = append(bidEntities, bid_entity.Bid{
 			Id:        bidEntityMongo.Id,
 			UserId:    bidEntityMongo.UserId,
 			AuctionId: bidEntityMongo.AuctionId,
-			Amount:    bidEntityMongo.Amount,
+			Amount:    bidEntityMongo.amount(),
 			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
 		})
 	}
 
-	return bidEntities, nil
+	return bidEntities, pageInfo, nil
 }
 
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auction_id": auctionId}
+	ctx, span := tracing.Start(ctx, "BidRepository.FindWinningBidByAuctionId")
+	defer span.End()
+
+	return bd.findHighestBid(ctx, bson.M{"auction_id": auctionId, "retracted_at": bson.M{"$exists": false}})
+}
 
+// FindRunnerUpBid returns the highest bid of the auction placed by someone
+// other than the winner.
+func (bd *BidRepository) FindRunnerUpBid(
+	ctx context.Context, auctionId, winnerId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidRepository.FindRunnerUpBid")
+	defer span.End()
+
+	return bd.findHighestBid(ctx, bson.M{
+		"auction_id":   auctionId,
+		"user_id":      bson.M{"$ne": winnerId},
+		"retracted_at": bson.M{"$exists": false},
+	})
+}
+
+// findHighestBid breaks ties by the earliest bid, the one that took the lead.
+func (bd *BidRepository) findHighestBid(
+	ctx context.Context, filter bson.M) (*bid_entity.Bid, *internal_error.InternalError) {
 	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{"amount", -1}})
-	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
+	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}})
+	err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError("No bid found for this auction")
+	}
+	if err != nil {
 		logger.Error("Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
 	}
@@ -62,7 +134,93 @@ func (bd *BidRepository) FindWinningBidByAuctionId(
 		Id:        bidEntityMongo.Id,
 		UserId:    bidEntityMongo.UserId,
 		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
+		Amount:    bidEntityMongo.amount(),
 		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
 	}, nil
 }
+
+func bidSortKey(sort bid_entity.BidSort) (string, string, int) {
+	if sort == bid_entity.SortHighestAmount {
+		return string(sort), "amount", -1
+	}
+
+	return string(bid_entity.SortNewest), "timestamp", -1
+}
+
+func (bm BidEntityMongo) sortValue(field string) interface{} {
+	if field == "amount" {
+		return bm.Amount
+	}
+
+	return bm.Timestamp
+}
+
+// FindBidById returns the bid, including a retracted one.
+func (bd *BidRepository) FindBidById(
+	ctx context.Context, bidId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidRepository.FindBidById")
+	defer span.End()
+
+	var bidEntityMongo BidEntityMongo
+	err := bd.Collection.FindOne(ctx, bson.M{"_id": bidId}).Decode(&bidEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("Bid not found with this id = %s", bidId))
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bid by id = %s", bidId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bid by id")
+	}
+
+	bid := bidEntityMongo.toBidEntity()
+	return &bid, nil
+}
+
+// FindAllBidsByAuctionId returns every bid stored for the auction, retracted
+// ones included, in the order they were placed.
+func (bd *BidRepository) FindAllBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidRepository.FindAllBidsByAuctionId")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := bd.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+	defer cursor.Close(ctx)
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bidEntityMongo.toBidEntity())
+	}
+
+	return bidEntities, nil
+}
+
+// MarkBidRetracted hides the bid from listings and from the winner lookup.
+// Marking a bid already retracted keeps its first retraction time.
+func (bd *BidRepository) MarkBidRetracted(
+	ctx context.Context, bidId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "BidRepository.MarkBidRetracted")
+	defer span.End()
+
+	filter := bson.M{"_id": bidId, "retracted_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"retracted_at": time.Now().Unix()}}
+
+	if _, err := bd.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to mark bid %s as retracted", bidId), err)
+		return internal_error.NewInternalServerError("Error trying to retract bid")
+	}
+
+	return nil
+}
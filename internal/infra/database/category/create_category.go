@@ -0,0 +1,129 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CategoryEntityMongo struct {
+	Id        string `bson:"_id"`
+	Name      string `bson:"name"`
+	Slug      string `bson:"slug"`
+	ParentId  string `bson:"parent_id,omitempty"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+type CategoryRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCategoryRepository(database *mongo.Database) *CategoryRepository {
+	return &CategoryRepository{
+		Collection: database.Collection("categories"),
+	}
+}
+
+func (cr *CategoryRepository) CreateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.CreateCategory")
+	defer span.End()
+
+	categoryEntityMongo := toCategoryEntityMongo(category)
+
+	if _, err := cr.Collection.InsertOne(ctx, categoryEntityMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError(
+				fmt.Sprintf("Category with slug %s already exists", category.Slug))
+		}
+
+		logger.Error("Error trying to insert category", err)
+		return internal_error.NewInternalServerError("Error trying to insert category")
+	}
+
+	return nil
+}
+
+func (cr *CategoryRepository) UpdateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.UpdateCategory")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{"name": category.Name}}
+	if category.ParentId == "" {
+		update["$unset"] = bson.M{"parent_id": ""}
+	} else {
+		update["$set"].(bson.M)["parent_id"] = category.ParentId
+	}
+
+	result, err := cr.Collection.UpdateOne(ctx, bson.M{"_id": category.Id}, update)
+	if err != nil {
+		logger.Error("Error trying to update category", err)
+		return internal_error.NewInternalServerError("Error trying to update category")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Category not found with this id = %s", category.Id))
+	}
+
+	return nil
+}
+
+func (cr *CategoryRepository) DeleteCategory(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.DeleteCategory")
+	defer span.End()
+
+	result, err := cr.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error("Error trying to delete category", err)
+		return internal_error.NewInternalServerError("Error trying to delete category")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Category not found with this id = %s", id))
+	}
+
+	return nil
+}
+
+func toCategoryEntityMongo(category *category_entity.Category) *CategoryEntityMongo {
+	return &CategoryEntityMongo{
+		Id:        category.Id,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentId:  category.ParentId,
+		Timestamp: category.Timestamp.Unix(),
+	}
+}
+
+func toCategoryEntity(categoryEntityMongo CategoryEntityMongo) category_entity.Category {
+	return category_entity.Category{
+		Id:        categoryEntityMongo.Id,
+		Name:      categoryEntityMongo.Name,
+		Slug:      categoryEntityMongo.Slug,
+		ParentId:  categoryEntityMongo.ParentId,
+		Timestamp: time.Unix(categoryEntityMongo.Timestamp, 0),
+	}
+}
+
+func notFoundOrInternal(err error, notFoundMessage, internalMessage string) *internal_error.InternalError {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error(notFoundMessage, err)
+		return internal_error.NewNotFoundError(notFoundMessage)
+	}
+
+	logger.Error(internalMessage, err)
+	return internal_error.NewInternalServerError(internalMessage)
+}
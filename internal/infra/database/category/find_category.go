@@ -0,0 +1,130 @@
+package category
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (cr *CategoryRepository) FindCategoryById(
+	ctx context.Context, id string) (*category_entity.Category, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.FindCategoryById")
+	defer span.End()
+
+	var categoryEntityMongo CategoryEntityMongo
+	if err := cr.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&categoryEntityMongo); err != nil {
+		return nil, notFoundOrInternal(err,
+			fmt.Sprintf("Category not found with this id = %s", id),
+			"Error trying to find category by id")
+	}
+
+	category := toCategoryEntity(categoryEntityMongo)
+	return &category, nil
+}
+
+func (cr *CategoryRepository) FindCategoryBySlug(
+	ctx context.Context, slug string) (*category_entity.Category, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.FindCategoryBySlug")
+	defer span.End()
+
+	var categoryEntityMongo CategoryEntityMongo
+	if err := cr.Collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&categoryEntityMongo); err != nil {
+		return nil, notFoundOrInternal(err,
+			fmt.Sprintf("Category not found with this slug = %s", slug),
+			"Error trying to find category by slug")
+	}
+
+	category := toCategoryEntity(categoryEntityMongo)
+	return &category, nil
+}
+
+// FindCategories lists the categories ordered by name. A nil parentId lists
+// every category, an empty one lists only the root categories.
+func (cr *CategoryRepository) FindCategories(
+	ctx context.Context, parentId *string) ([]category_entity.Category, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.FindCategories")
+	defer span.End()
+
+	filter := bson.M{}
+	if parentId != nil {
+		if *parentId == "" {
+			filter["parent_id"] = bson.M{"$exists": false}
+		} else {
+			filter["parent_id"] = *parentId
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := cr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error finding categories", err)
+		return nil, internal_error.NewInternalServerError("Error finding categories")
+	}
+	defer cursor.Close(ctx)
+
+	var categoriesMongo []CategoryEntityMongo
+	if err := cursor.All(ctx, &categoriesMongo); err != nil {
+		logger.Error("Error decoding categories", err)
+		return nil, internal_error.NewInternalServerError("Error decoding categories")
+	}
+
+	var categories []category_entity.Category
+	for _, categoryEntityMongo := range categoriesMongo {
+		categories = append(categories, toCategoryEntity(categoryEntityMongo))
+	}
+
+	return categories, nil
+}
+
+// FindDescendants returns every subcategory below id, at any depth, walking the
+// parent_id links with $graphLookup.
+func (cr *CategoryRepository) FindDescendants(
+	ctx context.Context, id string) ([]category_entity.Category, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryRepository.FindDescendants")
+	defer span.End()
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"_id": id}},
+		bson.M{"$graphLookup": bson.M{
+			"from":             cr.Collection.Name(),
+			"startWith":        "$_id",
+			"connectFromField": "_id",
+			"connectToField":   "parent_id",
+			"as":               "descendants",
+		}},
+	}
+
+	cursor, err := cr.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error finding subcategories", err)
+		return nil, internal_error.NewInternalServerError("Error finding subcategories")
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Descendants []CategoryEntityMongo `bson:"descendants"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error("Error decoding subcategories", err)
+		return nil, internal_error.NewInternalServerError("Error decoding subcategories")
+	}
+
+	if len(results) == 0 {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Category not found with this id = %s", id))
+	}
+
+	var descendants []category_entity.Category
+	for _, categoryEntityMongo := range results[0].Descendants {
+		descendants = append(descendants, toCategoryEntity(categoryEntityMongo))
+	}
+
+	return descendants, nil
+}
@@ -0,0 +1,194 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreditAccountEntityMongo keeps limit, held and hold amounts in minor units
+// of Currency.
+type CreditAccountEntityMongo struct {
+	Id        string            `bson:"_id"`
+	Limit     int64             `bson:"limit"`
+	Held      int64             `bson:"held"`
+	Currency  string            `bson:"currency"`
+	Holds     []HoldEntityMongo `bson:"holds"`
+	UpdatedAt int64             `bson:"updated_at"`
+}
+
+// HoldEntityMongo keeps the bid amount and the rate that converted it when the
+// auction is in another currency; Rate is the exact fraction of the rate.
+// Holds without them were taken in the account currency.
+type HoldEntityMongo struct {
+	AuctionId   string `bson:"auction_id"`
+	BidId       string `bson:"bid_id"`
+	Amount      int64  `bson:"amount"`
+	BidAmount   int64  `bson:"bid_amount,omitempty"`
+	BidCurrency string `bson:"bid_currency,omitempty"`
+	Rate        string `bson:"rate,omitempty"`
+	RateAsOf    int64  `bson:"rate_as_of,omitempty"`
+	HeldAt      int64  `bson:"held_at"`
+}
+
+// CreditRepository stores one account per user. Users without an account get
+// one with the default limit the first time they bid. Every account is in the
+// default currency; RateProvider gives the rate of the holds of other
+// currencies, which is then kept on the hold.
+type CreditRepository struct {
+	Collection   *mongo.Collection
+	RateProvider money_entity.RateProviderInterface
+	defaultLimit money_entity.Money
+}
+
+func NewCreditRepository(
+	database *mongo.Database, rateProvider money_entity.RateProviderInterface) *CreditRepository {
+	return &CreditRepository{
+		Collection:   database.Collection("credit_accounts"),
+		RateProvider: rateProvider,
+		defaultLimit: getDefaultCreditLimit(),
+	}
+}
+
+func (cr *CreditRepository) FindCreditAccount(
+	ctx context.Context, userId string) (*credit_entity.CreditAccount, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CreditRepository.FindCreditAccount")
+	defer span.End()
+
+	var accountEntityMongo CreditAccountEntityMongo
+	err := cr.Collection.FindOne(ctx, bson.M{"_id": userId}).Decode(&accountEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return credit_entity.NewCreditAccount(userId, cr.defaultLimit), nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find credit account", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find credit account")
+	}
+
+	return toCreditAccountEntity(accountEntityMongo), nil
+}
+
+func (cr *CreditRepository) SetCreditLimit(
+	ctx context.Context,
+	userId string,
+	limit money_entity.Money) (*credit_entity.CreditAccount, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CreditRepository.SetCreditLimit")
+	defer span.End()
+
+	update := bson.M{
+		"$set":         bson.M{"limit": limit.Amount, "updated_at": time.Now().Unix()},
+		"$setOnInsert": bson.M{"held": int64(0), "currency": limit.Currency, "holds": bson.A{}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var accountEntityMongo CreditAccountEntityMongo
+	err := cr.Collection.FindOneAndUpdate(ctx, bson.M{"_id": userId}, update, opts).Decode(&accountEntityMongo)
+	if err != nil {
+		logger.Error("Error trying to update credit limit", err)
+		return nil, internal_error.NewInternalServerError("Error trying to update credit limit")
+	}
+
+	return toCreditAccountEntity(accountEntityMongo), nil
+}
+
+// ensureAccount creates the account of userId with the default limit when it
+// does not exist yet.
+func (cr *CreditRepository) ensureAccount(
+	ctx context.Context, userId string) *internal_error.InternalError {
+	update := bson.M{"$setOnInsert": bson.M{
+		"limit":      cr.defaultLimit.Amount,
+		"held":       int64(0),
+		"currency":   cr.defaultLimit.Currency,
+		"holds":      bson.A{},
+		"updated_at": time.Now().Unix(),
+	}}
+
+	_, err := cr.Collection.UpdateOne(ctx, bson.M{"_id": userId}, update, options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.Error("Error trying to create credit account", err)
+		return internal_error.NewInternalServerError("Error trying to create credit account")
+	}
+
+	return nil
+}
+
+func toCreditAccountEntity(account CreditAccountEntityMongo) *credit_entity.CreditAccount {
+	currency := money_entity.Currency(account.Currency)
+	holds := make([]credit_entity.Hold, 0, len(account.Holds))
+	for _, hold := range account.Holds {
+		holds = append(holds, toHoldEntity(hold, currency))
+	}
+
+	return &credit_entity.CreditAccount{
+		UserId:    account.Id,
+		Limit:     money_entity.New(account.Limit, currency),
+		Held:      money_entity.New(account.Held, currency),
+		Holds:     holds,
+		UpdatedAt: time.Unix(account.UpdatedAt, 0),
+	}
+}
+
+func toHoldEntity(hold HoldEntityMongo, currency money_entity.Currency) credit_entity.Hold {
+	amount := money_entity.New(hold.Amount, currency)
+	entity := credit_entity.Hold{
+		AuctionId: hold.AuctionId,
+		BidId:     hold.BidId,
+		Amount:    amount,
+		Bid:       amount,
+		HeldAt:    time.Unix(hold.HeldAt, 0),
+	}
+
+	value, ok := new(big.Rat).SetString(hold.Rate)
+	if hold.BidCurrency == "" || !ok {
+		return entity
+	}
+
+	entity.Bid = money_entity.New(hold.BidAmount, money_entity.Currency(hold.BidCurrency))
+	entity.Rate = &money_entity.Rate{
+		From:  entity.Bid.Currency,
+		To:    currency,
+		Value: value,
+		AsOf:  time.Unix(hold.RateAsOf, 0),
+	}
+	return entity
+}
+
+func toHoldEntityMongo(hold credit_entity.Hold) HoldEntityMongo {
+	entity := HoldEntityMongo{
+		AuctionId: hold.AuctionId,
+		BidId:     hold.BidId,
+		Amount:    hold.Amount.Amount,
+		HeldAt:    hold.HeldAt.Unix(),
+	}
+	if hold.Rate != nil {
+		entity.BidAmount = hold.Bid.Amount
+		entity.BidCurrency = string(hold.Bid.Currency)
+		entity.Rate = hold.Rate.Value.RatString()
+		entity.RateAsOf = hold.Rate.AsOf.Unix()
+	}
+
+	return entity
+}
+
+// getDefaultCreditLimit reads CREDIT_DEFAULT_LIMIT in major units of the
+// default currency.
+func getDefaultCreditLimit() money_entity.Money {
+	limit, err := money_entity.Parse(os.Getenv("CREDIT_DEFAULT_LIMIT"), money_entity.DefaultCurrency)
+	if err != nil {
+		return money_entity.New(1000000, money_entity.DefaultCurrency)
+	}
+
+	return limit
+}
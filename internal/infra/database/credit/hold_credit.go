@@ -0,0 +1,140 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// HoldCredit converts the bid into the account currency, reusing the rate of
+// the current hold of the auction, then checks the currency and the limit and
+// replaces the hold of the auction in a single conditional update, so
+// concurrent bids from the same user cannot both pass the check.
+func (cr *CreditRepository) HoldCredit(
+	ctx context.Context, userId string, hold credit_entity.Hold) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CreditRepository.HoldCredit")
+	defer span.End()
+
+	account, err := cr.FindCreditAccount(ctx, userId)
+	if err != nil {
+		return err
+	}
+	hold, err = account.NewHold(ctx, cr.RateProvider, hold.AuctionId, hold.BidId, hold.Bid)
+	if err != nil {
+		return err
+	}
+
+	current := heldIn(hold.AuctionId)
+	raises := bson.M{"$gt": bson.A{hold.Amount.Amount, current}}
+	heldAfter := bson.M{"$add": bson.A{bson.M{"$subtract": bson.A{"$held", current}}, hold.Amount.Amount}}
+
+	filter := bson.M{
+		"_id":      userId,
+		"currency": hold.Amount.Currency,
+		"$expr": bson.M{"$or": bson.A{
+			bson.M{"$not": bson.A{raises}},
+			bson.M{"$lte": bson.A{heldAfter, "$limit"}},
+		}},
+	}
+	update := bson.A{bson.M{"$set": bson.M{
+		"held": bson.M{"$cond": bson.A{raises, heldAfter, "$held"}},
+		"holds": bson.M{"$cond": bson.A{
+			raises,
+			bson.M{"$concatArrays": bson.A{
+				holdsExcept(hold.AuctionId),
+				bson.A{bson.M{"$literal": toHoldEntityMongo(hold)}},
+			}},
+			"$holds",
+		}},
+		"updated_at": time.Now().Unix(),
+	}}}
+
+	// No match means the limit would be exceeded, the currency differs or the
+	// user has no account yet; in the latter case the account is created and
+	// the hold retried.
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := cr.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			logger.Error("Error trying to hold credit", err)
+			return internal_error.NewInternalServerError("Error trying to hold credit")
+		}
+		if result.MatchedCount > 0 {
+			return nil
+		}
+
+		if attempt == 0 {
+			if err := cr.ensureAccount(ctx, userId); err != nil {
+				return err
+			}
+		}
+	}
+
+	return internal_error.NewConflictError("Bid exceeds the available credit")
+}
+
+func (cr *CreditRepository) ReleaseHold(
+	ctx context.Context, userId, auctionId, bidId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CreditRepository.ReleaseHold")
+	defer span.End()
+
+	filter := bson.M{
+		"_id":   userId,
+		"holds": bson.M{"$elemMatch": bson.M{"auction_id": auctionId, "bid_id": bidId}},
+	}
+
+	if _, err := cr.Collection.UpdateOne(ctx, filter, releaseUpdate(auctionId)); err != nil {
+		logger.Error("Error trying to release credit hold", err)
+		return internal_error.NewInternalServerError("Error trying to release credit hold")
+	}
+
+	return nil
+}
+
+func (cr *CreditRepository) ReleaseAuctionHolds(
+	ctx context.Context, auctionId, keepBidId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CreditRepository.ReleaseAuctionHolds")
+	defer span.End()
+
+	filter := bson.M{
+		"holds": bson.M{"$elemMatch": bson.M{"auction_id": auctionId, "bid_id": bson.M{"$ne": keepBidId}}},
+	}
+
+	if _, err := cr.Collection.UpdateMany(ctx, filter, releaseUpdate(auctionId)); err != nil {
+		logger.Error("Error trying to release credit holds of auction", err)
+		return internal_error.NewInternalServerError("Error trying to release credit holds of auction")
+	}
+
+	return nil
+}
+
+func releaseUpdate(auctionId string) bson.A {
+	return bson.A{bson.M{"$set": bson.M{
+		"held":       bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$held", heldIn(auctionId)}}}},
+		"holds":      holdsExcept(auctionId),
+		"updated_at": time.Now().Unix(),
+	}}}
+}
+
+// heldIn is the expression of the amount held by the account in auctionId.
+func heldIn(auctionId string) bson.M {
+	return bson.M{"$sum": bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$holds", bson.A{}}},
+			"cond":  bson.M{"$eq": bson.A{"$$this.auction_id", auctionId}},
+		}},
+		"in": "$$this.amount",
+	}}}
+}
+
+func holdsExcept(auctionId string) bson.M {
+	return bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$holds", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this.auction_id", auctionId}},
+	}}
+}
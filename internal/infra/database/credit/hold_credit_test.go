@@ -0,0 +1,163 @@
+package credit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/fx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+var defaultLimit = money_entity.New(100000, "BRL")
+
+func updateResponse(matched int) bson.D {
+	return bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: matched}, {Key: "nModified", Value: matched}}
+}
+
+// noAccount is the response to the lookup of a user without an account.
+func noAccount() bson.D {
+	return mtest.CreateCursorResponse(0, "testdb.credit_accounts", mtest.FirstBatch)
+}
+
+func TestHoldCredit(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	hold := credit_entity.Hold{AuctionId: "auction-1", BidId: "bid-1", Bid: money_entity.New(10000, "BRL")}
+
+	mt.Run("should hold when the bid fits the limit", func(mt *mtest.T) {
+		mt.AddMockResponses(noAccount(), updateResponse(1))
+		repo := &CreditRepository{Collection: mt.Coll, defaultLimit: defaultLimit}
+
+		err := repo.HoldCredit(context.Background(), "user-1", hold)
+
+		assert.Nil(mt, err)
+	})
+
+	mt.Run("should create the account of a new bidder and hold", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			noAccount(),
+			updateResponse(0),
+			bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "n", Value: 1},
+				{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: "user-1"}}}},
+			},
+			updateResponse(1),
+		)
+		repo := &CreditRepository{Collection: mt.Coll, defaultLimit: defaultLimit}
+
+		err := repo.HoldCredit(context.Background(), "user-1", hold)
+
+		assert.Nil(mt, err)
+	})
+
+	mt.Run("should hold a bid of an auction in another currency converted", func(mt *mtest.T) {
+		mt.AddMockResponses(noAccount(), updateResponse(1))
+		rates, err := fx.NewStaticProvider(strings.NewReader(`{"base": "USD", "rates": {"BRL": "5.00"}}`))
+		require.NoError(mt, err)
+		repo := &CreditRepository{Collection: mt.Coll, RateProvider: rates, defaultLimit: defaultLimit}
+
+		usdHold := hold
+		usdHold.Bid = money_entity.New(1500, "USD")
+		ierr := repo.HoldCredit(context.Background(), "user-1", usdHold)
+
+		require.Nil(mt, ierr)
+		mt.GetStartedEvent()
+		command := mt.GetStartedEvent().Command.String()
+		assert.Contains(mt, command, `"currency": "BRL"`)
+		assert.Contains(mt, command, `"amount": {"$numberLong":"7500"}`)
+		assert.Contains(mt, command, `"bid_currency": "USD"`)
+		assert.Contains(mt, command, `"rate": "5"`)
+	})
+
+	mt.Run("should convert a higher bid at the rate of the hold of the auction", func(mt *mtest.T) {
+		account := bson.D{
+			{Key: "_id", Value: "user-1"},
+			{Key: "limit", Value: int64(100000)},
+			{Key: "held", Value: int64(6000)},
+			{Key: "currency", Value: "BRL"},
+			{Key: "holds", Value: bson.A{bson.D{
+				{Key: "auction_id", Value: "auction-1"},
+				{Key: "bid_id", Value: "bid-1"},
+				{Key: "amount", Value: int64(6000)},
+				{Key: "bid_amount", Value: int64(1500)},
+				{Key: "bid_currency", Value: "USD"},
+				{Key: "rate", Value: "4"},
+				{Key: "held_at", Value: time.Now().Unix()},
+			}}},
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.credit_accounts", mtest.FirstBatch, account),
+			updateResponse(1),
+		)
+		rates, err := fx.NewStaticProvider(strings.NewReader(`{"base": "USD", "rates": {"BRL": "5.00"}}`))
+		require.NoError(mt, err)
+		repo := &CreditRepository{Collection: mt.Coll, RateProvider: rates, defaultLimit: defaultLimit}
+
+		usdHold := hold
+		usdHold.BidId = "bid-2"
+		usdHold.Bid = money_entity.New(2000, "USD")
+		ierr := repo.HoldCredit(context.Background(), "user-1", usdHold)
+
+		require.Nil(mt, ierr)
+		mt.GetStartedEvent()
+		command := mt.GetStartedEvent().Command.String()
+		assert.Contains(mt, command, `"amount": {"$numberLong":"8000"}`)
+		assert.Contains(mt, command, `"rate": "4"`)
+	})
+
+	mt.Run("should reject a bid in a currency without an exchange rate", func(mt *mtest.T) {
+		rates, err := fx.NewStaticProvider(strings.NewReader(`{"base": "USD", "rates": {"BRL": "5.00"}}`))
+		require.NoError(mt, err)
+		repo := &CreditRepository{Collection: mt.Coll, RateProvider: rates, defaultLimit: defaultLimit}
+
+		mt.AddMockResponses(noAccount())
+		eurHold := hold
+		eurHold.Bid = money_entity.New(1500, "EUR")
+		ierr := repo.HoldCredit(context.Background(), "user-1", eurHold)
+
+		require.NotNil(mt, ierr)
+		assert.Equal(mt, "unprocessable_entity", ierr.Err)
+	})
+
+	mt.Run("should return conflict when the limit would be exceeded", func(mt *mtest.T) {
+		mt.AddMockResponses(noAccount(), updateResponse(0), updateResponse(1), updateResponse(0))
+		repo := &CreditRepository{Collection: mt.Coll, defaultLimit: defaultLimit}
+
+		err := repo.HoldCredit(context.Background(), "user-1", hold)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "conflict", err.Err)
+	})
+}
+
+func TestFindCreditAccount(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the default account of a user without one", func(mt *mtest.T) {
+		mt.AddMockResponses(noAccount())
+		repo := &CreditRepository{Collection: mt.Coll, defaultLimit: defaultLimit}
+
+		account, err := repo.FindCreditAccount(context.Background(), "user-1")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, defaultLimit, account.Limit)
+		assert.Equal(mt, defaultLimit, account.Available())
+		assert.Empty(mt, account.Holds)
+	})
+}
+
+func TestGetDefaultCreditLimit(t *testing.T) {
+	t.Setenv("CREDIT_DEFAULT_LIMIT", "2500.50")
+	assert.Equal(t, money_entity.New(250050, money_entity.DefaultCurrency), getDefaultCreditLimit())
+
+	t.Setenv("CREDIT_DEFAULT_LIMIT", "-1")
+	assert.Equal(t, money_entity.New(1000000, money_entity.DefaultCurrency), getDefaultCreditLimit())
+}
@@ -0,0 +1,245 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/transaction"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EventEntityMongo struct {
+	Id         string `bson:"_id"`
+	AuctionId  string `bson:"auction_id"`
+	Version    int64  `bson:"version"`
+	Type       string `bson:"type"`
+	BidId      string `bson:"bid_id,omitempty"`
+	UserId     string `bson:"user_id,omitempty"`
+	Amount     int64  `bson:"amount,omitempty"`
+	Currency   string `bson:"currency,omitempty"`
+	Reason     string `bson:"reason,omitempty"`
+	Detail     string `bson:"detail,omitempty"`
+	EndsAt     int64  `bson:"ends_at,omitempty"`
+	OccurredAt int64  `bson:"occurred_at"`
+}
+
+func (em EventEntityMongo) toEventEntity() ledger_entity.Event {
+	event := ledger_entity.Event{
+		Id:         em.Id,
+		AuctionId:  em.AuctionId,
+		Version:    em.Version,
+		Type:       ledger_entity.EventType(em.Type),
+		BidId:      em.BidId,
+		UserId:     em.UserId,
+		Reason:     em.Reason,
+		Detail:     em.Detail,
+		OccurredAt: time.Unix(em.OccurredAt, 0),
+	}
+	if em.Currency != "" {
+		event.Amount = money_entity.New(em.Amount, money_entity.Currency(em.Currency))
+	}
+	if em.EndsAt != 0 {
+		event.EndsAt = time.Unix(em.EndsAt, 0)
+	}
+
+	return event
+}
+
+// VersionEntityMongo is the last version taken in the ledger of an auction.
+type VersionEntityMongo struct {
+	AuctionId string `bson:"_id"`
+	Version   int64  `bson:"version"`
+}
+
+// LedgerRepository keeps the bid ledger of every auction in one collection,
+// with a unique version per auction.
+type LedgerRepository struct {
+	Collection        *mongo.Collection
+	VersionCollection *mongo.Collection
+}
+
+func NewLedgerRepository(database *mongo.Database) *LedgerRepository {
+	return &LedgerRepository{
+		Collection:        database.Collection("bid_ledger"),
+		VersionCollection: database.Collection("bid_ledger_versions"),
+	}
+}
+
+// AppendEvent takes the version after the last one of the auction and records
+// it on the version of the auction, in one transaction; it joins the
+// transaction in ctx, if any, so the event commits with the change it
+// describes. Concurrent appends to an auction write the same version document,
+// so all but one conflict and are retried, and versions never have gaps.
+func (lr *LedgerRepository) AppendEvent(
+	ctx context.Context, event ledger_entity.Event) (*ledger_entity.Event, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerRepository.AppendEvent")
+	defer span.End()
+
+	eventMongo := &EventEntityMongo{
+		Id:         event.Id,
+		AuctionId:  event.AuctionId,
+		Type:       string(event.Type),
+		BidId:      event.BidId,
+		UserId:     event.UserId,
+		Amount:     event.Amount.Amount,
+		Currency:   string(event.Amount.Currency),
+		Reason:     event.Reason,
+		Detail:     event.Detail,
+		OccurredAt: event.OccurredAt.Unix(),
+	}
+	if !event.EndsAt.IsZero() {
+		eventMongo.EndsAt = event.EndsAt.Unix()
+	}
+
+	err := transaction.Run(ctx, lr.Collection.Database().Client(), func(ctx context.Context) *internal_error.InternalError {
+		last, err := lr.lastVersion(ctx, event.AuctionId)
+		if err != nil {
+			return err
+		}
+		eventMongo.Version = last + 1
+
+		update := bson.M{"$set": bson.M{"version": eventMongo.Version}}
+		opts := options.Update().SetUpsert(true)
+		if _, err := lr.VersionCollection.UpdateOne(ctx, bson.M{"_id": event.AuctionId}, update, opts); err != nil {
+			logger.Error(fmt.Sprintf("Error trying to take ledger version %d of auction %s",
+				eventMongo.Version, event.AuctionId), err)
+			return internal_error.NewInternalServerError("Error trying to append ledger event")
+		}
+
+		if _, err := lr.Collection.InsertOne(ctx, eventMongo); err != nil {
+			logger.Error("Error trying to append ledger event", err)
+			return internal_error.NewInternalServerError("Error trying to append ledger event")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event.Version = eventMongo.Version
+	return &event, nil
+}
+
+func (lr *LedgerRepository) lastVersion(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+
+	var eventMongo EventEntityMongo
+	err := lr.Collection.FindOne(ctx, bson.M{"auction_id": auctionId}, opts).Decode(&eventMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find the last ledger version", err)
+		return 0, internal_error.NewInternalServerError("Error trying to find the last ledger version")
+	}
+
+	return eventMongo.Version, nil
+}
+
+func (lr *LedgerRepository) FindAuctionEvents(
+	ctx context.Context, auctionId string) ([]ledger_entity.Event, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerRepository.FindAuctionEvents")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := lr.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find the ledger of auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find ledger events")
+	}
+	defer cursor.Close(ctx)
+
+	var eventsMongo []EventEntityMongo
+	if err := cursor.All(ctx, &eventsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode the ledger of auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find ledger events")
+	}
+
+	events := make([]ledger_entity.Event, 0, len(eventsMongo))
+	for _, eventMongo := range eventsMongo {
+		events = append(events, eventMongo.toEventEntity())
+	}
+
+	return events, nil
+}
+
+// FindEvents pages through the events of the auction in version order.
+func (lr *LedgerRepository) FindEvents(
+	ctx context.Context,
+	auctionId string,
+	page pagination_entity.PageRequest) ([]ledger_entity.Event, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerRepository.FindEvents")
+	defer span.End()
+
+	const sortName, sortField, direction = "version", "version", 1
+
+	filter := bson.M{"auction_id": auctionId}
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := lr.Collection.Find(ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find the ledger of auction %s", auctionId), err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find ledger events")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var eventsMongo []EventEntityMongo
+	if err := mongoCursor.All(ctx, &eventsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode the ledger of auction %s", auctionId), err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find ledger events")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(eventsMongo)) > limit {
+		eventsMongo = eventsMongo[:limit]
+		last := eventsMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.Version, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := lr.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error trying to count the ledger of auction %s", auctionId), err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count ledger events")
+		}
+		pageInfo.Total = &total
+	}
+
+	events := make([]ledger_entity.Event, 0, len(eventsMongo))
+	for _, eventMongo := range eventsMongo {
+		events = append(events, eventMongo.toEventEntity())
+	}
+
+	return events, pageInfo, nil
+}
@@ -0,0 +1,174 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAppendEvent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	bid := bid_entity.Bid{
+		Id:        "bid-1",
+		UserId:    "user-1",
+		AuctionId: "auction-1",
+		Amount:    money_entity.New(1500, "BRL"),
+		Timestamp: time.Now(),
+	}
+
+	mt.Run("should append after the last version of the auction", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.bid_ledger", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "event-1"}, {Key: "version", Value: int64(4)}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &LedgerRepository{Collection: mt.Coll, VersionCollection: mt.Coll}
+
+		event, err := repo.AppendEvent(context.Background(), ledger_entity.NewBidPlaced(bid))
+
+		require.Nil(mt, err)
+		assert.Equal(mt, int64(5), event.Version)
+		assert.Equal(mt, "bid-1", event.BidId)
+	})
+
+	mt.Run("should take the version and append in one transaction", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.bid_ledger", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &LedgerRepository{Collection: mt.Coll, VersionCollection: mt.Coll}
+
+		_, err := repo.AppendEvent(context.Background(), ledger_entity.NewBidPlaced(bid))
+
+		require.Nil(mt, err)
+		started := mt.GetAllStartedEvents()
+		require.Len(mt, started, 4)
+		assert.Equal(mt, "update", started[1].CommandName)
+		assert.Equal(mt, int64(1), started[1].Command.Lookup("updates").Array().Index(0).Value().Document().
+			Lookup("u", "$set", "version").AsInt64())
+		assert.Equal(mt, "insert", started[2].CommandName)
+		assert.Equal(mt, "commitTransaction", started[3].CommandName)
+	})
+
+	mt.Run("should return internal error when the insert fails", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.bid_ledger", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "insert error"}),
+		)
+		repo := &LedgerRepository{Collection: mt.Coll, VersionCollection: mt.Coll}
+
+		event, err := repo.AppendEvent(context.Background(), ledger_entity.NewBidPlaced(bid))
+
+		assert.Nil(mt, event)
+		require.NotNil(mt, err)
+		assert.Equal(mt, "Error trying to append ledger event", err.Message)
+	})
+}
+
+func TestFindAuctionEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the events of the auction", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.bid_ledger", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "event-1"},
+				{Key: "auction_id", Value: "auction-1"},
+				{Key: "version", Value: int64(1)},
+				{Key: "type", Value: "bid.placed"},
+				{Key: "bid_id", Value: "bid-1"},
+				{Key: "user_id", Value: "user-1"},
+				{Key: "amount", Value: int64(1500)},
+				{Key: "currency", Value: "BRL"},
+				{Key: "occurred_at", Value: int64(1700000000)},
+			},
+			bson.D{
+				{Key: "_id", Value: "event-2"},
+				{Key: "auction_id", Value: "auction-1"},
+				{Key: "version", Value: int64(2)},
+				{Key: "type", Value: "auction.extended"},
+				{Key: "ends_at", Value: int64(1700003600)},
+				{Key: "occurred_at", Value: int64(1700000100)},
+			},
+		))
+		repo := &LedgerRepository{Collection: mt.Coll}
+
+		events, err := repo.FindAuctionEvents(context.Background(), "auction-1")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, []ledger_entity.Event{
+			{
+				Id:         "event-1",
+				AuctionId:  "auction-1",
+				Version:    1,
+				Type:       ledger_entity.BidPlaced,
+				BidId:      "bid-1",
+				UserId:     "user-1",
+				Amount:     money_entity.New(1500, "BRL"),
+				OccurredAt: time.Unix(1700000000, 0),
+			},
+			{
+				Id:         "event-2",
+				AuctionId:  "auction-1",
+				Version:    2,
+				Type:       ledger_entity.AuctionExtended,
+				EndsAt:     time.Unix(1700003600, 0),
+				OccurredAt: time.Unix(1700000100, 0),
+			},
+		}, events)
+	})
+}
+
+func TestFindEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	event := func(id string, version int64) bson.D {
+		return bson.D{
+			{Key: "_id", Value: id},
+			{Key: "auction_id", Value: "auction-1"},
+			{Key: "version", Value: version},
+			{Key: "type", Value: "bid.rejected"},
+			{Key: "occurred_at", Value: int64(1700000000)},
+		}
+	}
+
+	mt.Run("should return a next cursor when there are more events than the limit", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.bid_ledger", mtest.FirstBatch,
+			event("event-1", 1), event("event-2", 2), event("event-3", 3)))
+		repo := &LedgerRepository{Collection: mt.Coll}
+
+		events, pageInfo, err := repo.FindEvents(
+			context.Background(), "auction-1", pagination_entity.PageRequest{Limit: 2})
+
+		require.Nil(mt, err)
+		require.Len(mt, events, 2)
+		assert.Equal(mt, int64(2), events[1].Version)
+		assert.True(mt, pageInfo.HasMore)
+		assert.NotEmpty(mt, pageInfo.NextCursor)
+	})
+
+	mt.Run("should return bad request for an invalid cursor", func(mt *mtest.T) {
+		repo := &LedgerRepository{Collection: mt.Coll}
+
+		_, _, err := repo.FindEvents(
+			context.Background(), "auction-1", pagination_entity.PageRequest{Cursor: "invalid"})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "bad_request", err.Err)
+	})
+}
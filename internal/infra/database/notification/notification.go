@@ -0,0 +1,280 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationEntityMongo is an in-app notification, shown in the inbox of the
+// user.
+type NotificationEntityMongo struct {
+	Id        string `bson:"_id"`
+	Key       string `bson:"key"`
+	UserId    string `bson:"user_id"`
+	Type      string `bson:"type"`
+	AuctionId string `bson:"auction_id"`
+	Title     string `bson:"title"`
+	Message   string `bson:"message"`
+	CreatedAt int64  `bson:"created_at"`
+	ReadAt    *int64 `bson:"read_at,omitempty"`
+}
+
+type PreferencesEntityMongo struct {
+	Id        string              `bson:"_id"`
+	Channels  map[string][]string `bson:"channels"`
+	UpdatedAt int64               `bson:"updated_at"`
+}
+
+// ClaimEntityMongo marks a notification key as already notified.
+type ClaimEntityMongo struct {
+	Id        string `bson:"_id"`
+	ClaimedAt int64  `bson:"claimed_at"`
+}
+
+type NotificationRepository struct {
+	Collection            *mongo.Collection
+	PreferencesCollection *mongo.Collection
+	ClaimCollection       *mongo.Collection
+}
+
+func NewNotificationRepository(database *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		Collection:            database.Collection("notifications"),
+		PreferencesCollection: database.Collection("notification_preferences"),
+		ClaimCollection:       database.Collection("notification_claims"),
+	}
+}
+
+func (nr *NotificationRepository) ClaimNotification(
+	ctx context.Context, key string, claimedAt time.Time) (bool, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.ClaimNotification")
+	defer span.End()
+
+	_, err := nr.ClaimCollection.InsertOne(ctx, ClaimEntityMongo{Id: key, ClaimedAt: claimedAt.Unix()})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to claim notification "+key, err)
+		return false, internal_error.NewInternalServerError("Error trying to claim notification")
+	}
+
+	return true, nil
+}
+
+func (nr *NotificationRepository) FindPreferences(
+	ctx context.Context, userId string) (*notification_entity.Preferences, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.FindPreferences")
+	defer span.End()
+
+	var preferencesMongo PreferencesEntityMongo
+	err := nr.PreferencesCollection.FindOne(ctx, bson.M{"_id": userId}).Decode(&preferencesMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notification_entity.DefaultPreferences(userId), nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find notification preferences", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find notification preferences")
+	}
+
+	channels := make(map[notification_entity.NotificationType][]notification_entity.Channel)
+	for notificationType, names := range preferencesMongo.Channels {
+		for _, name := range names {
+			channels[notification_entity.NotificationType(notificationType)] = append(
+				channels[notification_entity.NotificationType(notificationType)], notification_entity.Channel(name))
+		}
+	}
+
+	return &notification_entity.Preferences{
+		UserId:    preferencesMongo.Id,
+		Channels:  channels,
+		UpdatedAt: time.Unix(preferencesMongo.UpdatedAt, 0),
+	}, nil
+}
+
+func (nr *NotificationRepository) UpdatePreferences(
+	ctx context.Context, preferences *notification_entity.Preferences) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.UpdatePreferences")
+	defer span.End()
+
+	channels := make(map[string][]string, len(preferences.Channels))
+	for notificationType, values := range preferences.Channels {
+		names := make([]string, 0, len(values))
+		for _, channel := range values {
+			names = append(names, string(channel))
+		}
+		channels[string(notificationType)] = names
+	}
+
+	update := bson.M{"$set": bson.M{"channels": channels, "updated_at": preferences.UpdatedAt.Unix()}}
+	_, err := nr.PreferencesCollection.UpdateOne(
+		ctx, bson.M{"_id": preferences.UserId}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		logger.Error("Error trying to update notification preferences", err)
+		return internal_error.NewInternalServerError("Error trying to update notification preferences")
+	}
+
+	return nil
+}
+
+func (nr *NotificationRepository) FindNotifications(
+	ctx context.Context,
+	userId string,
+	page pagination_entity.PageRequest) ([]notification_entity.Notification, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.FindNotifications")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "created_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	filter := bson.M{"user_id": userId}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := nr.Collection.Find(ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find notifications", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find notifications")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var notificationsMongo []NotificationEntityMongo
+	if err := mongoCursor.All(ctx, &notificationsMongo); err != nil {
+		logger.Error("Error trying to decode notifications", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find notifications")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(notificationsMongo)) > limit {
+		notificationsMongo = notificationsMongo[:limit]
+		last := notificationsMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.CreatedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := nr.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error trying to count notifications", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count notifications")
+		}
+		pageInfo.Total = &total
+	}
+
+	notifications := make([]notification_entity.Notification, 0, len(notificationsMongo))
+	for _, notificationMongo := range notificationsMongo {
+		notifications = append(notifications, toNotificationEntity(notificationMongo))
+	}
+
+	return notifications, pageInfo, nil
+}
+
+func (nr *NotificationRepository) MarkAsRead(
+	ctx context.Context, userId, notificationId string, readAt time.Time) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.MarkAsRead")
+	defer span.End()
+
+	result, err := nr.Collection.UpdateOne(ctx,
+		bson.M{"_id": notificationId, "user_id": userId},
+		bson.A{bson.M{"$set": bson.M{
+			"read_at": bson.M{"$ifNull": bson.A{"$read_at", readAt.Unix()}},
+		}}})
+	if err != nil {
+		logger.Error("Error trying to mark notification as read", err)
+		return internal_error.NewInternalServerError("Error trying to mark notification as read")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Notification not found with this id = %s", notificationId))
+	}
+
+	return nil
+}
+
+// InboxNotifier delivers the in-app notifications, stored for the user to read
+// later.
+type InboxNotifier struct {
+	Collection *mongo.Collection
+}
+
+func NewInboxNotifier(database *mongo.Database) *InboxNotifier {
+	return &InboxNotifier{
+		Collection: database.Collection("notifications"),
+	}
+}
+
+func (in *InboxNotifier) Channel() notification_entity.Channel {
+	return notification_entity.ChannelInApp
+}
+
+func (in *InboxNotifier) Notify(
+	ctx context.Context,
+	recipient user_entity.User,
+	notification notification_entity.Notification) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "InboxNotifier.Notify")
+	defer span.End()
+
+	notificationMongo := NotificationEntityMongo{
+		Id:        notification.Id,
+		Key:       notification.Key,
+		UserId:    recipient.Id,
+		Type:      string(notification.Type),
+		AuctionId: notification.AuctionId,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		CreatedAt: notification.CreatedAt.Unix(),
+	}
+
+	if _, err := in.Collection.InsertOne(ctx, notificationMongo); err != nil {
+		logger.Error("Error trying to insert notification", err)
+		return internal_error.NewInternalServerError("Error trying to insert notification")
+	}
+
+	return nil
+}
+
+func toNotificationEntity(notificationMongo NotificationEntityMongo) notification_entity.Notification {
+	notification := notification_entity.Notification{
+		Id:        notificationMongo.Id,
+		Key:       notificationMongo.Key,
+		UserId:    notificationMongo.UserId,
+		Type:      notification_entity.NotificationType(notificationMongo.Type),
+		AuctionId: notificationMongo.AuctionId,
+		Title:     notificationMongo.Title,
+		Message:   notificationMongo.Message,
+		CreatedAt: time.Unix(notificationMongo.CreatedAt, 0),
+	}
+
+	if notificationMongo.ReadAt != nil {
+		readAt := time.Unix(*notificationMongo.ReadAt, 0)
+		notification.ReadAt = &readAt
+	}
+
+	return notification
+}
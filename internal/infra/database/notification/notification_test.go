@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestClaimNotification(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should claim a new key", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		repo := &NotificationRepository{ClaimCollection: mt.Coll}
+
+		claimed, err := repo.ClaimNotification(context.Background(), "outbid:auction-1:bid-1", time.Now())
+
+		assert.Nil(mt, err)
+		assert.True(mt, claimed)
+	})
+
+	mt.Run("should not claim a key twice", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index: 0, Code: 11000, Message: "duplicate key error",
+		}))
+		repo := &NotificationRepository{ClaimCollection: mt.Coll}
+
+		claimed, err := repo.ClaimNotification(context.Background(), "outbid:auction-1:bid-1", time.Now())
+
+		assert.Nil(mt, err)
+		assert.False(mt, claimed)
+	})
+}
+
+func TestFindPreferences(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the defaults of users without preferences", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.notification_preferences", mtest.FirstBatch))
+		repo := &NotificationRepository{PreferencesCollection: mt.Coll}
+
+		preferences, err := repo.FindPreferences(context.Background(), "user-1")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, notification_entity.DefaultPreferences("user-1"), preferences)
+	})
+
+	mt.Run("should return the saved channels", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.notification_preferences", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "user-1"},
+				{Key: "channels", Value: bson.D{{Key: "outbid", Value: bson.A{"in_app"}}}},
+				{Key: "updated_at", Value: time.Now().Unix()},
+			}))
+		repo := &NotificationRepository{PreferencesCollection: mt.Coll}
+
+		preferences, err := repo.FindPreferences(context.Background(), "user-1")
+
+		require.Nil(mt, err)
+		assert.True(mt, preferences.Allows(notification_entity.Outbid, notification_entity.ChannelInApp))
+		assert.False(mt, preferences.Allows(notification_entity.Outbid, notification_entity.ChannelEmail))
+		assert.False(mt, preferences.Allows(notification_entity.AuctionWon, notification_entity.ChannelInApp))
+	})
+}
+
+func TestMarkAsRead(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return not found for notifications of other users", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		repo := &NotificationRepository{Collection: mt.Coll}
+
+		err := repo.MarkAsRead(context.Background(), "user-1", "notification-1", time.Now())
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
@@ -0,0 +1,90 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrderEntityMongo struct {
+	Id           string                   `bson:"_id"`
+	AuctionId    string                   `bson:"auction_id"`
+	SellerId     string                   `bson:"seller_id,omitempty"`
+	BuyerId      string                   `bson:"buyer_id"`
+	BidId        string                   `bson:"bid_id"`
+	Amount       int64                    `bson:"amount"`
+	Currency     string                   `bson:"currency"`
+	Status       order_entity.OrderStatus `bson:"status"`
+	SecondChance bool                     `bson:"second_chance"`
+	PaymentId    string                   `bson:"payment_id,omitempty"`
+	TrackingCode string                   `bson:"tracking_code,omitempty"`
+	PaymentDueAt int64                    `bson:"payment_due_at"`
+	CreatedAt    int64                    `bson:"created_at"`
+	UpdatedAt    int64                    `bson:"updated_at"`
+}
+
+type OrderRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewOrderRepository(database *mongo.Database) *OrderRepository {
+	return &OrderRepository{
+		Collection: database.Collection("orders"),
+	}
+}
+
+func (rp *OrderRepository) CreateOrder(
+	ctx context.Context, order *order_entity.Order) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OrderRepository.CreateOrder")
+	defer span.End()
+
+	orderEntityMongo := &OrderEntityMongo{
+		Id:           order.Id,
+		AuctionId:    order.AuctionId,
+		SellerId:     order.SellerId,
+		BuyerId:      order.BuyerId,
+		BidId:        order.BidId,
+		Amount:       order.Amount.Amount,
+		Currency:     string(order.Amount.Currency),
+		Status:       order.Status,
+		SecondChance: order.SecondChance,
+		PaymentDueAt: order.PaymentDueAt.Unix(),
+		CreatedAt:    order.CreatedAt.Unix(),
+		UpdatedAt:    order.UpdatedAt.Unix(),
+	}
+
+	if _, err := rp.Collection.InsertOne(ctx, orderEntityMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError("Auction already has this order")
+		}
+		logger.Error("Error trying to insert order", err)
+		return internal_error.NewInternalServerError("Error trying to insert order")
+	}
+
+	return nil
+}
+
+func toOrderEntity(order OrderEntityMongo) *order_entity.Order {
+	return &order_entity.Order{
+		Id:           order.Id,
+		AuctionId:    order.AuctionId,
+		SellerId:     order.SellerId,
+		BuyerId:      order.BuyerId,
+		BidId:        order.BidId,
+		Amount:       money_entity.New(order.Amount, money_entity.Currency(order.Currency)),
+		Status:       order.Status,
+		SecondChance: order.SecondChance,
+		PaymentId:    order.PaymentId,
+		TrackingCode: order.TrackingCode,
+		PaymentDueAt: time.Unix(order.PaymentDueAt, 0),
+		CreatedAt:    time.Unix(order.CreatedAt, 0),
+		UpdatedAt:    time.Unix(order.UpdatedAt, 0),
+	}
+}
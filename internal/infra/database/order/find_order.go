@@ -0,0 +1,144 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (rp *OrderRepository) FindOrderById(
+	ctx context.Context, id string) (*order_entity.Order, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderRepository.FindOrderById")
+	defer span.End()
+
+	var orderEntityMongo OrderEntityMongo
+	err := rp.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&orderEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("Order not found with this id = %s", id))
+	}
+	if err != nil {
+		logger.Error("Error trying to find order by id", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find order by id")
+	}
+
+	return toOrderEntity(orderEntityMongo), nil
+}
+
+// FindOrders lists the orders matching filter, newest first.
+func (rp *OrderRepository) FindOrders(
+	ctx context.Context,
+	filter order_entity.OrderFilter,
+	page pagination_entity.PageRequest) ([]order_entity.Order, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderRepository.FindOrders")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "created_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	mongoFilter := bson.M{}
+	if filter.BuyerId != "" {
+		mongoFilter["buyer_id"] = filter.BuyerId
+	}
+	if filter.SellerId != "" {
+		mongoFilter["seller_id"] = filter.SellerId
+	}
+	if filter.Status != "" {
+		mongoFilter["status"] = filter.Status
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := rp.Collection.Find(ctx, pagination.WithCursor(mongoFilter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find orders", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find orders")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var ordersMongo []OrderEntityMongo
+	if err := mongoCursor.All(ctx, &ordersMongo); err != nil {
+		logger.Error("Error trying to decode orders", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find orders")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(ordersMongo)) > limit {
+		ordersMongo = ordersMongo[:limit]
+		last := ordersMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.CreatedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := rp.Collection.CountDocuments(ctx, mongoFilter)
+		if err != nil {
+			logger.Error("Error trying to count orders", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count orders")
+		}
+		pageInfo.Total = &total
+	}
+
+	var orders []order_entity.Order
+	for _, orderEntityMongo := range ordersMongo {
+		orders = append(orders, *toOrderEntity(orderEntityMongo))
+	}
+
+	return orders, pageInfo, nil
+}
+
+// FindOverdueOrders returns up to limit orders still awaiting a payment whose
+// deadline passed, oldest deadline first.
+func (rp *OrderRepository) FindOverdueOrders(
+	ctx context.Context, now time.Time, limit int64) ([]order_entity.Order, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderRepository.FindOverdueOrders")
+	defer span.End()
+
+	filter := bson.M{
+		"status":         order_entity.AwaitingPayment,
+		"payment_due_at": bson.M{"$lte": now.Unix()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "payment_due_at", Value: 1}}).SetLimit(limit)
+
+	mongoCursor, err := rp.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error trying to find overdue orders", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find overdue orders")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var ordersMongo []OrderEntityMongo
+	if err := mongoCursor.All(ctx, &ordersMongo); err != nil {
+		logger.Error("Error trying to decode overdue orders", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find overdue orders")
+	}
+
+	orders := make([]order_entity.Order, 0, len(ordersMongo))
+	for _, orderEntityMongo := range ordersMongo {
+		orders = append(orders, *toOrderEntity(orderEntityMongo))
+	}
+
+	return orders, nil
+}
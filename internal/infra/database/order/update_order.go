@@ -0,0 +1,68 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UpdateOrderStatus applies action only when the order is in the status it
+// starts from, so a payment and its timeout cannot both succeed.
+func (rp *OrderRepository) UpdateOrderStatus(
+	ctx context.Context,
+	id string,
+	action order_entity.OrderAction,
+	changes order_entity.OrderChanges) (*order_entity.Order, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderRepository.UpdateOrderStatus")
+	defer span.End()
+
+	from, to, ok := action.Transition()
+	if !ok {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("Action %s is not valid", action))
+	}
+
+	set := bson.M{"status": to, "updated_at": time.Now().Unix()}
+	if changes.PaymentId != "" {
+		set["payment_id"] = changes.PaymentId
+	}
+	if changes.TrackingCode != "" {
+		set["tracking_code"] = changes.TrackingCode
+	}
+
+	filter := bson.M{"_id": id, "status": from}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var orderEntityMongo OrderEntityMongo
+	err := rp.Collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&orderEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, rp.statusConflict(ctx, id, to)
+	}
+	if err != nil {
+		logger.Error("Error trying to update order status", err)
+		return nil, internal_error.NewInternalServerError("Error trying to update order status")
+	}
+
+	return toOrderEntity(orderEntityMongo), nil
+}
+
+// statusConflict explains why a status change did not match any order.
+func (rp *OrderRepository) statusConflict(
+	ctx context.Context, id string, to order_entity.OrderStatus) *internal_error.InternalError {
+	current, err := rp.FindOrderById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return internal_error.NewConflictError(fmt.Sprintf(
+		"Order cannot become %s while it is %s", to, current.Status))
+}
@@ -0,0 +1,265 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/transaction"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventEntityMongo keeps OccurredAt in nanoseconds, so events appended within
+// the same second are numbered in the order they happened.
+type EventEntityMongo struct {
+	Id         string `bson:"_id"`
+	Sequence   int64  `bson:"sequence"`
+	Type       string `bson:"type"`
+	AuctionId  string `bson:"auction_id"`
+	Payload    string `bson:"payload"`
+	OccurredAt int64  `bson:"occurred_at"`
+}
+
+type OffsetEntityMongo struct {
+	Consumer  string `bson:"_id"`
+	Sequence  int64  `bson:"sequence"`
+	UpdatedAt int64  `bson:"updated_at"`
+}
+
+// DeadLetterEventEntityMongo is an event a consumer gave up on, kept once per
+// consumer.
+type DeadLetterEventEntityMongo struct {
+	Id        string `bson:"_id"`
+	Consumer  string `bson:"consumer"`
+	EventId   string `bson:"event_id"`
+	Sequence  int64  `bson:"sequence"`
+	Type      string `bson:"type"`
+	AuctionId string `bson:"auction_id"`
+	Payload   string `bson:"payload"`
+	Detail    string `bson:"detail"`
+	FailedAt  int64  `bson:"failed_at"`
+}
+
+type OutboxRepository struct {
+	Collection           *mongo.Collection
+	OffsetCollection     *mongo.Collection
+	DeadLetterCollection *mongo.Collection
+}
+
+func NewOutboxRepository(database *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{
+		Collection:           database.Collection("outbox"),
+		OffsetCollection:     database.Collection("outbox_offsets"),
+		DeadLetterCollection: database.Collection("outbox_dead_letter"),
+	}
+}
+
+func (ob *OutboxRepository) AppendEvents(
+	ctx context.Context, events ...outbox_entity.Event) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.AppendEvents")
+	defer span.End()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		documents = append(documents, &EventEntityMongo{
+			Id:         event.Id,
+			Type:       string(event.Type),
+			AuctionId:  event.AuctionId,
+			Payload:    string(event.Payload),
+			OccurredAt: event.OccurredAt.UnixNano(),
+		})
+	}
+
+	if _, err := ob.Collection.InsertMany(ctx, documents); err != nil {
+		logger.Error("Error trying to append outbox events", err)
+		return internal_error.NewInternalServerError("Error trying to append outbox events")
+	}
+
+	return nil
+}
+
+// WithEvents joins the transaction already running in ctx, if any, so the
+// writes of a repository can be combined with those of another one.
+func (ob *OutboxRepository) WithEvents(
+	ctx context.Context, write outbox_entity.WriteFunc) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.WithEvents")
+	defer span.End()
+
+	return transaction.Run(ctx, ob.Collection.Database().Client(), func(ctx context.Context) *internal_error.InternalError {
+		events, err := write(ctx)
+		if err != nil {
+			return err
+		}
+
+		return ob.AppendEvents(ctx, events...)
+	})
+}
+
+// SequenceEvents numbers the events one at a time after the highest sequence.
+// The sequence is unique, so when another relay numbers an event first the
+// remaining ones are left to the next run instead of leaving a gap.
+func (ob *OutboxRepository) SequenceEvents(
+	ctx context.Context, limit int) (int, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.SequenceEvents")
+	defer span.End()
+
+	last, err := ob.lastSequence(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	numbered := 0
+	for numbered < limit {
+		update := bson.M{"$set": bson.M{"sequence": last + 1}}
+
+		err := ob.Collection.FindOneAndUpdate(ctx, bson.M{"sequence": 0}, update, opts).Err()
+		if errors.Is(err, mongo.ErrNoDocuments) || mongo.IsDuplicateKeyError(err) {
+			return numbered, nil
+		}
+		if err != nil {
+			logger.Error("Error trying to sequence outbox events", err)
+			return numbered, internal_error.NewInternalServerError("Error trying to sequence outbox events")
+		}
+
+		last++
+		numbered++
+	}
+
+	return numbered, nil
+}
+
+func (ob *OutboxRepository) lastSequence(ctx context.Context) (int64, *internal_error.InternalError) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "sequence", Value: -1}}).
+		SetProjection(bson.M{"sequence": 1})
+
+	var eventMongo EventEntityMongo
+	err := ob.Collection.FindOne(ctx, bson.M{"sequence": bson.M{"$gt": 0}}, opts).Decode(&eventMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find the last outbox sequence", err)
+		return 0, internal_error.NewInternalServerError("Error trying to find the last outbox sequence")
+	}
+
+	return eventMongo.Sequence, nil
+}
+
+func (ob *OutboxRepository) FindEventsAfter(
+	ctx context.Context, sequence int64, limit int) ([]outbox_entity.Event, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.FindEventsAfter")
+	defer span.End()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := ob.Collection.Find(ctx, bson.M{"sequence": bson.M{"$gt": sequence}}, opts)
+	if err != nil {
+		logger.Error("Error trying to find outbox events", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find outbox events")
+	}
+	defer cursor.Close(ctx)
+
+	var eventsMongo []EventEntityMongo
+	if err := cursor.All(ctx, &eventsMongo); err != nil {
+		logger.Error("Error trying to decode outbox events", err)
+		return nil, internal_error.NewInternalServerError("Error trying to decode outbox events")
+	}
+
+	events := make([]outbox_entity.Event, 0, len(eventsMongo))
+	for _, eventMongo := range eventsMongo {
+		events = append(events, outbox_entity.Event{
+			Id:         eventMongo.Id,
+			Sequence:   eventMongo.Sequence,
+			Type:       outbox_entity.EventType(eventMongo.Type),
+			AuctionId:  eventMongo.AuctionId,
+			Payload:    []byte(eventMongo.Payload),
+			OccurredAt: time.Unix(0, eventMongo.OccurredAt),
+		})
+	}
+
+	return events, nil
+}
+
+func (ob *OutboxRepository) FindOffset(
+	ctx context.Context, consumer string) (int64, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.FindOffset")
+	defer span.End()
+
+	var offsetMongo OffsetEntityMongo
+	err := ob.OffsetCollection.FindOne(ctx, bson.M{"_id": consumer}).Decode(&offsetMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find the outbox offset of "+consumer, err)
+		return 0, internal_error.NewInternalServerError("Error trying to find outbox offset")
+	}
+
+	return offsetMongo.Sequence, nil
+}
+
+// UpdateOffset never moves an offset back, so a consumer running late in
+// another instance cannot make the events be handled again.
+func (ob *OutboxRepository) UpdateOffset(
+	ctx context.Context, consumer string, sequence int64) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.UpdateOffset")
+	defer span.End()
+
+	update := bson.M{
+		"$max": bson.M{"sequence": sequence},
+		"$set": bson.M{"updated_at": time.Now().Unix()},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	if _, err := ob.OffsetCollection.UpdateOne(ctx, bson.M{"_id": consumer}, update, opts); err != nil {
+		logger.Error("Error trying to update the outbox offset of "+consumer, err)
+		return internal_error.NewInternalServerError("Error trying to update outbox offset")
+	}
+
+	return nil
+}
+
+// DeadLetterEvent replaces the entry of an event the consumer already gave up
+// on, as a consumer may give up again after its offset failed to be stored.
+func (ob *OutboxRepository) DeadLetterEvent(
+	ctx context.Context, consumer string, event outbox_entity.Event, detail string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.DeadLetterEvent")
+	defer span.End()
+
+	deadLetterMongo := &DeadLetterEventEntityMongo{
+		Id:        consumer + ":" + event.Id,
+		Consumer:  consumer,
+		EventId:   event.Id,
+		Sequence:  event.Sequence,
+		Type:      string(event.Type),
+		AuctionId: event.AuctionId,
+		Payload:   string(event.Payload),
+		Detail:    detail,
+		FailedAt:  time.Now().Unix(),
+	}
+	opts := options.Replace().SetUpsert(true)
+
+	_, err := ob.DeadLetterCollection.ReplaceOne(ctx, bson.M{"_id": deadLetterMongo.Id}, deadLetterMongo, opts)
+	if err != nil {
+		logger.Error("Error trying to dead-letter the outbox event of "+consumer, err)
+		return internal_error.NewInternalServerError("Error trying to dead-letter outbox event")
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSequenceEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should number the events after the last sequence", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.outbox", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "event-1"}, {Key: "sequence", Value: int64(41)}}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: "event-2"}}}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: "event-3"}}}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+		)
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		numbered, err := repo.SequenceEvents(context.Background(), 10)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, 2, numbered)
+	})
+
+	mt.Run("should stop at the limit", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.outbox", mtest.FirstBatch),
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: "event-1"}}}},
+		)
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		numbered, err := repo.SequenceEvents(context.Background(), 1)
+
+		require.Nil(mt, err)
+		assert.Equal(mt, 1, numbered)
+	})
+
+	mt.Run("should leave the events to the next run when another relay took the sequence", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.outbox", mtest.FirstBatch),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Message: "duplicate key"}),
+		)
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		numbered, err := repo.SequenceEvents(context.Background(), 10)
+
+		assert.Nil(mt, err)
+		assert.Zero(mt, numbered)
+	})
+}
+
+func TestFindEventsAfter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the numbered events", func(mt *mtest.T) {
+		occurredAt := time.Unix(1700000000, 123456789)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.outbox", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "event-1"},
+			{Key: "sequence", Value: int64(7)},
+			{Key: "type", Value: "auction.closed"},
+			{Key: "auction_id", Value: "auction-1"},
+			{Key: "payload", Value: `{"status":"completed"}`},
+			{Key: "occurred_at", Value: occurredAt.UnixNano()},
+		}))
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		events, err := repo.FindEventsAfter(context.Background(), 6, 100)
+
+		require.Nil(mt, err)
+		require.Len(mt, events, 1)
+		assert.Equal(mt, outbox_entity.Event{
+			Id:         "event-1",
+			Sequence:   7,
+			Type:       outbox_entity.AuctionClosed,
+			AuctionId:  "auction-1",
+			Payload:    []byte(`{"status":"completed"}`),
+			OccurredAt: occurredAt,
+		}, events[0])
+	})
+}
+
+func TestFindOffset(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the stored offset", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.outbox_offsets", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "webhooks"}, {Key: "sequence", Value: int64(12)}}))
+		repo := &OutboxRepository{OffsetCollection: mt.Coll}
+
+		offset, err := repo.FindOffset(context.Background(), "webhooks")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, int64(12), offset)
+	})
+
+	mt.Run("should start new consumers from the beginning", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.outbox_offsets", mtest.FirstBatch))
+		repo := &OutboxRepository{OffsetCollection: mt.Coll}
+
+		offset, err := repo.FindOffset(context.Background(), "webhooks")
+
+		require.Nil(mt, err)
+		assert.Zero(mt, offset)
+	})
+}
+
+func TestAppendEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return internal error when the insert fails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "insert error"}))
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		event, _ := outbox_entity.NewStatusChanged("auction-1", auction_entity.Completed)
+		err := repo.AppendEvents(context.Background(), event)
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "Error trying to append outbox events", err.Message)
+	})
+}
+
+func TestWithEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	event, _ := outbox_entity.NewStatusChanged("auction-1", auction_entity.Completed)
+
+	mt.Run("should commit the change and its events together", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		err := repo.WithEvents(context.Background(),
+			func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+				if _, err := mt.DB.Collection("auctions").InsertOne(ctx, bson.M{"_id": "auction-1"}); err != nil {
+					return nil, internal_error.NewInternalServerError(err.Error())
+				}
+				return []outbox_entity.Event{event}, nil
+			})
+
+		require.Nil(mt, err)
+		started := mt.GetAllStartedEvents()
+		require.Len(mt, started, 3)
+		assert.Equal(mt, "insert", started[0].CommandName)
+		assert.Equal(mt, "insert", started[1].CommandName)
+		assert.Equal(mt, "commitTransaction", started[2].CommandName)
+		_, inTransaction := started[1].Command.Lookup("txnNumber").Int64OK()
+		assert.True(mt, inTransaction, "events should be appended in the transaction")
+	})
+
+	mt.Run("should append no events when the change fails", func(mt *mtest.T) {
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		err := repo.WithEvents(context.Background(),
+			func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+				return nil, internal_error.NewConflictError("Auction is closed")
+			})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "conflict", err.Err)
+		assert.Empty(mt, mt.GetAllStartedEvents())
+	})
+
+	mt.Run("should return internal error when the events cannot be appended", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "insert error"}))
+		repo := &OutboxRepository{Collection: mt.Coll}
+
+		err := repo.WithEvents(context.Background(),
+			func(ctx context.Context) ([]outbox_entity.Event, *internal_error.InternalError) {
+				return []outbox_entity.Event{event}, nil
+			})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "Error trying to append outbox events", err.Message)
+	})
+}
+
+func TestDeadLetterEvent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	event, _ := outbox_entity.NewStatusChanged("auction-1", auction_entity.Completed)
+
+	mt.Run("should keep the event once per consumer", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		repo := &OutboxRepository{DeadLetterCollection: mt.Coll}
+
+		err := repo.DeadLetterEvent(context.Background(), "orders", event, "handler error")
+
+		require.Nil(mt, err)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(mt, "orders:"+event.Id, update.Lookup("q", "_id").StringValue())
+		assert.Equal(mt, "handler error", update.Lookup("u", "detail").StringValue())
+		assert.True(mt, update.Lookup("upsert").Boolean())
+	})
+
+	mt.Run("should return internal error when the write fails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Message: "write error"}))
+		repo := &OutboxRepository{DeadLetterCollection: mt.Coll}
+
+		err := repo.DeadLetterEvent(context.Background(), "orders", event, "handler error")
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "Error trying to dead-letter outbox event", err.Message)
+	})
+}
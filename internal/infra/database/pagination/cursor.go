@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor is the keyset position of the last document of a page: the value of
+// the sort field and the document id used as tie-breaker. The sort name is kept
+// so a cursor issued for one ordering cannot be replayed against another one.
+type Cursor struct {
+	Sort  string      `bson:"s"`
+	Value interface{} `bson:"v"`
+	Id    string      `bson:"id"`
+}
+
+func EncodeCursor(sort string, value interface{}, id string) string {
+	raw, err := bson.Marshal(Cursor{Sort: sort, Value: value, Id: id})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token, sort string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := bson.Unmarshal(raw, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.Sort != sort || cursor.Id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// KeysetFilter returns the condition that selects the documents placed after
+// the cursor for a sort on field (ties broken by _id) in the given direction.
+func KeysetFilter(field string, direction int, cursor *Cursor) bson.M {
+	operator := "$gt"
+	if direction < 0 {
+		operator = "$lt"
+	}
+
+	return bson.M{
+		"$or": bson.A{
+			bson.M{field: bson.M{operator: cursor.Value}},
+			bson.M{field: cursor.Value, "_id": bson.M{operator: cursor.Id}},
+		},
+	}
+}
+
+// SortOptions returns the sort document for field with _id as tie-breaker.
+func SortOptions(field string, direction int) bson.D {
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// WithCursor combines the base filter with the keyset condition of the cursor.
+func WithCursor(filter bson.M, field string, direction int, cursor *Cursor) bson.M {
+	if cursor == nil {
+		return filter
+	}
+
+	return bson.M{"$and": bson.A{filter, KeysetFilter(field, direction, cursor)}}
+}
@@ -0,0 +1,61 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should decode the same position that was encoded", func(t *testing.T) {
+		token := EncodeCursor("newest", int64(1700000000), "abc")
+
+		cursor, err := DecodeCursor(token, "newest")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1700000000), cursor.Value)
+		assert.Equal(t, "abc", cursor.Id)
+	})
+
+	t.Run("should keep float values when encoding prices", func(t *testing.T) {
+		token := EncodeCursor("highest_bid", 15.5, "abc")
+
+		cursor, err := DecodeCursor(token, "highest_bid")
+		require.NoError(t, err)
+		assert.Equal(t, 15.5, cursor.Value)
+	})
+
+	t.Run("should reject a cursor issued for another sort", func(t *testing.T) {
+		token := EncodeCursor("newest", int64(1), "abc")
+
+		_, err := DecodeCursor(token, "highest_bid")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("should reject a malformed cursor", func(t *testing.T) {
+		_, err := DecodeCursor("not a cursor", "newest")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestWithCursor(t *testing.T) {
+	t.Run("should return the base filter when there is no cursor", func(t *testing.T) {
+		filter := bson.M{"status": 0}
+		assert.Equal(t, filter, WithCursor(filter, "timestamp", -1, nil))
+	})
+
+	t.Run("should use $lt for descending sorts", func(t *testing.T) {
+		cursor := &Cursor{Value: int64(10), Id: "abc"}
+
+		filter := WithCursor(bson.M{}, "timestamp", -1, cursor)
+		expected := bson.M{"$and": bson.A{
+			bson.M{},
+			bson.M{"$or": bson.A{
+				bson.M{"timestamp": bson.M{"$lt": int64(10)}},
+				bson.M{"timestamp": int64(10), "_id": bson.M{"$lt": "abc"}},
+			}},
+		}}
+		assert.Equal(t, expected, filter)
+	})
+}
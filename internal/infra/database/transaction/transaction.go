@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Run runs write in a transaction of client, retried by the driver on
+// transient errors such as write conflicts. It joins the transaction already
+// running in ctx, if any, so the writes of several repositories commit
+// together.
+func Run(
+	ctx context.Context,
+	client *mongo.Client,
+	write func(ctx context.Context) *internal_error.InternalError) *internal_error.InternalError {
+	if mongo.SessionFromContext(ctx) != nil {
+		return write(ctx)
+	}
+
+	session, err := client.StartSession()
+	if err != nil {
+		logger.Error("Error trying to start session", err)
+		return internal_error.NewInternalServerError("Error trying to start transaction")
+	}
+	defer session.EndSession(ctx)
+
+	// The error of write is kept apart, as a nil *InternalError returned as an
+	// error would not compare equal to nil.
+	var writeErr *internal_error.InternalError
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		writeErr = write(sessionCtx)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		return nil, nil
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		logger.Error("Error trying to commit transaction", err)
+		return internal_error.NewInternalServerError("Error trying to commit transaction")
+	}
+
+	return nil
+}
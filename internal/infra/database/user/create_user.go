@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (ur *UserRepository) CreateUser(
+	ctx context.Context, user *user_entity.User) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "UserRepository.CreateUser")
+	defer span.End()
+
+	userEntityMongo := &UserEntityMongo{
+		Id:           user.Id,
+		Name:         user.Name,
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Roles:        user.RoleNames(),
+		Timestamp:    user.Timestamp.Unix(),
+	}
+
+	if _, err := ur.Collection.InsertOne(ctx, userEntityMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError(
+				fmt.Sprintf("Email %s is already in use", user.Email))
+		}
+
+		logger.Error("Error trying to insert user", err)
+		return internal_error.NewInternalServerError("Error trying to insert user")
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) UpdateUser(
+	ctx context.Context, user *user_entity.User) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "UserRepository.UpdateUser")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{
+		"name":          user.Name,
+		"email":         user.Email,
+		"password_hash": user.PasswordHash,
+	}}
+
+	result, err := ur.Collection.UpdateOne(ctx, bson.M{"_id": user.Id}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError(
+				fmt.Sprintf("Email %s is already in use", user.Email))
+		}
+
+		logger.Error("Error trying to update user", err)
+		return internal_error.NewInternalServerError("Error trying to update user")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", user.Id))
+	}
+
+	return nil
+}
+
+// UpdateUserRoles is kept apart from UpdateUser so profile changes can never
+// change the user permissions.
+func (ur *UserRepository) UpdateUserRoles(
+	ctx context.Context, userId string, roles []user_entity.Role) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "UserRepository.UpdateUserRoles")
+	defer span.End()
+
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, string(role))
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx,
+		bson.M{"_id": userId}, bson.M{"$set": bson.M{"roles": roleNames}})
+	if err != nil {
+		logger.Error("Error trying to update user roles", err)
+		return internal_error.NewInternalServerError("Error trying to update user roles")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	return nil
+}
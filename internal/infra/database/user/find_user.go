@@ -4,17 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserEntityMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
+	Id           string   `bson:"_id"`
+	Name         string   `bson:"name"`
+	Email        string   `bson:"email,omitempty"`
+	PasswordHash string   `bson:"password_hash,omitempty"`
+	Roles        []string `bson:"roles,omitempty"`
+	Timestamp    int64    `bson:"timestamp"`
 }
 
 type UserRepository struct {
@@ -29,6 +38,9 @@ func NewUserRepository(database *mongo.Database) *UserRepository {
 
 func (ur *UserRepository) FindUserById(
 	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.FindUserById")
+	defer span.End()
+
 	filter := bson.M{"_id": userId}
 
 	var userEntityMongo UserEntityMongo
@@ -44,10 +56,109 @@ func (ur *UserRepository) FindUserById(
 		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
 	}
 
-	userEntity := &user_entity.User{
-		Id:   userEntityMongo.Id,
-		Name: userEntityMongo.Name,
+	return toUserEntity(userEntityMongo), nil
+}
+
+func (ur *UserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.FindUserByEmail")
+	defer span.End()
+
+	filter := bson.M{"email": email}
+
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("User not found with this email = %s", email))
+		}
+
+		logger.Error("Error trying to find user by email", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find user by email")
+	}
+
+	return toUserEntity(userEntityMongo), nil
+}
+
+// FindUsers lists users from the newest to the oldest using a keyset cursor.
+func (ur *UserRepository) FindUsers(
+	ctx context.Context,
+	page pagination_entity.PageRequest) ([]user_entity.User, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.FindUsers")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "timestamp", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := ur.Collection.Find(ctx, pagination.WithCursor(bson.M{}, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error finding users", err)
+		return nil, nil, internal_error.NewInternalServerError("Error finding users")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var usersMongo []UserEntityMongo
+	if err := mongoCursor.All(ctx, &usersMongo); err != nil {
+		logger.Error("Error decoding users", err)
+		return nil, nil, internal_error.NewInternalServerError("Error decoding users")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(usersMongo)) > limit {
+		usersMongo = usersMongo[:limit]
+		last := usersMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.Timestamp, last.Id)
 	}
 
-	return userEntity, nil
+	if page.IncludeTotal {
+		total, err := ur.Collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			logger.Error("Error counting users", err)
+			return nil, nil, internal_error.NewInternalServerError("Error counting users")
+		}
+		pageInfo.Total = &total
+	}
+
+	var users []user_entity.User
+	for _, userEntityMongo := range usersMongo {
+		users = append(users, *toUserEntity(userEntityMongo))
+	}
+
+	return users, pageInfo, nil
+}
+
+// toUserEntity maps a stored user; users created before roles existed are
+// treated as having the default roles.
+func toUserEntity(userEntityMongo UserEntityMongo) *user_entity.User {
+	roles := user_entity.DefaultRoles
+	if len(userEntityMongo.Roles) > 0 {
+		roles = make([]user_entity.Role, 0, len(userEntityMongo.Roles))
+		for _, role := range userEntityMongo.Roles {
+			roles = append(roles, user_entity.Role(role))
+		}
+	}
+
+	return &user_entity.User{
+		Id:           userEntityMongo.Id,
+		Name:         userEntityMongo.Name,
+		Email:        userEntityMongo.Email,
+		PasswordHash: userEntityMongo.PasswordHash,
+		Roles:        roles,
+		Timestamp:    time.Unix(userEntityMongo.Timestamp, 0),
+	}
 }
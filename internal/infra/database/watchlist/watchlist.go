@@ -0,0 +1,177 @@
+package watchlist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/watchlist_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchedAuctionEntityMongo is keyed by user and auction, so watching an
+// auction twice keeps a single document.
+type WatchedAuctionEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	WatchedAt int64  `bson:"watched_at"`
+}
+
+type WatchlistRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewWatchlistRepository(database *mongo.Database) *WatchlistRepository {
+	return &WatchlistRepository{
+		Collection: database.Collection("watchlist"),
+	}
+}
+
+func watchedAuctionId(userId, auctionId string) string {
+	return userId + ":" + auctionId
+}
+
+func (wr *WatchlistRepository) WatchAuction(
+	ctx context.Context, watched *watchlist_entity.WatchedAuction) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.WatchAuction")
+	defer span.End()
+
+	id := watchedAuctionId(watched.UserId, watched.AuctionId)
+	update := bson.M{"$setOnInsert": WatchedAuctionEntityMongo{
+		Id:        id,
+		UserId:    watched.UserId,
+		AuctionId: watched.AuctionId,
+		WatchedAt: watched.WatchedAt.Unix(),
+	}}
+
+	_, err := wr.Collection.UpdateOne(ctx, bson.M{"_id": id}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		logger.Error("Error trying to watch auction", err)
+		return internal_error.NewInternalServerError("Error trying to watch auction")
+	}
+
+	return nil
+}
+
+func (wr *WatchlistRepository) UnwatchAuction(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.UnwatchAuction")
+	defer span.End()
+
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": watchedAuctionId(userId, auctionId)})
+	if err != nil {
+		logger.Error("Error trying to unwatch auction", err)
+		return internal_error.NewInternalServerError("Error trying to unwatch auction")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction %s is not in the watchlist", auctionId))
+	}
+
+	return nil
+}
+
+func (wr *WatchlistRepository) FindWatchlist(
+	ctx context.Context,
+	userId string,
+	page pagination_entity.PageRequest) ([]watchlist_entity.WatchedAuction, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.FindWatchlist")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "watched_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	filter := bson.M{"user_id": userId}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := wr.Collection.Find(ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find watchlist", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var watchedMongo []WatchedAuctionEntityMongo
+	if err := mongoCursor.All(ctx, &watchedMongo); err != nil {
+		logger.Error("Error trying to decode watchlist", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(watchedMongo)) > limit {
+		watchedMongo = watchedMongo[:limit]
+		last := watchedMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.WatchedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := wr.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error trying to count watchlist", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count watchlist")
+		}
+		pageInfo.Total = &total
+	}
+
+	watchlist := make([]watchlist_entity.WatchedAuction, 0, len(watchedMongo))
+	for _, watched := range watchedMongo {
+		watchlist = append(watchlist, watchlist_entity.WatchedAuction{
+			UserId:    watched.UserId,
+			AuctionId: watched.AuctionId,
+			WatchedAt: time.Unix(watched.WatchedAt, 0),
+		})
+	}
+
+	return watchlist, pageInfo, nil
+}
+
+func (wr *WatchlistRepository) FindWatchers(
+	ctx context.Context, auctionId string) ([]string, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.FindWatchers")
+	defer span.End()
+
+	opts := options.Find().SetProjection(bson.M{"user_id": 1})
+
+	mongoCursor, err := wr.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.Error("Error trying to find watchers", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find watchers")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var watchedMongo []WatchedAuctionEntityMongo
+	if err := mongoCursor.All(ctx, &watchedMongo); err != nil {
+		logger.Error("Error trying to decode watchers", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find watchers")
+	}
+
+	userIds := make([]string, 0, len(watchedMongo))
+	for _, watched := range watchedMongo {
+		userIds = append(userIds, watched.UserId)
+	}
+
+	return userIds, nil
+}
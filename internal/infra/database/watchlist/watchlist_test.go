@@ -0,0 +1,94 @@
+package watchlist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func watchedDocument(userId, auctionId string, watchedAt int64) bson.D {
+	return bson.D{
+		{Key: "_id", Value: watchedAuctionId(userId, auctionId)},
+		{Key: "user_id", Value: userId},
+		{Key: "auction_id", Value: auctionId},
+		{Key: "watched_at", Value: watchedAt},
+	}
+}
+
+func TestUnwatchAuction(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should remove a watched auction", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		repo := &WatchlistRepository{Collection: mt.Coll}
+
+		assert.Nil(mt, repo.UnwatchAuction(context.Background(), "user-1", "auction-1"))
+	})
+
+	mt.Run("should return not found when the auction is not watched", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+		repo := &WatchlistRepository{Collection: mt.Coll}
+
+		err := repo.UnwatchAuction(context.Background(), "user-1", "auction-1")
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
+
+func TestFindWatchlist(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return a page and the cursor of the next one", func(mt *mtest.T) {
+		now := time.Now().Unix()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.watchlist", mtest.FirstBatch,
+			watchedDocument("user-1", "auction-2", now),
+			watchedDocument("user-1", "auction-1", now-10),
+		))
+		repo := &WatchlistRepository{Collection: mt.Coll}
+
+		watchlist, pageInfo, err := repo.FindWatchlist(
+			context.Background(), "user-1", pagination_entity.PageRequest{Limit: 1})
+
+		require.Nil(mt, err)
+		require.Len(mt, watchlist, 1)
+		assert.Equal(mt, "auction-2", watchlist[0].AuctionId)
+		assert.Equal(mt, now, watchlist[0].WatchedAt.Unix())
+		assert.True(mt, pageInfo.HasMore)
+		assert.NotEmpty(mt, pageInfo.NextCursor)
+	})
+
+	mt.Run("should reject cursors of other listings", func(mt *mtest.T) {
+		repo := &WatchlistRepository{Collection: mt.Coll}
+
+		_, _, err := repo.FindWatchlist(
+			context.Background(), "user-1", pagination_entity.PageRequest{Cursor: "invalid"})
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "bad_request", err.Err)
+	})
+}
+
+func TestFindWatchers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the users watching the auction", func(mt *mtest.T) {
+		now := time.Now().Unix()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.watchlist", mtest.FirstBatch,
+			watchedDocument("user-1", "auction-1", now),
+			watchedDocument("user-2", "auction-1", now),
+		))
+		repo := &WatchlistRepository{Collection: mt.Coll}
+
+		userIds, err := repo.FindWatchers(context.Background(), "auction-1")
+
+		require.Nil(mt, err)
+		assert.Equal(mt, []string{"user-1", "user-2"}, userIds)
+	})
+}
@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeliveryEntityMongo keeps the payload as sent, so redeliveries carry the
+// same body and the same signature input.
+type DeliveryEntityMongo struct {
+	Id             string `bson:"_id"`
+	WebhookId      string `bson:"webhook_id"`
+	EventId        string `bson:"event_id"`
+	Event          string `bson:"event"`
+	Payload        string `bson:"payload"`
+	Status         string `bson:"status"`
+	Attempts       int    `bson:"attempts"`
+	NextAttemptAt  int64  `bson:"next_attempt_at"`
+	LastStatusCode int    `bson:"last_status_code,omitempty"`
+	LastError      string `bson:"last_error,omitempty"`
+	RedeliveryOf   string `bson:"redelivery_of,omitempty"`
+	CreatedAt      int64  `bson:"created_at"`
+	UpdatedAt      int64  `bson:"updated_at"`
+}
+
+func (wr *WebhookRepository) CreateDeliveries(
+	ctx context.Context, deliveries []webhook_entity.Delivery) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		documents = append(documents, toDeliveryEntityMongo(delivery))
+	}
+
+	if _, err := wr.DeliveryCollection.InsertMany(ctx, documents); err != nil {
+		logger.Error("Error trying to insert webhook deliveries", err)
+		return internal_error.NewInternalServerError("Error trying to insert webhook deliveries")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) ClaimDueDelivery(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration) (*webhook_entity.Delivery, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ClaimDueDelivery")
+	defer span.End()
+
+	filter := bson.M{
+		"status":          string(webhook_entity.DeliveryPending),
+		"next_attempt_at": bson.M{"$lte": now.Unix()},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease).Unix()}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+	var deliveryMongo DeliveryEntityMongo
+	err := wr.DeliveryCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&deliveryMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to claim webhook delivery", err)
+		return nil, internal_error.NewInternalServerError("Error trying to claim webhook delivery")
+	}
+
+	return toDeliveryEntity(deliveryMongo), nil
+}
+
+func (wr *WebhookRepository) UpdateDelivery(
+	ctx context.Context, delivery *webhook_entity.Delivery) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.UpdateDelivery")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{
+		"status":           string(delivery.Status),
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  delivery.NextAttemptAt.Unix(),
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"updated_at":       delivery.UpdatedAt.Unix(),
+	}}
+
+	if _, err := wr.DeliveryCollection.UpdateOne(ctx, bson.M{"_id": delivery.Id}, update); err != nil {
+		logger.Error("Error trying to update webhook delivery", err)
+		return internal_error.NewInternalServerError("Error trying to update webhook delivery")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) FindDeliveryById(
+	ctx context.Context, webhookId, id string) (*webhook_entity.Delivery, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.FindDeliveryById")
+	defer span.End()
+
+	var deliveryMongo DeliveryEntityMongo
+	err := wr.DeliveryCollection.FindOne(ctx, bson.M{"_id": id, "webhook_id": webhookId}).Decode(&deliveryMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("Webhook delivery not found with this id = %s", id))
+	}
+	if err != nil {
+		logger.Error("Error trying to find webhook delivery", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook delivery")
+	}
+
+	return toDeliveryEntity(deliveryMongo), nil
+}
+
+func (wr *WebhookRepository) FindDeliveries(
+	ctx context.Context,
+	webhookId string,
+	status webhook_entity.DeliveryStatus,
+	page pagination_entity.PageRequest) ([]webhook_entity.Delivery, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.FindDeliveries")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "created_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	filter := bson.M{"webhook_id": webhookId}
+	if status != "" {
+		filter["status"] = string(status)
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := wr.DeliveryCollection.Find(
+		ctx, pagination.WithCursor(filter, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find webhook deliveries", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find webhook deliveries")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var deliveriesMongo []DeliveryEntityMongo
+	if err := mongoCursor.All(ctx, &deliveriesMongo); err != nil {
+		logger.Error("Error trying to decode webhook deliveries", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find webhook deliveries")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(deliveriesMongo)) > limit {
+		deliveriesMongo = deliveriesMongo[:limit]
+		last := deliveriesMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.CreatedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := wr.DeliveryCollection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error trying to count webhook deliveries", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count webhook deliveries")
+		}
+		pageInfo.Total = &total
+	}
+
+	deliveries := make([]webhook_entity.Delivery, 0, len(deliveriesMongo))
+	for _, deliveryMongo := range deliveriesMongo {
+		deliveries = append(deliveries, *toDeliveryEntity(deliveryMongo))
+	}
+
+	return deliveries, pageInfo, nil
+}
+
+func toDeliveryEntityMongo(delivery webhook_entity.Delivery) DeliveryEntityMongo {
+	return DeliveryEntityMongo{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		Event:          string(delivery.Event),
+		Payload:        string(delivery.Payload),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt.Unix(),
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		CreatedAt:      delivery.CreatedAt.Unix(),
+		UpdatedAt:      delivery.UpdatedAt.Unix(),
+	}
+}
+
+func toDeliveryEntity(deliveryMongo DeliveryEntityMongo) *webhook_entity.Delivery {
+	return &webhook_entity.Delivery{
+		Id:             deliveryMongo.Id,
+		WebhookId:      deliveryMongo.WebhookId,
+		EventId:        deliveryMongo.EventId,
+		Event:          webhook_entity.EventType(deliveryMongo.Event),
+		Payload:        []byte(deliveryMongo.Payload),
+		Status:         webhook_entity.DeliveryStatus(deliveryMongo.Status),
+		Attempts:       deliveryMongo.Attempts,
+		NextAttemptAt:  time.Unix(deliveryMongo.NextAttemptAt, 0),
+		LastStatusCode: deliveryMongo.LastStatusCode,
+		LastError:      deliveryMongo.LastError,
+		RedeliveryOf:   deliveryMongo.RedeliveryOf,
+		CreatedAt:      time.Unix(deliveryMongo.CreatedAt, 0),
+		UpdatedAt:      time.Unix(deliveryMongo.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestClaimDueDelivery(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Now()
+
+	mt.Run("should claim the delivery due the longest", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: "delivery-1"},
+				{Key: "webhook_id", Value: "webhook-1"},
+				{Key: "event_id", Value: "event-1"},
+				{Key: "event", Value: "bid.accepted"},
+				{Key: "payload", Value: `{"id":"event-1"}`},
+				{Key: "status", Value: "pending"},
+				{Key: "attempts", Value: 2},
+				{Key: "next_attempt_at", Value: now.Unix()},
+				{Key: "created_at", Value: now.Unix()},
+				{Key: "updated_at", Value: now.Unix()},
+			}},
+		})
+		repo := &WebhookRepository{DeliveryCollection: mt.Coll}
+
+		delivery, err := repo.ClaimDueDelivery(context.Background(), now, time.Minute)
+
+		require.Nil(mt, err)
+		require.NotNil(mt, delivery)
+		assert.Equal(mt, "delivery-1", delivery.Id)
+		assert.Equal(mt, webhook_entity.BidAccepted, delivery.Event)
+		assert.Equal(mt, []byte(`{"id":"event-1"}`), delivery.Payload)
+		assert.Equal(mt, 2, delivery.Attempts)
+	})
+
+	mt.Run("should return nil when no delivery is due", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		repo := &WebhookRepository{DeliveryCollection: mt.Coll}
+
+		delivery, err := repo.ClaimDueDelivery(context.Background(), now, time.Minute)
+
+		assert.Nil(mt, err)
+		assert.Nil(mt, delivery)
+	})
+}
+
+func TestFindDeliveryById(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return not found for deliveries of other webhooks", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.webhook_deliveries", mtest.FirstBatch))
+		repo := &WebhookRepository{DeliveryCollection: mt.Coll}
+
+		_, err := repo.FindDeliveryById(context.Background(), "webhook-1", "delivery-1")
+
+		require.NotNil(mt, err)
+		assert.Equal(mt, "not_found", err.Err)
+	})
+}
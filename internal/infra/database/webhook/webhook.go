@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/pagination"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookEntityMongo struct {
+	Id          string   `bson:"_id"`
+	Url         string   `bson:"url"`
+	Description string   `bson:"description,omitempty"`
+	Events      []string `bson:"events"`
+	Secret      string   `bson:"secret"`
+	CreatedAt   int64    `bson:"created_at"`
+}
+
+type WebhookRepository struct {
+	Collection         *mongo.Collection
+	DeliveryCollection *mongo.Collection
+}
+
+func NewWebhookRepository(database *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{
+		Collection:         database.Collection("webhooks"),
+		DeliveryCollection: database.Collection("webhook_deliveries"),
+	}
+}
+
+func (wr *WebhookRepository) CreateWebhook(
+	ctx context.Context, webhook *webhook_entity.Webhook) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateWebhook")
+	defer span.End()
+
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+
+	webhookEntityMongo := &WebhookEntityMongo{
+		Id:          webhook.Id,
+		Url:         webhook.Url,
+		Description: webhook.Description,
+		Events:      events,
+		Secret:      webhook.Secret,
+		CreatedAt:   webhook.CreatedAt.Unix(),
+	}
+
+	if _, err := wr.Collection.InsertOne(ctx, webhookEntityMongo); err != nil {
+		logger.Error("Error trying to insert webhook", err)
+		return internal_error.NewInternalServerError("Error trying to insert webhook")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) FindWebhookById(
+	ctx context.Context, id string) (*webhook_entity.Webhook, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.FindWebhookById")
+	defer span.End()
+
+	var webhookEntityMongo WebhookEntityMongo
+	err := wr.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhookEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("Webhook not found with this id = %s", id))
+	}
+	if err != nil {
+		logger.Error("Error trying to find webhook", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook")
+	}
+
+	return toWebhookEntity(webhookEntityMongo), nil
+}
+
+func (wr *WebhookRepository) FindWebhooks(
+	ctx context.Context,
+	page pagination_entity.PageRequest) ([]webhook_entity.Webhook, *pagination_entity.PageInfo, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.FindWebhooks")
+	defer span.End()
+
+	const sortName, sortField, direction = "newest", "created_at", -1
+
+	var cursor *pagination.Cursor
+	if page.Cursor != "" {
+		decoded, err := pagination.DecodeCursor(page.Cursor, sortName)
+		if err != nil {
+			return nil, nil, internal_error.NewBadRequestError("Invalid pagination cursor")
+		}
+		cursor = decoded
+	}
+
+	limit := page.NormalizedLimit()
+	opts := options.Find().
+		SetSort(pagination.SortOptions(sortField, direction)).
+		SetLimit(limit + 1)
+
+	mongoCursor, err := wr.Collection.Find(ctx, pagination.WithCursor(bson.M{}, sortField, direction, cursor), opts)
+	if err != nil {
+		logger.Error("Error trying to find webhooks", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var webhooksMongo []WebhookEntityMongo
+	if err := mongoCursor.All(ctx, &webhooksMongo); err != nil {
+		logger.Error("Error trying to decode webhooks", err)
+		return nil, nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+
+	pageInfo := &pagination_entity.PageInfo{Limit: limit}
+	if int64(len(webhooksMongo)) > limit {
+		webhooksMongo = webhooksMongo[:limit]
+		last := webhooksMongo[limit-1]
+		pageInfo.HasMore = true
+		pageInfo.NextCursor = pagination.EncodeCursor(sortName, last.CreatedAt, last.Id)
+	}
+
+	if page.IncludeTotal {
+		total, err := wr.Collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			logger.Error("Error trying to count webhooks", err)
+			return nil, nil, internal_error.NewInternalServerError("Error trying to count webhooks")
+		}
+		pageInfo.Total = &total
+	}
+
+	webhooks := make([]webhook_entity.Webhook, 0, len(webhooksMongo))
+	for _, webhookMongo := range webhooksMongo {
+		webhooks = append(webhooks, *toWebhookEntity(webhookMongo))
+	}
+
+	return webhooks, pageInfo, nil
+}
+
+func (wr *WebhookRepository) FindWebhooksByEvent(
+	ctx context.Context, event webhook_entity.EventType) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.FindWebhooksByEvent")
+	defer span.End()
+
+	mongoCursor, err := wr.Collection.Find(ctx, bson.M{"events": string(event)})
+	if err != nil {
+		logger.Error("Error trying to find webhooks by event", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+	defer mongoCursor.Close(ctx)
+
+	var webhooksMongo []WebhookEntityMongo
+	if err := mongoCursor.All(ctx, &webhooksMongo); err != nil {
+		logger.Error("Error trying to decode webhooks", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+
+	webhooks := make([]webhook_entity.Webhook, 0, len(webhooksMongo))
+	for _, webhookMongo := range webhooksMongo {
+		webhooks = append(webhooks, *toWebhookEntity(webhookMongo))
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook stops future deliveries; the delivery log is kept.
+func (wr *WebhookRepository) DeleteWebhook(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeleteWebhook")
+	defer span.End()
+
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error("Error trying to delete webhook", err)
+		return internal_error.NewInternalServerError("Error trying to delete webhook")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(fmt.Sprintf("Webhook not found with this id = %s", id))
+	}
+
+	return nil
+}
+
+func toWebhookEntity(webhookEntityMongo WebhookEntityMongo) *webhook_entity.Webhook {
+	events := make([]webhook_entity.EventType, 0, len(webhookEntityMongo.Events))
+	for _, event := range webhookEntityMongo.Events {
+		events = append(events, webhook_entity.EventType(event))
+	}
+
+	return &webhook_entity.Webhook{
+		Id:          webhookEntityMongo.Id,
+		Url:         webhookEntityMongo.Url,
+		Description: webhookEntityMongo.Description,
+		Events:      events,
+		Secret:      webhookEntityMongo.Secret,
+		CreatedAt:   time.Unix(webhookEntityMongo.CreatedAt, 0),
+	}
+}
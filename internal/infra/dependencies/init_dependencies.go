@@ -1,32 +1,402 @@
 package dependencies
 
 import (
+	"context"
+	"net/http"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/database/mongodb"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/health_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/api_key_controller"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/audit_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/auth_controller"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/category_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/credit_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/ledger_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/notification_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/order_controller"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/watchlist_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/controller/webhook_controller"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/auth"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/broker"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/api_key"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/auction"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/audit"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/bid"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/category"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/credit"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/ledger"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/notification"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/order"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/outbox"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/user"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/watchlist"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/database/webhook"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/events"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/fx"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/httpsender"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/mail"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/metrics"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/payment"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/ratelimit"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/api_key_usecase"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/audit_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auth_usecase"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/category_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/credit_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/health_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/ledger_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/notification_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/order_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/outbox_usecase"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/watchlist_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/webhook_usecase"
+	"github.com/nats-io/nats.go"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Dependencies groups everything the router needs to register the routes.
+type Dependencies struct {
+	UserController         *user_controller.UserController
+	BidController          *bid_controller.BidController
+	AuctionController      *auction_controller.AuctionController
+	CategoryController     *category_controller.CategoryController
+	AuthController         *auth_controller.AuthController
+	ApiKeyController       *api_key_controller.ApiKeyController
+	CreditController       *credit_controller.CreditController
+	LedgerController       *ledger_controller.LedgerController
+	OrderController        *order_controller.OrderController
+	WatchlistController    *watchlist_controller.WatchlistController
+	NotificationController *notification_controller.NotificationController
+	WebhookController      *webhook_controller.WebhookController
+	AuditController        *audit_controller.AuditController
+	HealthController       *health_controller.HealthController
+
+	Auth      *middleware.Auth
+	RateLimit *middleware.RateLimiter
+	Audit     *middleware.Audit
+
+	Metrics        metrics_entity.MetricsInterface
+	MetricsHandler http.Handler
+
+	// Health turns the readiness probe off when the application shuts down.
+	Health health_usecase.HealthUseCaseInterface
+
+	eventHub     *events.Hub
+	brokerConn   *nats.Conn
+	bidUseCase   bid_usecase.BidUseCaseInterface
+	orderUseCase order_usecase.OrderUseCaseInterface
+}
+
+// CloseStreams ends the server-sent event streams, which would otherwise keep
+// the HTTP server from shutting down.
+func (d *Dependencies) CloseStreams() {
+	d.eventHub.Close()
+}
+
+// DrainBroker stops taking bids from the broker, once those already received
+// are placed.
+func (d *Dependencies) DrainBroker(ctx context.Context) error {
+	if d.brokerConn == nil {
+		return nil
+	}
+
+	return broker.Drain(ctx, d.brokerConn)
+}
+
+// StopBids stores the bids still waiting for a batch. The HTTP server must be
+// shut down and the broker drained first; bids placed afterwards are refused.
+func (d *Dependencies) StopBids(ctx context.Context) *internal_error.InternalError {
+	return d.bidUseCase.Stop(ctx)
+}
+
+// StopOrders stops expiring the overdue orders, waiting for a sweep under way.
+func (d *Dependencies) StopOrders(ctx context.Context) *internal_error.InternalError {
+	return d.orderUseCase.Stop(ctx)
+}
+
+// Default budgets when RATE_LIMIT_<GROUP>_RATE and _BURST are not set.
+var (
+	defaultReadLimit = ratelimit.Limit{Rate: 20, Burst: 40}
+	defaultBidLimit  = ratelimit.Limit{Rate: 2, Burst: 10}
+	defaultAuthLimit = ratelimit.Limit{Rate: 0.1, Burst: 5}
 )
 
-func InitDependencies(database *mongo.Database) (
-	userController *user_controller.UserController,
-	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+func InitDependencies(database *mongo.Database, prometheusMetrics *metrics.Prometheus) (*Dependencies, error) {
+	tokenService, err := auth.NewJWTTokenServiceFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	rateProvider, err := fx.NewStaticProviderFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	smtpNotifier, err := mail.NewSMTPNotifierFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	brokerConn, err := broker.ConnectFromEnv()
+	if err != nil {
+		return nil, err
+	}
 
-	auctionRepository := auction.NewAuctionRepository(database)
-	bidRepository := bid.NewBidRepository(database, auctionRepository)
+	readLimit, err := ratelimit.LimitFromEnv("READ", defaultReadLimit)
+	if err != nil {
+		return nil, err
+	}
+	bidLimit, err := ratelimit.LimitFromEnv("BID", defaultBidLimit)
+	if err != nil {
+		return nil, err
+	}
+	authLimit, err := ratelimit.LimitFromEnv("AUTH", defaultAuthLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepository := outbox.NewOutboxRepository(database)
+	auctionRepository := auction.NewAuctionRepository(database, outboxRepository)
+	creditRepository := credit.NewCreditRepository(database, rateProvider)
+	ledgerRepository := ledger.NewLedgerRepository(database)
+	bidRepository := bid.NewBidRepository(
+		database, auctionRepository, creditRepository, outboxRepository, ledgerRepository, prometheusMetrics)
 	userRepository := user.NewUserRepository(database)
+	categoryRepository := category.NewCategoryRepository(database)
+	apiKeyRepository := api_key.NewApiKeyRepository(database)
+	orderRepository := order.NewOrderRepository(database)
+	watchlistRepository := watchlist.NewWatchlistRepository(database)
+
+	auditUseCase := audit_usecase.NewAuditUseCase(audit.NewAuditRepository(database))
+
+	orderUseCase := order_usecase.NewOrderUseCase(
+		orderRepository, auctionRepository, bidRepository, creditRepository, payment.NewFakeProvider(), auditUseCase)
+
+	eventHub := events.NewHub(events.DefaultHistorySize, events.DefaultSubscriberBuffer)
+	bidRepository.OnBidAccepted(publishBidEvents(eventHub))
+	auctionRepository.OnStatusChange(publishStatusEvents(eventHub))
+	auctionRepository.OnStatusChange(countClosedAuctions(prometheusMetrics))
+
+	notifiers := []notification_entity.NotifierInterface{notification.NewInboxNotifier(database)}
+	if smtpNotifier != nil {
+		notifiers = append(notifiers, smtpNotifier)
+	}
+	notificationUseCase := notification_usecase.NewNotificationUseCase(
+		notification.NewNotificationRepository(database),
+		userRepository, auctionRepository, watchlistRepository, notifiers...)
+
+	webhookUseCase := webhook_usecase.NewWebhookUseCase(
+		webhook.NewWebhookRepository(database), auctionRepository, httpsender.NewSender(httpsender.DefaultTimeout))
+
+	outboxUseCase := outbox_usecase.NewOutboxUseCase(outboxRepository)
+	outboxUseCase.Subscribe("orders", settleCompletedAuctions(orderUseCase))
+	outboxUseCase.Subscribe("notifications", notifyBidders(notificationUseCase))
+	outboxUseCase.Subscribe("webhooks", webhookUseCase.PublishEvent)
+
+	bidUseCase := bid_usecase.NewBidUseCase(
+		bidRepository, auctionRepository, creditRepository, rateProvider, prometheusMetrics)
+
+	if brokerConn != nil {
+		outboxUseCase.AddPublisher("broker", broker.NewNATSPublisherFromEnv(brokerConn))
+
+		if _, err := broker.NewBidSubscriber(bidUseCase, userRepository, auditUseCase).SubscribeFromEnv(brokerConn); err != nil {
+			return nil, err
+		}
+	}
+
+	authUseCase := auth_usecase.NewAuthUseCase(userRepository, apiKeyRepository, tokenService)
+	userUseCase := user_usecase.NewUserUseCase(userRepository)
+	auctionUseCase := auction_usecase.NewAuctionUseCase(
+		auctionRepository, bidRepository, categoryRepository, rateProvider, eventHub, prometheusMetrics)
+	categoryUseCase := category_usecase.NewCategoryUseCase(categoryRepository)
+	creditUseCase := credit_usecase.NewCreditUseCase(creditRepository, userRepository)
+
+	healthUseCase := health_usecase.NewHealthUseCase()
+	healthUseCase.AddCheck("mongodb", health_entity.CheckFunc(pingDatabase(database)))
+	healthUseCase.AddCheck("bid_worker", bidUseCase)
+	healthUseCase.AddCheck("auction_closing", auctionRepository)
+
+	auctionSnapshot := func(ctx context.Context, auctionId string) (any, *internal_error.InternalError) {
+		return auctionUseCase.FindAuctionById(ctx, auctionId, money_usecase.DisplayCurrencyInputDTO{})
+	}
+	auctionRepository.OnAutomaticClose(recordAutomaticClose(auditUseCase, auctionSnapshot))
+
+	auditMiddleware := middleware.NewAudit(auditUseCase)
+	auditMiddleware.Snapshot("auction", auctionSnapshot)
+	auditMiddleware.Snapshot("user", func(ctx context.Context, userId string) (any, *internal_error.InternalError) {
+		return userUseCase.FindUserById(ctx, userId)
+	})
+	auditMiddleware.Snapshot("credit", func(ctx context.Context, userId string) (any, *internal_error.InternalError) {
+		return creditUseCase.FindCreditAccount(ctx, userId)
+	})
+	auditMiddleware.Snapshot("category", func(ctx context.Context, categoryId string) (any, *internal_error.InternalError) {
+		return categoryUseCase.FindCategoryById(ctx, categoryId)
+	})
+	auditMiddleware.Snapshot("order", func(ctx context.Context, orderId string) (any, *internal_error.InternalError) {
+		return orderUseCase.FindOrderById(ctx, orderId)
+	})
+	auditMiddleware.Snapshot("webhook", func(ctx context.Context, webhookId string) (any, *internal_error.InternalError) {
+		return webhookUseCase.FindWebhookById(ctx, webhookId)
+	})
+	auditMiddleware.Snapshot("notification_preferences",
+		func(ctx context.Context, userId string) (any, *internal_error.InternalError) {
+			return notificationUseCase.FindPreferences(ctx, userId)
+		})
+
+	// Seeded only now, so the listeners of its close are already registered.
+	if err := mongodb.EnsureDevAuction(context.Background(), database, auctionRepository); err != nil {
+		return nil, err
+	}
+
+	return &Dependencies{
+		UserController:     user_controller.NewUserController(userUseCase),
+		AuctionController:  auction_controller.NewAuctionController(auctionUseCase),
+		BidController:      bid_controller.NewBidController(bidUseCase),
+		CategoryController: category_controller.NewCategoryController(categoryUseCase),
+		AuthController:     auth_controller.NewAuthController(authUseCase),
+		OrderController:    order_controller.NewOrderController(orderUseCase),
+		LedgerController: ledger_controller.NewLedgerController(
+			ledger_usecase.NewLedgerUseCase(ledgerRepository, auctionRepository, bidRepository, creditRepository)),
+		CreditController: credit_controller.NewCreditController(creditUseCase),
+		ApiKeyController: api_key_controller.NewApiKeyController(
+			api_key_usecase.NewApiKeyUseCase(apiKeyRepository, userRepository)),
+		WatchlistController: watchlist_controller.NewWatchlistController(
+			watchlist_usecase.NewWatchlistUseCase(watchlistRepository, auctionRepository, userRepository)),
+		NotificationController: notification_controller.NewNotificationController(notificationUseCase),
+		WebhookController:      webhook_controller.NewWebhookController(webhookUseCase),
+		AuditController:        audit_controller.NewAuditController(auditUseCase),
+		HealthController:       health_controller.NewHealthController(healthUseCase),
+		Auth:                   middleware.NewAuth(authUseCase),
+		Audit:                  auditMiddleware,
+		Metrics:                prometheusMetrics,
+		MetricsHandler:         prometheusMetrics.Handler(),
+		Health:                 healthUseCase,
+		RateLimit: middleware.NewRateLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{
+			middleware.RateLimitRead: readLimit,
+			middleware.RateLimitBid:  bidLimit,
+			middleware.RateLimitAuth: authLimit,
+		}),
+		eventHub:     eventHub,
+		brokerConn:   brokerConn,
+		bidUseCase:   bidUseCase,
+		orderUseCase: orderUseCase,
+	}, nil
+}
+
+// pingDatabase checks that the primary answers.
+func pingDatabase(database *mongo.Database) health_entity.CheckFunc {
+	return func(ctx context.Context) *internal_error.InternalError {
+		if err := database.Client().Ping(ctx, readpref.Primary()); err != nil {
+			return internal_error.NewInternalServerError(err.Error())
+		}
+		return nil
+	}
+}
+
+// settleCompletedAuctions opens the order of each auction completed by the
+// automatic closure or by an admin.
+func settleCompletedAuctions(orderUseCase order_usecase.OrderUseCaseInterface) outbox_usecase.Handler {
+	return func(ctx context.Context, event outbox_entity.Event) *internal_error.InternalError {
+		if event.Type != outbox_entity.AuctionClosed {
+			return nil
+		}
+
+		return orderUseCase.CreateAuctionOrder(ctx, event.AuctionId)
+	}
+}
+
+// recordAutomaticClose audits the auctions completed at their end, which no
+// request wraps.
+func recordAutomaticClose(
+	recorder audit_entity.RecorderInterface, snapshot middleware.SnapshotFunc) auction.CloseListener {
+	return func(auctionId string) {
+		ctx := context.Background()
+
+		var after any
+		if state, err := snapshot(ctx, auctionId); err == nil {
+			after = state
+		}
+
+		recorder.Record(ctx, audit_entity.NewEntry(ctx, "auction.close", "auction", auctionId, nil, after))
+	}
+}
+
+// publishBidEvents feeds the auction streams with the accepted bids and the
+// changes of leader. The streams stay on the repository listeners, as they are
+// live views with no delivery guarantee.
+func publishBidEvents(hub event_entity.HubInterface) bid.BidListener {
+	return func(bidEntity bid_entity.Bid, leading bool, previous *auction_entity.LeadingBid) {
+		hub.Publish(event_entity.NewBidAccepted(bidEntity))
+		if leading {
+			hub.Publish(event_entity.NewLeaderChanged(bidEntity, previous))
+		}
+	}
+}
+
+// publishStatusEvents feeds the auction streams with pauses, resumes and
+// closures, whether automatic or by an admin.
+func publishStatusEvents(hub event_entity.HubInterface) auction.StatusListener {
+	return func(auctionId string, status auction_entity.AuctionStatus) {
+		if event, ok := event_entity.NewStatusChanged(auctionId, status); ok {
+			hub.Publish(event)
+		}
+	}
+}
+
+// countClosedAuctions counts the auctions completed or cancelled, whether
+// automatically or by an admin.
+func countClosedAuctions(metrics metrics_entity.MetricsInterface) auction.StatusListener {
+	return func(_ string, status auction_entity.AuctionStatus) {
+		if status == auction_entity.Completed || status == auction_entity.Cancelled {
+			metrics.AuctionClosed(status.String())
+		}
+	}
+}
+
+// notifyBidders tells the previous leader they were outbid and the leader of
+// each completed auction they won.
+func notifyBidders(notificationUseCase notification_usecase.NotificationUseCaseInterface) outbox_usecase.Handler {
+	return func(ctx context.Context, event outbox_entity.Event) *internal_error.InternalError {
+		switch event.Type {
+		case outbox_entity.BidAccepted:
+			var data outbox_entity.BidData
+			if err := event.Decode(&data); err != nil {
+				return err
+			}
+
+			previous, err := data.Previous()
+			if err != nil || previous == nil {
+				return err
+			}
 
-	userController = user_controller.NewUserController(
-		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+			bidEntity, err := data.Bid(event.AuctionId)
+			if err != nil {
+				return err
+			}
 
-	return
+			return notificationUseCase.NotifyOutbid(ctx, *previous, bidEntity)
+		case outbox_entity.AuctionClosed:
+			return notificationUseCase.NotifyAuctionWon(ctx, event.AuctionId)
+		default:
+			return nil
+		}
+	}
 }
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+)
+
+// Default sizes of NewHub when not configured.
+const (
+	DefaultHistorySize      = 1024
+	DefaultSubscriberBuffer = 64
+)
+
+// Hub is an in-process pub/sub of auction events. It keeps the last events
+// published, across every auction, so subscribers can resume after a
+// reconnection. Event ids are "<epoch>-<sequence>": the epoch changes on every
+// start, and an id from a previous start replays every event still kept.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string
+	sequence    uint64
+	history     []event_entity.Event
+	historySize int
+	buffer      int
+	subscribers map[string]map[*subscriber]struct{}
+	closed      bool
+}
+
+type subscriber struct {
+	events chan event_entity.Event
+}
+
+func NewHub(historySize, subscriberBuffer int) *Hub {
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		buffer:      subscriberBuffer,
+		subscribers: make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+// Publish never blocks: a subscriber whose buffer is full is dropped, and
+// resumes from the history when it subscribes again.
+func (h *Hub) Publish(event event_entity.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sequence++
+	event.Id = fmt.Sprintf("%s-%d", h.epoch, h.sequence)
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subscribers[event.AuctionId] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(event.AuctionId, sub)
+		}
+	}
+}
+
+// Subscribe replays the kept events after lastEventId; without one, only new
+// events are received.
+func (h *Hub) Subscribe(auctionId, lastEventId string) *event_entity.Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []event_entity.Event
+	if lastEventId != "" {
+		epoch, sequence := parseEventId(lastEventId)
+		for _, event := range h.history {
+			if event.AuctionId != auctionId {
+				continue
+			}
+			if _, eventSequence := parseEventId(event.Id); epoch == h.epoch && eventSequence <= sequence {
+				continue
+			}
+			replay = append(replay, event)
+		}
+	}
+
+	sub := &subscriber{events: make(chan event_entity.Event, h.buffer)}
+	if h.closed {
+		close(sub.events)
+		return &event_entity.Subscription{Replay: replay, Events: sub.events, Close: func() {}}
+	}
+	if h.subscribers[auctionId] == nil {
+		h.subscribers[auctionId] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[auctionId][sub] = struct{}{}
+
+	return &event_entity.Subscription{
+		Replay: replay,
+		Events: sub.events,
+		Close: func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.remove(auctionId, sub)
+		},
+	}
+}
+
+// Close ends every subscription, and the ones made afterwards, so the streams
+// return and the HTTP server can shut down; clients resume elsewhere from the
+// last event they received.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for auctionId, subscribers := range h.subscribers {
+		for sub := range subscribers {
+			h.remove(auctionId, sub)
+		}
+	}
+}
+
+func (h *Hub) remove(auctionId string, sub *subscriber) {
+	subscribers, ok := h.subscribers[auctionId]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[sub]; !ok {
+		return
+	}
+
+	delete(subscribers, sub)
+	close(sub.events)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, auctionId)
+	}
+}
+
+func parseEventId(id string) (string, uint64) {
+	epoch, sequence, found := strings.Cut(id, "-")
+	if !found {
+		return "", 0
+	}
+
+	value, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return "", 0
+	}
+
+	return epoch, value
+}
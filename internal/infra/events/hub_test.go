@@ -0,0 +1,83 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	t.Run("should deliver the events of the subscribed auction only", func(t *testing.T) {
+		hub := NewHub(10, 10)
+		subscription := hub.Subscribe("auction-1", "")
+		defer subscription.Close()
+
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-2"})
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+
+		event := <-subscription.Events
+		assert.Equal(t, "auction-1", event.AuctionId)
+		assert.NotEmpty(t, event.Id)
+		assert.False(t, event.OccurredAt.IsZero())
+		assert.Empty(t, subscription.Events)
+	})
+
+	t.Run("should replay the events after the last one seen", func(t *testing.T) {
+		hub := NewHub(10, 10)
+		first := hub.Subscribe("auction-1", "")
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+		seen := <-first.Events
+		first.Close()
+
+		hub.Publish(event_entity.Event{Type: event_entity.LeaderChanged, AuctionId: "auction-1"})
+		hub.Publish(event_entity.Event{Type: event_entity.AuctionClosed, AuctionId: "auction-1"})
+
+		resumed := hub.Subscribe("auction-1", seen.Id)
+		defer resumed.Close()
+		require.Len(t, resumed.Replay, 2)
+		assert.Equal(t, event_entity.LeaderChanged, resumed.Replay[0].Type)
+		assert.Equal(t, event_entity.AuctionClosed, resumed.Replay[1].Type)
+	})
+
+	t.Run("should replay every kept event for ids of a previous start", func(t *testing.T) {
+		hub := NewHub(2, 10)
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+		hub.Publish(event_entity.Event{Type: event_entity.LeaderChanged, AuctionId: "auction-1"})
+
+		subscription := hub.Subscribe("auction-1", "old-99")
+		defer subscription.Close()
+		assert.Len(t, subscription.Replay, 2)
+	})
+
+	t.Run("should drop subscribers that fall behind", func(t *testing.T) {
+		hub := NewHub(10, 1)
+		subscription := hub.Subscribe("auction-1", "")
+
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+		hub.Publish(event_entity.Event{Type: event_entity.BidAccepted, AuctionId: "auction-1"})
+
+		<-subscription.Events
+		_, open := <-subscription.Events
+		assert.False(t, open)
+
+		subscription.Close()
+	})
+	t.Run("should end every subscription once closed", func(t *testing.T) {
+		hub := NewHub(10, 10)
+		subscription := hub.Subscribe("auction-1", "")
+		defer subscription.Close()
+
+		hub.Close()
+
+		_, open := <-subscription.Events
+		assert.False(t, open)
+
+		late := hub.Subscribe("auction-1", "")
+		defer late.Close()
+		_, open = <-late.Events
+		assert.False(t, open)
+	})
+}
@@ -0,0 +1,103 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// StaticProvider serves the rates of a file loaded at startup. Every rate is
+// quoted against the base currency, and cross rates are derived through it.
+type StaticProvider struct {
+	base  money_entity.Currency
+	asOf  time.Time
+	rates map[money_entity.Currency]*big.Rat
+}
+
+type ratesFile struct {
+	Base  string                 `json:"base"`
+	AsOf  time.Time              `json:"as_of"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// NewStaticProviderFromEnv loads the file at FX_RATES_FILE. Without it no
+// rates are known and only same currency conversions succeed.
+func NewStaticProviderFromEnv() (*StaticProvider, error) {
+	path := os.Getenv("FX_RATES_FILE")
+	if path == "" {
+		return &StaticProvider{rates: map[money_entity.Currency]*big.Rat{}}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("FX_RATES_FILE: %w", err)
+	}
+	defer file.Close()
+
+	return NewStaticProvider(file)
+}
+
+// NewStaticProvider reads rates such as
+// {"base": "USD", "as_of": "2026-01-02T00:00:00Z", "rates": {"BRL": "5.10"}},
+// where each rate is the price of one unit of the base currency.
+func NewStaticProvider(reader io.Reader) (*StaticProvider, error) {
+	var content ratesFile
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, fmt.Errorf("invalid FX rates file: %w", err)
+	}
+
+	base, ierr := money_entity.ParseCurrency(content.Base)
+	if ierr != nil {
+		return nil, fmt.Errorf("invalid FX rates base: %s", ierr.Message)
+	}
+
+	provider := &StaticProvider{
+		base:  base,
+		asOf:  content.AsOf,
+		rates: map[money_entity.Currency]*big.Rat{base: big.NewRat(1, 1)},
+	}
+	for code, number := range content.Rates {
+		currency, ierr := money_entity.ParseCurrency(code)
+		if ierr != nil {
+			return nil, fmt.Errorf("invalid FX rate %s: %s", code, ierr.Message)
+		}
+
+		value, ok := new(big.Rat).SetString(string(number))
+		if !ok || value.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid FX rate %s: must be a positive number", code)
+		}
+		provider.rates[currency] = value
+	}
+
+	return provider, nil
+}
+
+func (p *StaticProvider) Rate(
+	_ context.Context, from, to money_entity.Currency) (*money_entity.Rate, *internal_error.InternalError) {
+	if from == to {
+		return &money_entity.Rate{From: from, To: to, Value: big.NewRat(1, 1), AsOf: p.asOf}, nil
+	}
+
+	fromRate, fromOk := p.rates[from]
+	toRate, toOk := p.rates[to]
+	if !fromOk || !toOk {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("No exchange rate from %s to %s", from, to))
+	}
+
+	return &money_entity.Rate{
+		From:  from,
+		To:    to,
+		Value: new(big.Rat).Quo(toRate, fromRate),
+		AsOf:  p.asOf,
+	}, nil
+}
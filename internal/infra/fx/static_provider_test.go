@@ -0,0 +1,87 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+const rates = `{"base": "USD", "as_of": "2026-01-02T00:00:00Z", "rates": {"BRL": "5.00", "EUR": 0.8}}`
+
+func TestStaticProviderRate(t *testing.T) {
+	provider, err := NewStaticProvider(strings.NewReader(rates))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("should quote from the base currency", func(t *testing.T) {
+		rate, ierr := provider.Rate(ctx, "USD", "BRL")
+		assert.Nil(t, ierr)
+		assert.Equal(t, "5", rate.String())
+		assert.Equal(t, "2026-01-02", rate.AsOf.Format("2006-01-02"))
+	})
+
+	t.Run("should derive cross rates through the base currency", func(t *testing.T) {
+		rate, ierr := provider.Rate(ctx, "BRL", "EUR")
+		assert.Nil(t, ierr)
+		assert.Equal(t, "0.16", rate.String())
+		assert.Equal(t, money_entity.New(1600, "EUR"), money_entity.New(10000, "BRL").Convert(*rate))
+	})
+
+	t.Run("should keep the amount in the same currency", func(t *testing.T) {
+		rate, ierr := provider.Rate(ctx, "JPY", "JPY")
+		assert.Nil(t, ierr)
+		assert.Equal(t, "1", rate.String())
+	})
+
+	t.Run("should fail without a published rate", func(t *testing.T) {
+		_, ierr := provider.Rate(ctx, "BRL", "JPY")
+		assert.NotNil(t, ierr)
+		assert.Equal(t, "bad_request", ierr.Err)
+	})
+}
+
+func TestNewStaticProvider(t *testing.T) {
+	for _, content := range []string{
+		`not json`,
+		`{"base": "XYZ", "rates": {}}`,
+		`{"base": "USD", "rates": {"XYZ": "1"}}`,
+		`{"base": "USD", "rates": {"BRL": "0"}}`,
+		`{"base": "USD", "rates": {"BRL": "-5"}}`,
+	} {
+		_, err := NewStaticProvider(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestNewStaticProviderFromEnv(t *testing.T) {
+	t.Run("should know no rates without a file", func(t *testing.T) {
+		t.Setenv("FX_RATES_FILE", "")
+		provider, err := NewStaticProviderFromEnv()
+		assert.NoError(t, err)
+
+		_, ierr := provider.Rate(context.Background(), "USD", "BRL")
+		assert.NotNil(t, ierr)
+	})
+
+	t.Run("should load the configured file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rates.json")
+		assert.NoError(t, os.WriteFile(path, []byte(rates), 0o600))
+		t.Setenv("FX_RATES_FILE", path)
+
+		provider, err := NewStaticProviderFromEnv()
+		assert.NoError(t, err)
+		_, ierr := provider.Rate(context.Background(), "USD", "BRL")
+		assert.Nil(t, ierr)
+	})
+
+	t.Run("should fail when the file is missing", func(t *testing.T) {
+		t.Setenv("FX_RATES_FILE", filepath.Join(t.TempDir(), "missing.json"))
+		_, err := NewStaticProviderFromEnv()
+		assert.Error(t, err)
+	})
+}
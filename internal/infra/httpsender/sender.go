@@ -0,0 +1,68 @@
+package httpsender
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// Headers of every delivery. Receivers check the signature by computing
+// webhook_entity.Sign with their secret, the timestamp header and the raw body.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventId   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DefaultTimeout bounds each attempt, so a slow receiver only delays its own
+// deliveries until the next retry.
+const DefaultTimeout = 10 * time.Second
+
+// Sender posts the deliveries as JSON, signed with the secret of the webhook.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *Sender) Send(
+	ctx context.Context,
+	webhook webhook_entity.Webhook,
+	delivery webhook_entity.Delivery) (int, *internal_error.InternalError) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, internal_error.NewBadRequestError("Invalid webhook url")
+	}
+
+	timestamp := time.Now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "auction-webhooks/1")
+	request.Header.Set(HeaderEvent, string(delivery.Event))
+	request.Header.Set(HeaderEventId, delivery.EventId)
+	request.Header.Set(HeaderDelivery, delivery.Id)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	request.Header.Set(HeaderSignature,
+		"sha256="+webhook_entity.Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, internal_error.NewInternalServerError(err.Error())
+	}
+	defer response.Body.Close()
+	// Drains the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return response.StatusCode, nil
+}
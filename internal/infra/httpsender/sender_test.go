@@ -0,0 +1,77 @@
+package httpsender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSenderSend(t *testing.T) {
+	webhook, err := webhook_entity.CreateWebhook(
+		"http://placeholder", "", []webhook_entity.EventType{webhook_entity.BidAccepted})
+	require.Nil(t, err)
+	delivery := webhook_entity.NewDelivery(
+		webhook.Id, "event-1", webhook_entity.BidAccepted, []byte(`{"id":"event-1"}`))
+
+	t.Run("should post the signed payload", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		webhook.Url = receiver.URL
+		statusCode, sendErr := NewSender(time.Second).Send(context.Background(), *webhook, delivery)
+
+		require.Nil(t, sendErr)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+		assert.Equal(t, delivery.Payload, body)
+		assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+		assert.Equal(t, "bid.accepted", received.Header.Get(HeaderEvent))
+		assert.Equal(t, "event-1", received.Header.Get(HeaderEventId))
+		assert.Equal(t, delivery.Id, received.Header.Get(HeaderDelivery))
+
+		timestamp, parseErr := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, parseErr)
+		assert.Equal(t,
+			"sha256="+webhook_entity.Sign(webhook.Secret, time.Unix(timestamp, 0), body),
+			received.Header.Get(HeaderSignature))
+	})
+
+	t.Run("should return the status code of failed deliveries", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		webhook.Url = receiver.URL
+		statusCode, sendErr := NewSender(time.Second).Send(context.Background(), *webhook, delivery)
+
+		assert.Nil(t, sendErr)
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	})
+
+	t.Run("should fail when the receiver does not answer in time", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer receiver.Close()
+
+		webhook.Url = receiver.URL
+		statusCode, sendErr := NewSender(50*time.Millisecond).Send(context.Background(), *webhook, delivery)
+
+		require.NotNil(t, sendErr)
+		assert.Zero(t, statusCode)
+	})
+}
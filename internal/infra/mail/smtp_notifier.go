@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// SMTPNotifier delivers the email notifications through an SMTP server, such as
+// the local test server of docker compose.
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifierFromEnv reads SMTP_ADDR, SMTP_FROM and the optional
+// SMTP_USERNAME and SMTP_PASSWORD. Without SMTP_ADDR there is no email channel
+// and it returns nil.
+func NewSMTPNotifierFromEnv() (*SMTPNotifier, error) {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_ADDR: %w", err)
+	}
+
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		return nil, errors.New("SMTP_FROM is required when SMTP_ADDR is set")
+	}
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return NewSMTPNotifier(addr, from, auth), nil
+}
+
+func NewSMTPNotifier(addr, from string, auth smtp.Auth) *SMTPNotifier {
+	return &SMTPNotifier{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}
+}
+
+func (n *SMTPNotifier) Channel() notification_entity.Channel {
+	return notification_entity.ChannelEmail
+}
+
+// Notify skips recipients without an email.
+func (n *SMTPNotifier) Notify(
+	_ context.Context,
+	recipient user_entity.User,
+	notification notification_entity.Notification) *internal_error.InternalError {
+	if recipient.Email == "" {
+		return nil
+	}
+
+	message := n.message(recipient, notification)
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{recipient.Email}, message); err != nil {
+		logger.Error("Error trying to send notification email", err)
+		return internal_error.NewInternalServerError("Error trying to send notification email")
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) message(
+	recipient user_entity.User, notification notification_entity.Notification) []byte {
+	var message bytes.Buffer
+
+	fmt.Fprintf(&message, "From: %s\r\n", n.from)
+	fmt.Fprintf(&message, "To: %s\r\n", recipient.Email)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Title))
+	fmt.Fprintf(&message, "Date: %s\r\n", notification.CreatedAt.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@auction>\r\n", notification.Id)
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	fmt.Fprintf(&message, "Hi %s,\r\n\r\n%s\r\n", recipient.Name, notification.Message)
+
+	return message.Bytes()
+}
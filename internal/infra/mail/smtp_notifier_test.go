@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer accepts a single message, enough for the notifier to talk to.
+func startSMTPServer(t *testing.T) (string, <-chan receivedMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan receivedMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var mail receivedMail
+
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				text.PrintfLine("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				mail.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				text.PrintfLine("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				text.PrintfLine("250 OK")
+			case command == "DATA":
+				text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				mail.data = string(data)
+				text.PrintfLine("250 OK")
+				received <- mail
+			case command == "QUIT":
+				text.PrintfLine("221 Bye")
+				return
+			default:
+				text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	userId, auctionId := uuid.New().String(), uuid.New().String()
+	notification, err := notification_entity.CreateNotification(
+		notification_entity.AuctionWonKey(auctionId, userId), userId,
+		notification_entity.AuctionWon, auctionId, "You won", "Mola maluca is yours")
+	require.Nil(t, err)
+
+	t.Run("should send the notification by email", func(t *testing.T) {
+		addr, received := startSMTPServer(t)
+		notifier := NewSMTPNotifier(addr, "auction@example.com", nil)
+
+		err := notifier.Notify(context.Background(),
+			user_entity.User{Id: userId, Name: "Maria", Email: "maria@example.com"}, *notification)
+		require.Nil(t, err)
+
+		mail := <-received
+		assert.Equal(t, "auction@example.com", mail.from)
+		assert.Equal(t, []string{"maria@example.com"}, mail.to)
+		assert.Contains(t, mail.data, "Subject: You won")
+		assert.Contains(t, mail.data, "Hi Maria,")
+		assert.Contains(t, mail.data, "Mola maluca is yours")
+	})
+
+	t.Run("should skip recipients without an email", func(t *testing.T) {
+		notifier := NewSMTPNotifier("127.0.0.1:1", "auction@example.com", nil)
+
+		err := notifier.Notify(context.Background(), user_entity.User{Id: userId}, *notification)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should fail when the server is unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		notifier := NewSMTPNotifier(addr, "auction@example.com", nil)
+
+		internalErr := notifier.Notify(context.Background(),
+			user_entity.User{Id: userId, Email: "maria@example.com"}, *notification)
+		require.NotNil(t, internalErr)
+		assert.Equal(t, "internal_server_error", internalErr.Err)
+	})
+}
+
+func TestNewSMTPNotifierFromEnv(t *testing.T) {
+	t.Run("should disable the email channel without SMTP_ADDR", func(t *testing.T) {
+		t.Setenv("SMTP_ADDR", "")
+
+		notifier, err := NewSMTPNotifierFromEnv()
+		assert.NoError(t, err)
+		assert.Nil(t, notifier)
+	})
+
+	t.Run("should require the sender", func(t *testing.T) {
+		t.Setenv("SMTP_ADDR", "localhost:1025")
+		t.Setenv("SMTP_FROM", "")
+
+		_, err := NewSMTPNotifierFromEnv()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// NewCommandMonitor times the MongoDB commands of every repository sharing the
+// client. Commands without a collection, such as ping, are not recorded.
+func NewCommandMonitor(metrics metrics_entity.MetricsInterface) *event.CommandMonitor {
+	// collections keeps the collection of each started command until it
+	// finishes, as only the started event carries the command.
+	var collections sync.Map
+
+	finish := func(requestId int64, command string, finished event.CommandFinishedEvent, failed bool) {
+		collection, ok := collections.LoadAndDelete(requestId)
+		if !ok {
+			return
+		}
+		metrics.ObserveDatabaseCommand(collection.(string), command, finished.Duration, failed)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, started *event.CommandStartedEvent) {
+			if collection := commandCollection(started); collection != "" {
+				collections.Store(started.RequestID, collection)
+			}
+		},
+		Succeeded: func(_ context.Context, succeeded *event.CommandSucceededEvent) {
+			finish(succeeded.RequestID, succeeded.CommandName, succeeded.CommandFinishedEvent, false)
+		},
+		Failed: func(_ context.Context, failed *event.CommandFailedEvent) {
+			finish(failed.RequestID, failed.CommandName, failed.CommandFinishedEvent, true)
+		},
+	}
+}
+
+// commandCollection reads the collection of a command, named by the command
+// itself ({"find": "bids"}) except for getMore.
+func commandCollection(started *event.CommandStartedEvent) string {
+	field := started.CommandName
+	if field == "getMore" {
+		field = "collection"
+	}
+
+	collection, _ := started.Command.Lookup(field).StringValueOK()
+	return collection
+}
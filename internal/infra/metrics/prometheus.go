@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auction"
+
+// Prometheus keeps the metrics in its own registry, served by Handler along
+// with the Go runtime and process metrics.
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	bidQueueDepth   prometheus.Gauge
+	bidBatchSize    prometheus.Histogram
+	bidBatchFlush   prometheus.Histogram
+	bidsAccepted    prometheus.Counter
+	bidsRejected    *prometheus.CounterVec
+	auctionsOpened  prometheus.Counter
+	auctionsClosed  *prometheus.CounterVec
+	dbCommands      *prometheus.HistogramVec
+	dbFailures      *prometheus.CounterVec
+}
+
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		bidQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "bid_queue_depth",
+			Help:      "Bids waiting for the next batch.",
+		}),
+		bidBatchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bid_batch_size",
+			Help:      "Bids in each flushed batch.",
+			Buckets:   []float64{1, 2, 5, 10, 20, 50, 100, 200, 500},
+		}),
+		bidBatchFlush: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bid_batch_flush_duration_seconds",
+			Help:      "Time taken to store each bid batch.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		bidsAccepted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bids_accepted_total",
+			Help:      "Bids stored by the batches.",
+		}),
+		bidsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bids_rejected_total",
+			Help:      "Bids rejected by the batches, by dead letter reason.",
+		}, []string{"reason"}),
+		auctionsOpened: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auctions_opened_total",
+			Help:      "Auctions created.",
+		}),
+		auctionsClosed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auctions_closed_total",
+			Help:      "Auctions ended, by final status.",
+		}, []string{"status"}),
+		dbCommands: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "mongo_command_duration_seconds",
+			Help:      "MongoDB command latency by collection and command.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"collection", "command"}),
+		dbFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mongo_command_failures_total",
+			Help:      "MongoDB commands that failed, by collection and command.",
+		}, []string{"collection", "command"}),
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.requests, p.requestDuration,
+		p.bidQueueDepth, p.bidBatchSize, p.bidBatchFlush, p.bidsAccepted, p.bidsRejected,
+		p.auctionsOpened, p.auctionsClosed,
+		p.dbCommands, p.dbFailures,
+	)
+
+	return p
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+func (p *Prometheus) ObserveRequest(method, route string, status int, duration time.Duration) {
+	p.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	p.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (p *Prometheus) SetBidQueueDepth(depth int) {
+	p.bidQueueDepth.Set(float64(depth))
+}
+
+func (p *Prometheus) ObserveBidBatch(size int, duration time.Duration) {
+	p.bidBatchSize.Observe(float64(size))
+	p.bidBatchFlush.Observe(duration.Seconds())
+}
+
+func (p *Prometheus) BidAccepted() {
+	p.bidsAccepted.Inc()
+}
+
+func (p *Prometheus) BidRejected(reason string) {
+	p.bidsRejected.WithLabelValues(reason).Inc()
+}
+
+func (p *Prometheus) AuctionOpened() {
+	p.auctionsOpened.Inc()
+}
+
+func (p *Prometheus) AuctionClosed(status string) {
+	p.auctionsClosed.WithLabelValues(status).Inc()
+}
+
+func (p *Prometheus) ObserveDatabaseCommand(collection, command string, duration time.Duration, failed bool) {
+	p.dbCommands.WithLabelValues(collection, command).Observe(duration.Seconds())
+	if failed {
+		p.dbFailures.WithLabelValues(collection, command).Inc()
+	}
+}
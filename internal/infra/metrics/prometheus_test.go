@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+
+	p.ObserveRequest(http.MethodGet, "/auction/:auctionId", http.StatusOK, 10*time.Millisecond)
+	p.ObserveRequest(http.MethodGet, "/auction/:auctionId", http.StatusOK, 20*time.Millisecond)
+	p.SetBidQueueDepth(7)
+	p.BidAccepted()
+	p.BidRejected("auction_closed")
+	p.AuctionOpened()
+	p.AuctionClosed("completed")
+	p.ObserveDatabaseCommand("bids", "insert", time.Millisecond, true)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.requests.WithLabelValues(http.MethodGet, "/auction/:auctionId", "200")))
+	assert.Equal(t, 7.0, testutil.ToFloat64(p.bidQueueDepth))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.bidsAccepted))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.bidsRejected.WithLabelValues("auction_closed")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.auctionsOpened))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.auctionsClosed.WithLabelValues("completed")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.dbFailures.WithLabelValues("bids", "insert")))
+
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, string(body), `auction_http_requests_total{method="GET",route="/auction/:auctionId",status="200"} 2`)
+	assert.Contains(t, string(body), "auction_bid_queue_depth 7")
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestCommandMonitor(t *testing.T) {
+	p := NewPrometheus()
+	monitor := NewCommandMonitor(p)
+
+	start := func(id int64, name string, command bson.D) {
+		raw, err := bson.Marshal(command)
+		require.NoError(t, err)
+		monitor.Started(t.Context(), &event.CommandStartedEvent{Command: raw, CommandName: name, RequestID: id})
+	}
+
+	start(1, "find", bson.D{{Key: "find", Value: "bids"}})
+	start(2, "getMore", bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "bids"}})
+	start(3, "ping", bson.D{{Key: "ping", Value: 1}})
+
+	monitor.Succeeded(t.Context(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, Duration: time.Millisecond},
+	})
+	monitor.Failed(t.Context(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "getMore", RequestID: 2, Duration: time.Millisecond},
+	})
+	monitor.Succeeded(t.Context(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "ping", RequestID: 3},
+	})
+
+	assert.Equal(t, 2, testutil.CollectAndCount(p.dbCommands))
+	assert.Equal(t, 0.0, testutil.ToFloat64(p.dbFailures.WithLabelValues("bids", "find")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.dbFailures.WithLabelValues("bids", "getMore")))
+}
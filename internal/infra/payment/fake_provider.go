@@ -0,0 +1,61 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeclinedPaymentMethod makes the fake provider decline the capture, so the
+// failure path can be exercised locally.
+const DeclinedPaymentMethod = "tok_declined"
+
+// FakeProvider approves every capture in memory. It stands in for a real
+// payment gateway in development and tests.
+type FakeProvider struct {
+	mu       sync.Mutex
+	captured map[string]money_entity.Money
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		captured: make(map[string]money_entity.Money),
+	}
+}
+
+func (p *FakeProvider) Capture(
+	_ context.Context, order order_entity.Order, paymentMethod string) (string, *internal_error.InternalError) {
+	if paymentMethod == DeclinedPaymentMethod {
+		return "", internal_error.NewBadRequestError("Payment was declined")
+	}
+
+	paymentId := "pay_" + uuid.New().String()
+
+	p.mu.Lock()
+	p.captured[paymentId] = order.Amount
+	p.mu.Unlock()
+
+	logger.Info("Fake payment captured",
+		zap.String("payment_id", paymentId), zap.String("order_id", order.Id), zap.Stringer("amount", order.Amount))
+
+	return paymentId, nil
+}
+
+func (p *FakeProvider) Refund(_ context.Context, paymentId string) *internal_error.InternalError {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.captured[paymentId]; !ok {
+		return internal_error.NewNotFoundError(fmt.Sprintf("Payment not found with this id = %s", paymentId))
+	}
+
+	delete(p.captured, paymentId)
+	return nil
+}
@@ -0,0 +1,27 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider(t *testing.T) {
+	provider := NewFakeProvider()
+	order := order_entity.Order{Id: "order-1", Amount: money_entity.New(15000, "BRL")}
+
+	paymentId, err := provider.Capture(context.Background(), order, "tok_visa")
+	require.Nil(t, err)
+	assert.NotEmpty(t, paymentId)
+
+	assert.Nil(t, provider.Refund(context.Background(), paymentId))
+	assert.Equal(t, "not_found", provider.Refund(context.Background(), paymentId).Err)
+
+	_, err = provider.Capture(context.Background(), order, DeclinedPaymentMethod)
+	require.NotNil(t, err)
+	assert.Equal(t, "bad_request", err.Err)
+}
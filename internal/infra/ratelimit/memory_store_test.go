@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	t.Run("should allow a burst and then refuse", func(t *testing.T) {
+		first, _ := store.Take(context.Background(), "user:1", limit, now)
+		second, _ := store.Take(context.Background(), "user:1", limit, now)
+		third, _ := store.Take(context.Background(), "user:1", limit, now)
+
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 1, first.Remaining)
+		assert.True(t, second.Allowed)
+		assert.Equal(t, 0, second.Remaining)
+		assert.False(t, third.Allowed)
+		assert.Equal(t, time.Second, third.RetryAfter)
+		assert.Equal(t, 2*time.Second, third.Reset)
+	})
+
+	t.Run("should refill over time", func(t *testing.T) {
+		result, _ := store.Take(context.Background(), "user:1", limit, now.Add(1500*time.Millisecond))
+
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+	})
+
+	t.Run("should keep separate buckets per key", func(t *testing.T) {
+		result, _ := store.Take(context.Background(), "user:2", limit, now)
+
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1, result.Remaining)
+	})
+
+	t.Run("should drop full buckets", func(t *testing.T) {
+		store.Take(context.Background(), "user:3", limit, now)
+		store.Take(context.Background(), "user:3", limit, now.Add(time.Hour))
+
+		assert.Len(t, store.buckets, 1)
+	})
+}
+
+func TestLimitFromEnv(t *testing.T) {
+	fallback := Limit{Rate: 5, Burst: 10}
+
+	limit, err := LimitFromEnv("BID", fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, fallback, limit)
+
+	t.Setenv("RATE_LIMIT_BID_RATE", "0.5")
+	limit, err = LimitFromEnv("BID", fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 0.5, Burst: 10}, limit)
+
+	t.Setenv("RATE_LIMIT_BID_BURST", "many")
+	_, err = LimitFromEnv("BID", fallback)
+	assert.Error(t, err)
+}
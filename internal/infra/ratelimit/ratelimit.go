@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst tokens and refills Rate tokens
+// per second. A zero Rate disables the limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token, when the request was refused.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps the buckets. Implementations must be safe for concurrent use;
+// a shared backend lets several instances enforce the same budget.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// LimitFromEnv reads RATE_LIMIT_<GROUP>_RATE (tokens per second) and
+// RATE_LIMIT_<GROUP>_BURST, falling back to fallback for unset values.
+func LimitFromEnv(group string, fallback Limit) (Limit, error) {
+	limit := fallback
+
+	rateKey := fmt.Sprintf("RATE_LIMIT_%s_RATE", group)
+	if value := os.Getenv(rateKey); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return Limit{}, fmt.Errorf("%s must be a non-negative number", rateKey)
+		}
+		limit.Rate = rate
+	}
+
+	burstKey := fmt.Sprintf("RATE_LIMIT_%s_BURST", group)
+	if value := os.Getenv(burstKey); value != "" {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 0 {
+			return Limit{}, fmt.Errorf("%s must be a non-negative integer", burstKey)
+		}
+		limit.Burst = burst
+	}
+
+	return limit, nil
+}
@@ -29,3 +29,38 @@ func NewBadRequestError(message string) *InternalError {
 		Err:     "bad_request",
 	}
 }
+
+func NewConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "conflict",
+	}
+}
+
+func NewUnauthorizedError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "unauthorized",
+	}
+}
+
+func NewForbiddenError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "forbidden",
+	}
+}
+
+func NewServiceUnavailableError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "service_unavailable",
+	}
+}
+
+func NewUnprocessableEntityError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "unprocessable_entity",
+	}
+}
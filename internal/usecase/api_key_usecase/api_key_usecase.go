@@ -0,0 +1,170 @@
+package api_key_usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/api_key_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type ApiKeyInputDTO struct {
+	Name      string   `json:"name" binding:"required,min=2,max=100"`
+	OwnerId   string   `json:"owner_id" binding:"required,uuid"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,oneof=read bid create-auction"`
+	ExpiresAt string   `json:"expires_at" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type ApiKeyOutputDTO struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	OwnerId    string     `json:"owner_id"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IssuedApiKeyOutputDTO is only returned when the key is issued; Key cannot
+// be retrieved again.
+type IssuedApiKeyOutputDTO struct {
+	ApiKeyOutputDTO
+
+	Key string `json:"key"`
+}
+
+type FindApiKeysInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+type ApiKeyPageOutputDTO struct {
+	Items []ApiKeyOutputDTO                 `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+type ApiKeyUseCaseInterface interface {
+	IssueApiKey(
+		ctx context.Context,
+		apiKeyInput ApiKeyInputDTO) (*IssuedApiKeyOutputDTO, *internal_error.InternalError)
+
+	RevokeApiKey(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindApiKeys(
+		ctx context.Context,
+		findApiKeysInput FindApiKeysInputDTO) (*ApiKeyPageOutputDTO, *internal_error.InternalError)
+}
+
+type ApiKeyUseCase struct {
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface
+	userRepository   user_entity.UserRepositoryInterface
+}
+
+func NewApiKeyUseCase(
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) ApiKeyUseCaseInterface {
+	return &ApiKeyUseCase{
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+	}
+}
+
+func (au *ApiKeyUseCase) IssueApiKey(
+	ctx context.Context,
+	apiKeyInput ApiKeyInputDTO) (*IssuedApiKeyOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "ApiKeyUseCase.IssueApiKey")
+	defer span.End()
+
+	owner, err := au.userRepository.FindUserById(ctx, apiKeyInput.OwnerId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError(
+				fmt.Sprintf("User %s does not exist", apiKeyInput.OwnerId))
+		}
+		return nil, err
+	}
+
+	scopes := make([]api_key_entity.Scope, 0, len(apiKeyInput.Scopes))
+	for _, name := range apiKeyInput.Scopes {
+		scope := api_key_entity.Scope(name)
+		if role, ok := scope.Role(); ok && !owner.HasRole(role) {
+			return nil, internal_error.NewBadRequestError(
+				fmt.Sprintf("Scope %s requires the owner to have the %s role", scope, role))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	var expiresAt *time.Time
+	if apiKeyInput.ExpiresAt != "" {
+		parsed, parseErr := time.Parse(time.RFC3339, apiKeyInput.ExpiresAt)
+		if parseErr != nil {
+			return nil, internal_error.NewBadRequestError("ExpiresAt is not a valid value")
+		}
+		expiresAt = &parsed
+	}
+
+	apiKey, secret, err := api_key_entity.IssueApiKey(apiKeyInput.Name, owner.Id, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.apiKeyRepository.CreateApiKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &IssuedApiKeyOutputDTO{
+		ApiKeyOutputDTO: toApiKeyOutputDTO(*apiKey),
+		Key:             secret,
+	}, nil
+}
+
+func (au *ApiKeyUseCase) RevokeApiKey(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "ApiKeyUseCase.RevokeApiKey")
+	defer span.End()
+
+	return au.apiKeyRepository.RevokeApiKey(ctx, id, time.Now())
+}
+
+func (au *ApiKeyUseCase) FindApiKeys(
+	ctx context.Context,
+	findApiKeysInput FindApiKeysInputDTO) (*ApiKeyPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "ApiKeyUseCase.FindApiKeys")
+	defer span.End()
+
+	apiKeys, pageInfo, err := au.apiKeyRepository.FindApiKeys(ctx, findApiKeysInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyOutputs := make([]ApiKeyOutputDTO, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		apiKeyOutputs = append(apiKeyOutputs, toApiKeyOutputDTO(apiKey))
+	}
+
+	return &ApiKeyPageOutputDTO{
+		Items: apiKeyOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func toApiKeyOutputDTO(apiKey api_key_entity.ApiKey) ApiKeyOutputDTO {
+	return ApiKeyOutputDTO{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		OwnerId:    apiKey.OwnerId,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.ScopeNames(),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -2,29 +2,104 @@ package auction_usecase
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+	"github.com/google/uuid"
 )
 
+// AuctionInputDTO does not accept the seller from the body; the controller fills
+// SellerId with the authenticated user.
 type AuctionInputDTO struct {
+	SellerId    string           `json:"-"`
 	ProductName string           `json:"product_name" binding:"required,min=1"`
 	Category    string           `json:"category" binding:"required,min=2"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
 	Condition   ProductCondition `json:"condition" binding:"oneof=1 2 3"`
+	Currency    string           `json:"currency" binding:"omitempty,currency"`
 }
 
 type AuctionOutputDTO struct {
-	Id          string           `json:"id"`
-	ProductName string           `json:"product_name"`
-	Category    string           `json:"category"`
-	Description string           `json:"description"`
-	Condition   ProductCondition `json:"condition"`
-	Status      AuctionStatus    `json:"status"`
-	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id           string               `json:"id"`
+	SellerId     string               `json:"seller_id,omitempty"`
+	ProductName  string               `json:"product_name"`
+	Category     string               `json:"category"`
+	Description  string               `json:"description"`
+	Condition    ProductCondition     `json:"condition"`
+	Status       AuctionStatus        `json:"status"`
+	CurrentPrice money_entity.Decimal `json:"current_price"`
+	Currency     string               `json:"currency"`
+	Timestamp    time.Time            `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+
+	ConvertedPrice *money_usecase.ConvertedAmountOutputDTO `json:"converted_price,omitempty"`
+}
+
+// NewAuctionOutputDTO builds every auction output, so this and other use cases
+// return auctions in the same shape.
+func NewAuctionOutputDTO(auction auction_entity.Auction) AuctionOutputDTO {
+	return AuctionOutputDTO{
+		Id:           auction.Id,
+		SellerId:     auction.SellerId,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    ProductCondition(auction.Condition),
+		Status:       AuctionStatus(auction.Status),
+		CurrentPrice: auction.CurrentPrice.Decimal(),
+		Currency:     string(auction.Currency()),
+		Timestamp:    auction.Timestamp,
+	}
+}
+
+// AuctionFilterInputDTO receives every filter as text so invalid values are
+// reported by the validator with the offending field instead of a conversion
+// error. Repeated parameters (category=a&category=b) are combined with OR.
+// Prices are in major units of Currency.
+type AuctionFilterInputDTO struct {
+	Status        []string `form:"status" binding:"dive,oneof=0 1 2 3"`
+	Category      []string `form:"category"`
+	Subcategories string   `form:"includeSubcategories" binding:"omitempty,boolean"`
+	Condition     []string `form:"condition" binding:"dive,oneof=1 2 3"`
+	ProductName   string   `form:"productName"`
+	Currency      string   `form:"currency" binding:"omitempty,currency"`
+	MinPrice      string   `form:"minPrice" binding:"omitempty,numeric,excludes=-"`
+	MaxPrice      string   `form:"maxPrice" binding:"omitempty,numeric,excludes=-,numeric_gtefield=MinPrice"`
+	CreatedFrom   string   `form:"createdFrom" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	CreatedTo     string   `form:"createdTo" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00,datetime_gtefield=CreatedFrom"`
+	EndingAfter   string   `form:"endingAfter" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndingBefore  string   `form:"endingBefore" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00,datetime_gtefield=EndingAfter"`
+}
+
+type FindAuctionsInputDTO struct {
+	pagination_usecase.PageInputDTO
+	AuctionFilterInputDTO
+
+	Sort string `form:"sort" binding:"omitempty,oneof=newest ending_soonest highest_bid"`
+}
+
+type SearchAuctionsInputDTO struct {
+	pagination_usecase.PageInputDTO
+	AuctionFilterInputDTO
+
+	Query  string `form:"q" binding:"required,min=2,max=100"`
+	Phrase string `form:"phrase" binding:"omitempty,boolean"`
+}
+
+type AuctionPageOutputDTO struct {
+	Items []AuctionOutputDTO                `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
 }
 
 type WinningInfoOutputDTO struct {
@@ -34,54 +109,128 @@ type WinningInfoOutputDTO struct {
 
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface,
+	rateProvider money_entity.RateProviderInterface,
+	eventHub event_entity.HubInterface,
+	metrics metrics_entity.MetricsInterface) AuctionUseCaseInterface {
 	return &AuctionUseCase{
-		auctionRepositoryInterface: auctionRepositoryInterface,
-		bidRepositoryInterface:     bidRepositoryInterface,
+		auctionRepositoryInterface:  auctionRepositoryInterface,
+		bidRepositoryInterface:      bidRepositoryInterface,
+		categoryRepositoryInterface: categoryRepositoryInterface,
+		rateProvider:                rateProvider,
+		eventHub:                    eventHub,
+		metrics:                     metrics,
 	}
 }
 
 type AuctionUseCaseInterface interface {
 	CreateAuction(
 		requestCtx context.Context,
-		auctionInput AuctionInputDTO) *internal_error.InternalError
+		auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctionById(
-		ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError)
+		ctx context.Context,
+		id string,
+		displayInput money_usecase.DisplayCurrencyInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		findAuctionsInput FindAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError)
+
+	SearchAuctions(
+		ctx context.Context,
+		searchAuctionsInput SearchAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
-		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+		auctionId string,
+		displayInput money_usecase.DisplayCurrencyInputDTO) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	ChangeAuctionStatus(
+		ctx context.Context,
+		auctionId string,
+		action auction_entity.AuctionAction) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	StreamAuctionEvents(
+		ctx context.Context,
+		auctionId string,
+		lastEventId string) (*AuctionStreamOutputDTO, *internal_error.InternalError)
 }
 
 type ProductCondition int64
 type AuctionStatus int64
 
 type AuctionUseCase struct {
-	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
-	bidRepositoryInterface     bid_entity.BidEntityRepository
+	auctionRepositoryInterface  auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface      bid_entity.BidEntityRepository
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface
+	rateProvider                money_entity.RateProviderInterface
+	eventHub                    event_entity.HubInterface
+	metrics                     metrics_entity.MetricsInterface
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	requestCtx context.Context,
-	auctionInput AuctionInputDTO) *internal_error.InternalError {
+	auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	requestCtx, span := tracing.Start(requestCtx, "AuctionUseCase.CreateAuction")
+	defer span.End()
+
+	currency := money_entity.DefaultCurrency
+	if auctionInput.Currency != "" {
+		parsed, err := money_entity.ParseCurrency(auctionInput.Currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = parsed
+	}
+
 	auction, err := auction_entity.CreateAuction(
+		auctionInput.SellerId,
 		auctionInput.ProductName,
 		auctionInput.Category,
 		auctionInput.Description,
-		auction_entity.ProductCondition(auctionInput.Condition))
+		auction_entity.ProductCondition(auctionInput.Condition),
+		currency)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	category, err := au.findCategory(requestCtx, auctionInput.Category)
+	if err != nil {
+		return nil, err
+	}
+	auction.Category = category.Slug
+
 	if err := au.auctionRepositoryInterface.CreateAuction(requestCtx, auction); err != nil {
-		return err
+		return nil, err
+	}
+	au.metrics.AuctionOpened()
+
+	auctionOutput := NewAuctionOutputDTO(*auction)
+	return &auctionOutput, nil
+}
+
+// findCategory resolves the category informed on auction creation, either by its
+// id or by its name/slug, so auctions always reference the canonical slug.
+func (au *AuctionUseCase) findCategory(
+	ctx context.Context, category string) (*category_entity.Category, *internal_error.InternalError) {
+	var categoryEntity *category_entity.Category
+	var err *internal_error.InternalError
+	if uuid.Validate(category) == nil {
+		categoryEntity, err = au.categoryRepositoryInterface.FindCategoryById(ctx, category)
+	} else {
+		categoryEntity, err = au.categoryRepositoryInterface.FindCategoryBySlug(
+			ctx, category_entity.NormalizeSlug(category))
+	}
+
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError(
+				fmt.Sprintf("Category %s does not exist", category))
+		}
+		return nil, err
 	}
 
-	return nil
+	return categoryEntity, nil
 }
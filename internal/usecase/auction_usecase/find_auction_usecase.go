@@ -2,75 +2,286 @@ package auction_usecase
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
 	"github.com/Berchon/fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
 )
 
+// FindAuctionById adds the current price in the display currency, when one is
+// requested.
 func (au *AuctionUseCase) FindAuctionById(
-	ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx context.Context,
+	id string,
+	displayInput money_usecase.DisplayCurrencyInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.FindAuctionById")
+	defer span.End()
+
+	converter, err := money_usecase.NewConverter(au.rateProvider, displayInput)
+	if err != nil {
+		return nil, err
+	}
+
 	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	convertedPrice, err := converter.Convert(ctx, auctionEntity.CurrentPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutput := NewAuctionOutputDTO(*auctionEntity)
+	auctionOutput.ConvertedPrice = convertedPrice
+	return &auctionOutput, nil
 }
 
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
-	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+	findAuctionsInput FindAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.FindAuctions")
+	defer span.End()
+
+	query, err := findAuctionsInput.toAuctionQuery()
+	if err != nil {
+		return nil, err
+	}
+	categories, err := au.expandCategories(ctx, query.Categories, findAuctionsInput.Subcategories)
+	if err != nil {
+		return nil, err
+	}
+	query.Categories = categories
+
+	auctionEntities, pageInfo, err := au.auctionRepositoryInterface.FindAuctions(
+		ctx, query, findAuctionsInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputs := make([]AuctionOutputDTO, 0, len(auctionEntities))
+	for _, value := range auctionEntities {
+		auctionOutputs = append(auctionOutputs, NewAuctionOutputDTO(value))
+	}
+
+	return &AuctionPageOutputDTO{
+		Items: auctionOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (au *AuctionUseCase) SearchAuctions(
+	ctx context.Context,
+	searchAuctionsInput SearchAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.SearchAuctions")
+	defer span.End()
+
+	phrase, _ := strconv.ParseBool(searchAuctionsInput.Phrase)
+
+	filter, err := searchAuctionsInput.toAuctionFilter()
+	if err != nil {
+		return nil, err
+	}
+	categories, err := au.expandCategories(ctx, filter.Categories, searchAuctionsInput.Subcategories)
+	if err != nil {
+		return nil, err
+	}
+	filter.Categories = categories
+
+	auctionEntities, pageInfo, err := au.auctionRepositoryInterface.SearchAuctions(
+		ctx,
+		auction_entity.AuctionSearch{
+			AuctionFilter: filter,
+			Text:          searchAuctionsInput.Query,
+			Phrase:        phrase,
+		},
+		searchAuctionsInput.ToPageRequest())
 	if err != nil {
 		return nil, err
 	}
 
-	var auctionOutputs []AuctionOutputDTO
+	auctionOutputs := make([]AuctionOutputDTO, 0, len(auctionEntities))
 	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
-	}
-
-	return auctionOutputs, nil
+		auctionOutputs = append(auctionOutputs, NewAuctionOutputDTO(value))
+	}
+
+	return &AuctionPageOutputDTO{
+		Items: auctionOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+// toAuctionQuery requires a currency to sort by the highest bid, as prices in
+// minor units are only comparable within a currency. Price bounds already
+// restrict the auctions to the currency they were given in.
+func (input FindAuctionsInputDTO) toAuctionQuery() (auction_entity.AuctionQuery, *internal_error.InternalError) {
+	filter, err := input.toAuctionFilter()
+	if err != nil {
+		return auction_entity.AuctionQuery{}, err
+	}
+
+	sort := auction_entity.AuctionSort(input.Sort)
+	if sort == auction_entity.SortHighestBid &&
+		filter.Currency == "" && filter.MinPrice == nil && filter.MaxPrice == nil {
+		return auction_entity.AuctionQuery{}, internal_error.NewBadRequestError(
+			"Sorting by highest_bid requires a currency, as prices in different currencies are not comparable")
+	}
+
+	return auction_entity.AuctionQuery{
+		AuctionFilter: filter,
+		Sort:          sort,
+	}, nil
+}
+
+// toAuctionFilter converts the already validated query values into the entity
+// filter. Prices are read in the requested currency, the default one when absent,
+// and fail when they have more decimal places than the currency.
+func (input AuctionFilterInputDTO) toAuctionFilter() (auction_entity.AuctionFilter, *internal_error.InternalError) {
+	currency := money_entity.DefaultCurrency
+	if input.Currency != "" {
+		parsed, err := money_entity.ParseCurrency(input.Currency)
+		if err != nil {
+			return auction_entity.AuctionFilter{}, err
+		}
+		currency = parsed
+	}
+
+	minPrice, err := parseOptionalMoney(input.MinPrice, currency)
+	if err != nil {
+		return auction_entity.AuctionFilter{}, err
+	}
+	maxPrice, err := parseOptionalMoney(input.MaxPrice, currency)
+	if err != nil {
+		return auction_entity.AuctionFilter{}, err
+	}
+
+	filter := auction_entity.AuctionFilter{
+		ProductName:  input.ProductName,
+		MinPrice:     minPrice,
+		MaxPrice:     maxPrice,
+		CreatedFrom:  parseOptionalTime(input.CreatedFrom),
+		CreatedTo:    parseOptionalTime(input.CreatedTo),
+		EndingAfter:  parseOptionalTime(input.EndingAfter),
+		EndingBefore: parseOptionalTime(input.EndingBefore),
+	}
+
+	if input.Currency != "" {
+		filter.Currency = currency
+	}
+
+	for _, value := range input.Status {
+		if status, err := strconv.Atoi(value); err == nil {
+			filter.Statuses = append(filter.Statuses, auction_entity.AuctionStatus(status))
+		}
+	}
+
+	for _, value := range input.Condition {
+		if condition, err := strconv.Atoi(value); err == nil {
+			filter.Conditions = append(filter.Conditions, auction_entity.ProductCondition(condition))
+		}
+	}
+
+	for _, value := range input.Category {
+		if slug := category_entity.NormalizeSlug(value); slug != "" {
+			filter.Categories = append(filter.Categories, slug)
+		}
+	}
+
+	return filter, nil
+}
+
+// expandCategories adds the slugs of every subcategory of the requested ones
+// when includeSubcategories is set. Unknown categories are kept as they are and
+// simply match no auction.
+func (au *AuctionUseCase) expandCategories(
+	ctx context.Context,
+	slugs []string,
+	includeSubcategories string) ([]string, *internal_error.InternalError) {
+	if include, _ := strconv.ParseBool(includeSubcategories); !include {
+		return slugs, nil
+	}
+
+	expanded := append([]string{}, slugs...)
+	for _, slug := range slugs {
+		category, err := au.categoryRepositoryInterface.FindCategoryBySlug(ctx, slug)
+		if err != nil {
+			if err.Err == "not_found" {
+				continue
+			}
+			return nil, err
+		}
+
+		descendants, err := au.categoryRepositoryInterface.FindDescendants(ctx, category.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, descendant := range descendants {
+			expanded = append(expanded, descendant.Slug)
+		}
+	}
+
+	return expanded, nil
 }
 
+func parseOptionalMoney(
+	value string, currency money_entity.Currency) (*money_entity.Money, *internal_error.InternalError) {
+	if value == "" {
+		return nil, nil
+	}
+
+	money, err := money_entity.Parse(value, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &money, nil
+}
+
+func parseOptionalTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &parsed
+}
+
+// FindWinningBidByAuctionId adds the current price and the winning bid in the
+// display currency, when one is requested.
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
 	ctx context.Context,
-	auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError) {
+	auctionId string,
+	displayInput money_usecase.DisplayCurrencyInputDTO) (*WinningInfoOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.FindWinningBidByAuctionId")
+	defer span.End()
+
+	converter, err := money_usecase.NewConverter(au.rateProvider, displayInput)
+	if err != nil {
+		return nil, err
+	}
+
 	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
 	if err != nil {
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
+	convertedPrice, err := converter.Convert(ctx, auction.CurrentPrice)
+	if err != nil {
+		return nil, err
 	}
 
+	auctionOutputDTO := NewAuctionOutputDTO(*auction)
+	auctionOutputDTO.ConvertedPrice = convertedPrice
+
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
 	if err != nil {
 		logger.Error("", err)
@@ -80,12 +291,20 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		}, nil
 	}
 
+	convertedAmount, err := converter.Convert(ctx, bidWinning.Amount)
+	if err != nil {
+		return nil, err
+	}
+
 	bidOutputDTO := &bid_usecase.BidOutputDTO{
 		Id:        bidWinning.Id,
 		UserId:    bidWinning.UserId,
 		AuctionId: bidWinning.AuctionId,
-		Amount:    bidWinning.Amount,
+		Amount:    bidWinning.Amount.Decimal(),
+		Currency:  string(bidWinning.Amount.Currency),
 		Timestamp: bidWinning.Timestamp,
+
+		ConvertedAmount: convertedAmount,
 	}
 
 	return &WinningInfoOutputDTO{
@@ -0,0 +1,65 @@
+package auction_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// AuctionEventOutputDTO is the data of each event of the auction stream.
+type AuctionEventOutputDTO struct {
+	Id         string      `json:"id"`
+	Type       string      `json:"type"`
+	AuctionId  string      `json:"auction_id"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+func NewAuctionEventOutputDTO(event event_entity.Event) AuctionEventOutputDTO {
+	return AuctionEventOutputDTO{
+		Id:         event.Id,
+		Type:       string(event.Type),
+		AuctionId:  event.AuctionId,
+		Data:       event.Data,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+// AuctionStreamOutputDTO is a subscription to the events of an auction. Once the
+// auction is over, Open is false and only the replayed events are sent.
+type AuctionStreamOutputDTO struct {
+	Open         bool
+	Subscription *event_entity.Subscription
+}
+
+// StreamAuctionEvents subscribes before reading the auction, so no event
+// published in between is lost.
+func (au *AuctionUseCase) StreamAuctionEvents(
+	ctx context.Context,
+	auctionId string,
+	lastEventId string) (*AuctionStreamOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.StreamAuctionEvents")
+	defer span.End()
+
+	subscription := au.eventHub.Subscribe(auctionId, lastEventId)
+
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+
+	open := auction.Status == auction_entity.Active || auction.Status == auction_entity.Paused
+	if !open {
+		subscription.Close()
+	}
+
+	return &AuctionStreamOutputDTO{
+		Open:         open,
+		Subscription: subscription,
+	}, nil
+}
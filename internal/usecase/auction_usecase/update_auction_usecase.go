@@ -0,0 +1,33 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// ChangeAuctionStatus applies an administrative action (pause, resume, cancel
+// or force-close) to the auction.
+func (au *AuctionUseCase) ChangeAuctionStatus(
+	ctx context.Context,
+	auctionId string,
+	action auction_entity.AuctionAction) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuctionUseCase.ChangeAuctionStatus")
+	defer span.End()
+
+	from, to, ok := action.Transition()
+	if !ok {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("Action %s is not a valid value", action))
+	}
+
+	auction, err := au.auctionRepositoryInterface.UpdateAuctionStatus(ctx, auctionId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutput := NewAuctionOutputDTO(*auction)
+	return &auctionOutput, nil
+}
@@ -0,0 +1,205 @@
+package audit_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type FindEntriesInputDTO struct {
+	pagination_usecase.PageInputDTO
+
+	UserId     string `form:"userId" binding:"omitempty,uuid"`
+	Action     string `form:"action" binding:"omitempty,max=100"`
+	TargetType string `form:"targetType" binding:"omitempty,max=100"`
+	TargetId   string `form:"targetId" binding:"omitempty,max=100"`
+	RequestId  string `form:"requestId" binding:"omitempty,max=100"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00,datetime_gtefield=From"`
+}
+
+type ActorOutputDTO struct {
+	Type     audit_entity.ActorType `json:"type"`
+	UserId   string                 `json:"user_id,omitempty"`
+	ApiKeyId string                 `json:"api_key_id,omitempty"`
+}
+
+type EntryOutputDTO struct {
+	Id           string          `json:"id"`
+	Sequence     int64           `json:"sequence"`
+	Actor        ActorOutputDTO  `json:"actor"`
+	Action       string          `json:"action"`
+	TargetType   string          `json:"target_type"`
+	TargetId     string          `json:"target_id,omitempty"`
+	Before       json.RawMessage `json:"before,omitempty"`
+	After        json.RawMessage `json:"after,omitempty"`
+	RequestId    string          `json:"request_id,omitempty"`
+	ClientIP     string          `json:"client_ip,omitempty"`
+	RemoteIP     string          `json:"remote_ip,omitempty"`
+	Status       int             `json:"status,omitempty"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	PreviousHash string          `json:"previous_hash"`
+	Hash         string          `json:"hash"`
+}
+
+type EntryPageOutputDTO struct {
+	Items []EntryOutputDTO                  `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+// VerifyOutputDTO tells how many entries were verified and, when the chain is
+// broken, the first entry that cannot be trusted.
+type VerifyOutputDTO struct {
+	Entries  int64  `json:"entries"`
+	Valid    bool   `json:"valid"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type AuditUseCaseInterface interface {
+	audit_entity.RecorderInterface
+
+	FindEntries(
+		ctx context.Context,
+		findEntriesInput FindEntriesInputDTO) (*EntryPageOutputDTO, *internal_error.InternalError)
+
+	// VerifyChain walks the whole audit log checking that no entry was changed
+	// or removed.
+	VerifyChain(
+		ctx context.Context) (*VerifyOutputDTO, *internal_error.InternalError)
+}
+
+// verifyBatchSize bounds the entries read at once while verifying the chain.
+const verifyBatchSize = 500
+
+type AuditUseCase struct {
+	auditRepository audit_entity.AuditRepositoryInterface
+}
+
+func NewAuditUseCase(auditRepository audit_entity.AuditRepositoryInterface) AuditUseCaseInterface {
+	return &AuditUseCase{
+		auditRepository: auditRepository,
+	}
+}
+
+// Record appends the entry to the audit log. The change it records already
+// happened, so a failure is only logged.
+func (au *AuditUseCase) Record(ctx context.Context, entry audit_entity.Entry) {
+	ctx, span := tracing.Start(ctx, "AuditUseCase.Record")
+	defer span.End()
+
+	if _, err := au.auditRepository.AppendEntry(context.WithoutCancel(ctx), entry); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to record %s of %s %s", entry.Action, entry.TargetType, entry.TargetId), err)
+	}
+}
+
+func (au *AuditUseCase) FindEntries(
+	ctx context.Context,
+	findEntriesInput FindEntriesInputDTO) (*EntryPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuditUseCase.FindEntries")
+	defer span.End()
+
+	filter := audit_entity.EntryFilter{
+		UserId:     findEntriesInput.UserId,
+		Action:     findEntriesInput.Action,
+		TargetType: findEntriesInput.TargetType,
+		TargetId:   findEntriesInput.TargetId,
+		RequestId:  findEntriesInput.RequestId,
+		From:       parseOptionalTime(findEntriesInput.From),
+		To:         parseOptionalTime(findEntriesInput.To),
+	}
+
+	entries, pageInfo, err := au.auditRepository.FindEntries(ctx, filter, findEntriesInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	entryOutputs := make([]EntryOutputDTO, 0, len(entries))
+	for _, entry := range entries {
+		entryOutputs = append(entryOutputs, toEntryOutputDTO(entry))
+	}
+
+	return &EntryPageOutputDTO{
+		Items: entryOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (au *AuditUseCase) VerifyChain(
+	ctx context.Context) (*VerifyOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuditUseCase.VerifyChain")
+	defer span.End()
+
+	var previousSequence int64
+	var previousHash string
+
+	for {
+		entries, err := au.auditRepository.FindChain(ctx, previousSequence, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if chainBreak := audit_entity.VerifyChain(previousSequence, previousHash, entries); chainBreak != nil {
+			return &VerifyOutputDTO{
+				Entries:  chainBreak.Sequence - 1,
+				BrokenAt: chainBreak.Sequence,
+				Reason:   chainBreak.Reason,
+			}, nil
+		}
+
+		if len(entries) > 0 {
+			last := entries[len(entries)-1]
+			previousSequence, previousHash = last.Sequence, last.Hash
+		}
+		if len(entries) < verifyBatchSize {
+			return &VerifyOutputDTO{Entries: previousSequence, Valid: true}, nil
+		}
+	}
+}
+
+func toEntryOutputDTO(entry audit_entity.Entry) EntryOutputDTO {
+	output := EntryOutputDTO{
+		Id:       entry.Id,
+		Sequence: entry.Sequence,
+		Actor: ActorOutputDTO{
+			Type:     entry.Actor.Type,
+			UserId:   entry.Actor.UserId,
+			ApiKeyId: entry.Actor.ApiKeyId,
+		},
+		Action:       entry.Action,
+		TargetType:   entry.TargetType,
+		TargetId:     entry.TargetId,
+		RequestId:    entry.RequestId,
+		ClientIP:     entry.ClientIP,
+		RemoteIP:     entry.RemoteIP,
+		Status:       entry.Status,
+		OccurredAt:   entry.OccurredAt,
+		PreviousHash: entry.PreviousHash,
+		Hash:         entry.Hash,
+	}
+	if entry.Before != "" {
+		output.Before = json.RawMessage(entry.Before)
+	}
+	if entry.After != "" {
+		output.After = json.RawMessage(entry.After)
+	}
+
+	return output
+}
+
+func parseOptionalTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &parsed
+}
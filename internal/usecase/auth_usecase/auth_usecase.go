@@ -0,0 +1,131 @@
+package auth_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/api_key_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auth_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+type LoginInputDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenOutputDTO struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type AuthUseCaseInterface interface {
+	Login(
+		ctx context.Context,
+		loginInput LoginInputDTO) (*TokenOutputDTO, *internal_error.InternalError)
+
+	Authenticate(token string) (*auth_entity.Claims, *internal_error.InternalError)
+
+	AuthenticateApiKey(
+		ctx context.Context, key string) (*auth_entity.Claims, *internal_error.InternalError)
+}
+
+// apiKeyTouchInterval limits how often the last use of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+type AuthUseCase struct {
+	userRepository   user_entity.UserRepositoryInterface
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface
+	tokenService     auth_entity.TokenServiceInterface
+}
+
+func NewAuthUseCase(
+	userRepository user_entity.UserRepositoryInterface,
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface,
+	tokenService auth_entity.TokenServiceInterface) AuthUseCaseInterface {
+	return &AuthUseCase{
+		userRepository:   userRepository,
+		apiKeyRepository: apiKeyRepository,
+		tokenService:     tokenService,
+	}
+}
+
+func (au *AuthUseCase) Login(
+	ctx context.Context,
+	loginInput LoginInputDTO) (*TokenOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.Login")
+	defer span.End()
+
+	invalidCredentials := internal_error.NewUnauthorizedError("Invalid email or password")
+
+	user, err := au.userRepository.FindUserByEmail(ctx, user_entity.NormalizeEmail(loginInput.Email))
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, invalidCredentials
+		}
+		return nil, err
+	}
+
+	if !user.CheckPassword(loginInput.Password) {
+		return nil, invalidCredentials
+	}
+
+	token, claims, err := au.tokenService.IssueToken(user.Id, user.RoleNames())
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenOutputDTO{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(claims.ExpiresAt.Sub(claims.IssuedAt).Seconds()),
+		ExpiresAt:   claims.ExpiresAt,
+	}, nil
+}
+
+func (au *AuthUseCase) Authenticate(token string) (*auth_entity.Claims, *internal_error.InternalError) {
+	return au.tokenService.ParseToken(token)
+}
+
+func (au *AuthUseCase) AuthenticateApiKey(
+	ctx context.Context, key string) (*auth_entity.Claims, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.AuthenticateApiKey")
+	defer span.End()
+
+	invalidKey := internal_error.NewUnauthorizedError("Invalid, expired or revoked API key")
+
+	apiKey, err := au.apiKeyRepository.FindApiKeyByHash(ctx, api_key_entity.HashSecret(key))
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, invalidKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, invalidKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		// Failing to record the last use must not block the request.
+		_ = au.apiKeyRepository.TouchApiKey(ctx, apiKey.Id, now)
+	}
+
+	claims := &auth_entity.Claims{
+		UserId:   apiKey.OwnerId,
+		Roles:    apiKey.RoleNames(),
+		IssuedAt: apiKey.CreatedAt,
+		ApiKeyId: apiKey.Id,
+		Scopes:   apiKey.ScopeNames(),
+	}
+	if apiKey.ExpiresAt != nil {
+		claims.ExpiresAt = *apiKey.ExpiresAt
+	}
+
+	return claims, nil
+}
@@ -2,48 +2,125 @@ package bid_usecase
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/health_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/metrics_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// BidInputDTO does not accept the bidder from the body; the controller fills
+// UserId with the authenticated user. Amount is read in major units of the
+// auction currency; Currency is optional and, when sent, must match it.
 type BidInputDTO struct {
-	UserId    string  `json:"user_id"`
-	AuctionId string  `json:"auction_id"`
-	Amount    float64 `json:"amount"`
+	UserId    string               `json:"-"`
+	AuctionId string               `json:"auction_id"`
+	Amount    money_entity.Decimal `json:"amount" binding:"required"`
+	Currency  string               `json:"currency" binding:"omitempty,currency"`
 }
 
 type BidOutputDTO struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"user_id"`
-	AuctionId string    `json:"auction_id"`
-	Amount    float64   `json:"amount"`
-	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id        string               `json:"id"`
+	UserId    string               `json:"user_id"`
+	AuctionId string               `json:"auction_id"`
+	Amount    money_entity.Decimal `json:"amount"`
+	Currency  string               `json:"currency"`
+	Timestamp time.Time            `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+
+	ConvertedAmount *money_usecase.ConvertedAmountOutputDTO `json:"converted_amount,omitempty"`
+}
+
+func toBidOutputDTO(bid bid_entity.Bid) BidOutputDTO {
+	return BidOutputDTO{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount.Decimal(),
+		Currency:  string(bid.Amount.Currency),
+		Timestamp: bid.Timestamp,
+	}
+}
+
+type FindBidsInputDTO struct {
+	pagination_usecase.PageInputDTO
+	money_usecase.DisplayCurrencyInputDTO
+
+	Sort string `form:"sort" binding:"omitempty,oneof=newest highest_amount"`
+}
+
+type BidPageOutputDTO struct {
+	Items []BidOutputDTO                    `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
 }
 
 type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+	BidRepository     bid_entity.BidEntityRepository
+	AuctionRepository auction_entity.AuctionRepositoryInterface
+	CreditRepository  credit_entity.CreditRepositoryInterface
+	RateProvider      money_entity.RateProviderInterface
+	Metrics           metrics_entity.MetricsInterface
 
 	timer               *time.Timer
 	maxBatchSize        int
 	batchInsertInterval time.Duration
-	bidChannel          chan bid_entity.Bid
+	stallTimeout        time.Duration
+	bidChannel          chan queuedBid
+	heartbeat           health_entity.Heartbeat
+	stop                chan struct{}
+	stopOnce            sync.Once
+	stopped             chan struct{}
+
+	// stoppingMutex is held for reading while a bid is queued, so Stop waits
+	// for the queued bids before the worker drains the channel.
+	stoppingMutex sync.RWMutex
+	stopping      bool
+}
+
+// queuedBid carries a bid to its batch with a link to the span that queued
+// it, so the batch trace leads back to the request of each bid.
+type queuedBid struct {
+	bid  bid_entity.Bid
+	link trace.Link
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	creditRepository credit_entity.CreditRepositoryInterface,
+	rateProvider money_entity.RateProviderInterface,
+	metrics metrics_entity.MetricsInterface) BidUseCaseInterface {
 	maxSizeInterval := getMaxBatchSizeInterval()
 	maxBatchSize := getMaxBatchSize()
 
 	bidUseCase := &BidUseCase{
 		BidRepository:       bidRepository,
+		AuctionRepository:   auctionRepository,
+		CreditRepository:    creditRepository,
+		RateProvider:        rateProvider,
+		Metrics:             metrics,
 		maxBatchSize:        maxBatchSize,
 		batchInsertInterval: maxSizeInterval,
+		stallTimeout:        getStallTimeout(),
 		timer:               time.NewTimer(maxSizeInterval),
-		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+		bidChannel:          make(chan queuedBid, maxBatchSize),
+		stop:                make(chan struct{}),
+		stopped:             make(chan struct{}),
 	}
 
 	bidUseCase.triggerCreateRoutine(context.Background())
@@ -51,50 +128,61 @@ func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInter
 	return bidUseCase
 }
 
-var bidBatch []bid_entity.Bid
+var bidBatch []queuedBid
 
 type BidUseCaseInterface interface {
 	CreateBid(
 		ctx context.Context,
-		bidInputDTO BidInputDTO) *internal_error.InternalError
+		bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
+		ctx context.Context,
+		auctionId string,
+		findBidsInput FindBidsInputDTO) (*BidPageOutputDTO, *internal_error.InternalError)
+
+	FindDeadLetterBids(
+		ctx context.Context,
+		findDeadLetterBidsInput FindDeadLetterBidsInputDTO) (*DeadLetterBidPageOutputDTO, *internal_error.InternalError)
+
+	// CheckHealth reports whether the batch worker runs and has woken up
+	// within its batch interval plus HEALTH_STALL_TIMEOUT.
+	CheckHealth(ctx context.Context) *internal_error.InternalError
+
+	// Stop stores the bids still waiting for a batch and stops the worker. No
+	// bid may be placed afterwards.
+	Stop(ctx context.Context) *internal_error.InternalError
 }
 
 func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
+	bu.heartbeat.Start(time.Now())
+
 	go func() {
-		defer close(bu.bidChannel)
+		defer close(bu.stopped)
+		defer bu.heartbeat.Stop()
 
 		for {
 			select {
-			case bidEntity, ok := <-bu.bidChannel:
-				if !ok {
-					if len(bidBatch) > 0 {
-						if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-							logger.Error("error trying to process bid batch list", err)
-						}
-					}
-					return
-				}
+			case <-bu.stop:
+				bu.drainBatch(ctx)
+				return
+			case queued := <-bu.bidChannel:
+				bu.heartbeat.Beat(time.Now())
 
-				bidBatch = append(bidBatch, bidEntity)
+				bu.Metrics.SetBidQueueDepth(len(bu.bidChannel))
+				bidBatch = append(bidBatch, queued)
 
 				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
+					bu.flushBatch(ctx)
 
 					bidBatch = nil
 					bu.timer.Reset(bu.batchInsertInterval)
 				}
 			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
-				}
+				bu.heartbeat.Beat(time.Now())
+				bu.flushBatch(ctx)
 				bidBatch = nil
 				bu.timer.Reset(bu.batchInsertInterval)
 			}
@@ -102,20 +190,153 @@ func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
 	}()
 }
 
+// drainBatch stores the current batch along with the bids still queued.
+func (bu *BidUseCase) drainBatch(ctx context.Context) {
+	for {
+		select {
+		case queued := <-bu.bidChannel:
+			bidBatch = append(bidBatch, queued)
+		default:
+			bu.flushBatch(ctx)
+			bidBatch = nil
+			return
+		}
+	}
+}
+
+func (bu *BidUseCase) CheckHealth(ctx context.Context) *internal_error.InternalError {
+	return bu.heartbeat.Check(time.Now(), bu.batchInsertInterval+bu.stallTimeout)
+}
+
+// Stop may be called more than once; the channel of bids is never closed, so
+// CreateBid refuses the bids placed after it instead of sending them.
+func (bu *BidUseCase) Stop(ctx context.Context) *internal_error.InternalError {
+	bu.stopOnce.Do(func() {
+		bu.stoppingMutex.Lock()
+		bu.stopping = true
+		bu.stoppingMutex.Unlock()
+
+		close(bu.stop)
+	})
+
+	select {
+	case <-bu.stopped:
+		return nil
+	case <-ctx.Done():
+		return internal_error.NewInternalServerError("Timed out storing the pending bids")
+	}
+}
+
+// flushBatch stores the current batch in a trace of its own, linked to the
+// requests of its bids. Empty batches, flushed by the timer, are skipped.
+func (bu *BidUseCase) flushBatch(ctx context.Context) {
+	if len(bidBatch) == 0 {
+		return
+	}
+
+	bids := make([]bid_entity.Bid, len(bidBatch))
+	links := make([]trace.Link, len(bidBatch))
+	for i, queued := range bidBatch {
+		bids[i] = queued.bid
+		links[i] = queued.link
+	}
+
+	ctx, span := tracing.StartRoot(ctx, "BidUseCase.flushBatch",
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("bid.batch_size", len(bids))))
+	defer span.End()
+
+	start := time.Now()
+	if err := bu.BidRepository.CreateBid(ctx, bids); err != nil {
+		tracing.Fail(span, err)
+		logger.Error("error trying to process bid batch list", err)
+	}
+
+	bu.Metrics.ObserveBidBatch(len(bids), time.Since(start))
+}
+
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
-	bidInputDTO BidInputDTO) *internal_error.InternalError {
+	bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidUseCase.CreateBid")
+	defer span.End()
+
+	amount, err := bu.parseAmount(ctx, bidInputDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, amount)
+	if err != nil {
+		return nil, err
+	}
 
-	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, bidInputDTO.Amount)
+	// Fail fast on bids that cannot fit the credit limit; the batch checks it
+	// again, atomically, when the bid is stored.
+	account, err := bu.CreditRepository.FindCreditAccount(ctx, bidEntity.UserId)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := account.NewHold(ctx, bu.RateProvider, bidEntity.AuctionId, bidEntity.Id, bidEntity.Amount)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !account.CanHold(bidEntity.AuctionId, hold.Amount) {
+		return nil, internal_error.NewConflictError(fmt.Sprintf(
+			"Bid exceeds your available credit of %s", account.Available()))
 	}
 
-	bu.bidChannel <- *bidEntity
+	span.SetAttributes(
+		attribute.String("bid.id", bidEntity.Id),
+		attribute.String("auction.id", bidEntity.AuctionId))
+	if err := bu.queueBid(ctx, *bidEntity); err != nil {
+		return nil, err
+	}
 
+	bidOutput := toBidOutputDTO(*bidEntity)
+	return &bidOutput, nil
+}
+
+// queueBid hands bid to the batch worker, unless it is stopping.
+func (bu *BidUseCase) queueBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	bu.stoppingMutex.RLock()
+	defer bu.stoppingMutex.RUnlock()
+
+	if bu.stopping {
+		return internal_error.NewServiceUnavailableError("Service is shutting down")
+	}
+
+	bu.bidChannel <- queuedBid{bid: bid, link: trace.LinkFromContext(ctx)}
+	bu.Metrics.SetBidQueueDepth(len(bu.bidChannel))
 	return nil
 }
 
+// parseAmount reads the bid amount in the currency of its auction.
+func (bu *BidUseCase) parseAmount(
+	ctx context.Context, bidInputDTO BidInputDTO) (money_entity.Money, *internal_error.InternalError) {
+	if err := uuid.Validate(bidInputDTO.AuctionId); err != nil {
+		return money_entity.Money{}, internal_error.NewBadRequestError("AuctionId is not a valid id")
+	}
+
+	auction, err := bu.AuctionRepository.FindAuctionById(ctx, bidInputDTO.AuctionId)
+	if err != nil {
+		return money_entity.Money{}, err
+	}
+
+	if bidInputDTO.Currency != "" {
+		currency, err := money_entity.ParseCurrency(bidInputDTO.Currency)
+		if err != nil {
+			return money_entity.Money{}, err
+		}
+		if currency != auction.Currency() {
+			return money_entity.Money{}, internal_error.NewBadRequestError(fmt.Sprintf(
+				"Auction only takes bids in %s", auction.Currency()))
+		}
+	}
+
+	return money_entity.Parse(string(bidInputDTO.Amount), auction.Currency())
+}
+
 func getMaxBatchSizeInterval() time.Duration {
 	batchInsertInterval := os.Getenv("BATCH_INSERT_INTERVAL")
 	duration, err := time.ParseDuration(batchInsertInterval)
@@ -134,3 +355,12 @@ func getMaxBatchSize() int {
 
 	return value
 }
+
+func getStallTimeout() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("HEALTH_STALL_TIMEOUT"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
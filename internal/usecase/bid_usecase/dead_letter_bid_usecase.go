@@ -0,0 +1,58 @@
+package bid_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type FindDeadLetterBidsInputDTO struct {
+	pagination_usecase.PageInputDTO
+
+	AuctionId string `form:"auctionId" binding:"omitempty,uuid"`
+}
+
+type DeadLetterBidOutputDTO struct {
+	BidOutputDTO
+
+	Reason   string    `json:"reason"`
+	Detail   string    `json:"detail"`
+	FailedAt time.Time `json:"failed_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type DeadLetterBidPageOutputDTO struct {
+	Items []DeadLetterBidOutputDTO          `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+func (bu *BidUseCase) FindDeadLetterBids(
+	ctx context.Context,
+	findDeadLetterBidsInput FindDeadLetterBidsInputDTO) (*DeadLetterBidPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidUseCase.FindDeadLetterBids")
+	defer span.End()
+
+	deadLetters, pageInfo, err := bu.BidRepository.FindDeadLetterBids(
+		ctx, findDeadLetterBidsInput.AuctionId, findDeadLetterBidsInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterOutputs := make([]DeadLetterBidOutputDTO, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		deadLetterOutputs = append(deadLetterOutputs, DeadLetterBidOutputDTO{
+			BidOutputDTO: toBidOutputDTO(deadLetter.Bid),
+			Reason:       string(deadLetter.Reason),
+			Detail:       deadLetter.Detail,
+			FailedAt:     deadLetter.FailedAt,
+		})
+	}
+
+	return &DeadLetterBidPageOutputDTO{
+		Items: deadLetterOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
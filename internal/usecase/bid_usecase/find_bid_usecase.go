@@ -3,44 +3,58 @@ package bid_usecase
 import (
 	"context"
 
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/money_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
 )
 
+// FindBidByAuctionId adds each amount in the display currency, when one is
+// requested.
 func (bu *BidUseCase) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError) {
-	bidList, err := bu.BidRepository.FindBidByAuctionId(ctx, auctionId)
+	ctx context.Context,
+	auctionId string,
+	findBidsInput FindBidsInputDTO) (*BidPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidUseCase.FindBidByAuctionId")
+	defer span.End()
+
+	converter, err := money_usecase.NewConverter(bu.RateProvider, findBidsInput.DisplayCurrencyInputDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	bidList, pageInfo, err := bu.BidRepository.FindBidByAuctionId(
+		ctx, auctionId, bid_entity.BidSort(findBidsInput.Sort), findBidsInput.ToPageRequest())
 	if err != nil {
 		return nil, err
 	}
 
-	var bidOutputList []BidOutputDTO
+	bidOutputList := make([]BidOutputDTO, 0, len(bidList))
 	for _, bid := range bidList {
-		bidOutputList = append(bidOutputList, BidOutputDTO{
-			Id:        bid.Id,
-			UserId:    bid.UserId,
-			AuctionId: bid.AuctionId,
-			Amount:    bid.Amount,
-			Timestamp: bid.Timestamp,
-		})
+		bidOutput := toBidOutputDTO(bid)
+		if bidOutput.ConvertedAmount, err = converter.Convert(ctx, bid.Amount); err != nil {
+			return nil, err
+		}
+		bidOutputList = append(bidOutputList, bidOutput)
 	}
 
-	return bidOutputList, nil
+	return &BidPageOutputDTO{
+		Items: bidOutputList,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
 }
 
 func (bu *BidUseCase) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "BidUseCase.FindWinningBidByAuctionId")
+	defer span.End()
+
 	bidEntity, err := bu.BidRepository.FindWinningBidByAuctionId(ctx, auctionId)
 	if err != nil {
 		return nil, err
 	}
 
-	bidOutput := &BidOutputDTO{
-		Id:        bidEntity.Id,
-		UserId:    bidEntity.UserId,
-		AuctionId: bidEntity.AuctionId,
-		Amount:    bidEntity.Amount,
-		Timestamp: bidEntity.Timestamp,
-	}
-
-	return bidOutput, nil
+	bidOutput := toBidOutputDTO(*bidEntity)
+	return &bidOutput, nil
 }
@@ -0,0 +1,185 @@
+package category_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+type CategoryInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=60"`
+	ParentId string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+// CategoryUpdateInputDTO only changes the informed fields. An empty parent_id
+// moves the category to the root; the slug never changes once created.
+type CategoryUpdateInputDTO struct {
+	Name     *string `json:"name" binding:"omitempty,min=2,max=60"`
+	ParentId *string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+type CategoryOutputDTO struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	ParentId  string    `json:"parent_id,omitempty"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type FindCategoriesInputDTO struct {
+	ParentId string `form:"parentId" binding:"omitempty,uuid|eq=root"`
+}
+
+func NewCategoryUseCase(
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface) CategoryUseCaseInterface {
+	return &CategoryUseCase{
+		categoryRepositoryInterface: categoryRepositoryInterface,
+	}
+}
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(
+		ctx context.Context,
+		categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	UpdateCategory(
+		ctx context.Context,
+		id string,
+		categoryInput CategoryUpdateInputDTO) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	DeleteCategory(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindCategoryById(
+		ctx context.Context, id string) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	FindCategories(
+		ctx context.Context,
+		findCategoriesInput FindCategoriesInputDTO) ([]CategoryOutputDTO, *internal_error.InternalError)
+}
+
+type CategoryUseCase struct {
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface
+}
+
+func (cu *CategoryUseCase) CreateCategory(
+	ctx context.Context,
+	categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.CreateCategory")
+	defer span.End()
+
+	category, err := category_entity.CreateCategory(categoryInput.Name, categoryInput.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.ParentId != "" {
+		if _, err := cu.categoryRepositoryInterface.FindCategoryById(ctx, category.ParentId); err != nil {
+			return nil, parentError(err)
+		}
+	}
+
+	if err := cu.categoryRepositoryInterface.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return toCategoryOutputDTO(*category), nil
+}
+
+func (cu *CategoryUseCase) UpdateCategory(
+	ctx context.Context,
+	id string,
+	categoryInput CategoryUpdateInputDTO) (*CategoryOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.UpdateCategory")
+	defer span.End()
+
+	category, err := cu.categoryRepositoryInterface.FindCategoryById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if categoryInput.Name != nil {
+		category.Name = *categoryInput.Name
+	}
+
+	if categoryInput.ParentId != nil && *categoryInput.ParentId != category.ParentId {
+		category.ParentId = *categoryInput.ParentId
+		if err := cu.validateNewParent(ctx, category); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cu.categoryRepositoryInterface.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return toCategoryOutputDTO(*category), nil
+}
+
+func (cu *CategoryUseCase) DeleteCategory(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.DeleteCategory")
+	defer span.End()
+
+	children, err := cu.categoryRepositoryInterface.FindCategories(ctx, &id)
+	if err != nil {
+		return err
+	}
+
+	if len(children) > 0 {
+		return internal_error.NewConflictError("Category has subcategories and cannot be deleted")
+	}
+
+	return cu.categoryRepositoryInterface.DeleteCategory(ctx, id)
+}
+
+// validateNewParent makes sure the new parent exists and is not the category
+// itself or one of its descendants, which would create a cycle.
+func (cu *CategoryUseCase) validateNewParent(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	if category.ParentId == "" {
+		return nil
+	}
+
+	if _, err := cu.categoryRepositoryInterface.FindCategoryById(ctx, category.ParentId); err != nil {
+		return parentError(err)
+	}
+
+	descendants, err := cu.categoryRepositoryInterface.FindDescendants(ctx, category.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, descendant := range descendants {
+		if descendant.Id == category.ParentId {
+			return internal_error.NewBadRequestError("A category cannot be moved below one of its subcategories")
+		}
+	}
+
+	return nil
+}
+
+func parentError(err *internal_error.InternalError) *internal_error.InternalError {
+	if err.Err == "not_found" {
+		return internal_error.NewBadRequestError("Parent category not found")
+	}
+
+	return err
+}
+
+func toCategoryOutputDTO(category category_entity.Category) *CategoryOutputDTO {
+	return &CategoryOutputDTO{
+		Id:        category.Id,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentId:  category.ParentId,
+		Timestamp: category.Timestamp,
+	}
+}
@@ -0,0 +1,52 @@
+package category_usecase
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+func (cu *CategoryUseCase) FindCategoryById(
+	ctx context.Context, id string) (*CategoryOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.FindCategoryById")
+	defer span.End()
+
+	category, err := cu.categoryRepositoryInterface.FindCategoryById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCategoryOutputDTO(*category), nil
+}
+
+// FindCategories lists every category, the root ones (parentId=root) or the
+// direct children of parentId.
+func (cu *CategoryUseCase) FindCategories(
+	ctx context.Context,
+	findCategoriesInput FindCategoriesInputDTO) ([]CategoryOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.FindCategories")
+	defer span.End()
+
+	var parentId *string
+	switch findCategoriesInput.ParentId {
+	case "":
+	case "root":
+		root := ""
+		parentId = &root
+	default:
+		parentId = &findCategoriesInput.ParentId
+	}
+
+	categories, err := cu.categoryRepositoryInterface.FindCategories(ctx, parentId)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryOutputs := make([]CategoryOutputDTO, 0, len(categories))
+	for _, category := range categories {
+		categoryOutputs = append(categoryOutputs, *toCategoryOutputDTO(category))
+	}
+
+	return categoryOutputs, nil
+}
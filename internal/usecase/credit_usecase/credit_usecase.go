@@ -0,0 +1,141 @@
+package credit_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// CreditLimitInputDTO takes the limit in major units of the account currency.
+type CreditLimitInputDTO struct {
+	Limit money_entity.Decimal `json:"limit" binding:"required"`
+}
+
+// HoldOutputDTO shows, for a bid in another currency, the bid amount and the
+// rate that converted it into the held amount.
+type HoldOutputDTO struct {
+	AuctionId   string                `json:"auction_id"`
+	BidId       string                `json:"bid_id"`
+	Amount      money_entity.Decimal  `json:"amount"`
+	BidAmount   *money_entity.Decimal `json:"bid_amount,omitempty"`
+	BidCurrency string                `json:"bid_currency,omitempty"`
+	Rate        string                `json:"rate,omitempty"`
+	HeldAt      time.Time             `json:"held_at"`
+}
+
+type CreditAccountOutputDTO struct {
+	UserId    string               `json:"user_id"`
+	Currency  string               `json:"currency"`
+	Limit     money_entity.Decimal `json:"limit"`
+	Held      money_entity.Decimal `json:"held"`
+	Available money_entity.Decimal `json:"available"`
+	Holds     []HoldOutputDTO      `json:"holds"`
+}
+
+type CreditUseCaseInterface interface {
+	FindCreditAccount(
+		ctx context.Context, userId string) (*CreditAccountOutputDTO, *internal_error.InternalError)
+
+	SetCreditLimit(
+		ctx context.Context,
+		userId string,
+		creditLimitInput CreditLimitInputDTO) (*CreditAccountOutputDTO, *internal_error.InternalError)
+}
+
+type CreditUseCase struct {
+	creditRepository credit_entity.CreditRepositoryInterface
+	userRepository   user_entity.UserRepositoryInterface
+}
+
+func NewCreditUseCase(
+	creditRepository credit_entity.CreditRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) CreditUseCaseInterface {
+	return &CreditUseCase{
+		creditRepository: creditRepository,
+		userRepository:   userRepository,
+	}
+}
+
+func (cu *CreditUseCase) FindCreditAccount(
+	ctx context.Context, userId string) (*CreditAccountOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CreditUseCase.FindCreditAccount")
+	defer span.End()
+
+	if _, err := cu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	account, err := cu.creditRepository.FindCreditAccount(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCreditAccountOutputDTO(*account), nil
+}
+
+// SetCreditLimit changes the limit of the user. Lowering it below the amount
+// already held keeps the holds and only blocks new bids.
+func (cu *CreditUseCase) SetCreditLimit(
+	ctx context.Context,
+	userId string,
+	creditLimitInput CreditLimitInputDTO) (*CreditAccountOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "CreditUseCase.SetCreditLimit")
+	defer span.End()
+
+	if _, err := cu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	current, err := cu.creditRepository.FindCreditAccount(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := money_entity.Parse(string(creditLimitInput.Limit), current.Currency())
+	if err != nil {
+		return nil, err
+	}
+	if err := credit_entity.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+
+	account, err := cu.creditRepository.SetCreditLimit(ctx, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCreditAccountOutputDTO(*account), nil
+}
+
+func toCreditAccountOutputDTO(account credit_entity.CreditAccount) *CreditAccountOutputDTO {
+	holds := make([]HoldOutputDTO, 0, len(account.Holds))
+	for _, hold := range account.Holds {
+		holdOutput := HoldOutputDTO{
+			AuctionId: hold.AuctionId,
+			BidId:     hold.BidId,
+			Amount:    hold.Amount.Decimal(),
+			HeldAt:    hold.HeldAt,
+		}
+		if hold.Rate != nil {
+			bidAmount := hold.Bid.Decimal()
+			holdOutput.BidAmount = &bidAmount
+			holdOutput.BidCurrency = string(hold.Bid.Currency)
+			holdOutput.Rate = hold.Rate.String()
+		}
+		holds = append(holds, holdOutput)
+	}
+
+	return &CreditAccountOutputDTO{
+		UserId:    account.UserId,
+		Currency:  string(account.Currency()),
+		Limit:     account.Limit.Decimal(),
+		Held:      account.Held.Decimal(),
+		Available: account.Available().Decimal(),
+		Holds:     holds,
+	}
+}
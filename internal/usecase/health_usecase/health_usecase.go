@@ -0,0 +1,119 @@
+package health_usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/health_entity"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// checkTimeout bounds each readiness check, so a hung dependency reports down
+// instead of hanging the probe.
+const checkTimeout = 2 * time.Second
+
+type ComponentOutputDTO struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ReadinessOutputDTO struct {
+	Status     string                        `json:"status"`
+	Detail     string                        `json:"detail,omitempty"`
+	Components map[string]ComponentOutputDTO `json:"components"`
+}
+
+type HealthUseCaseInterface interface {
+	// AddCheck registers checker as the component name of the readiness
+	// report.
+	AddCheck(name string, checker health_entity.CheckerInterface)
+
+	// CheckReadiness checks every component, at once. The application is
+	// ready when they are all up and it is not shutting down.
+	CheckReadiness(ctx context.Context) ReadinessOutputDTO
+
+	// ShutDown makes the application not ready from now on, so the
+	// orchestrator stops routing traffic to it before it stops.
+	ShutDown()
+}
+
+type check struct {
+	name    string
+	checker health_entity.CheckerInterface
+}
+
+type HealthUseCase struct {
+	checks      []check
+	checksMutex sync.RWMutex
+
+	shuttingDown atomic.Bool
+}
+
+func NewHealthUseCase() HealthUseCaseInterface {
+	return &HealthUseCase{}
+}
+
+func (hu *HealthUseCase) AddCheck(name string, checker health_entity.CheckerInterface) {
+	hu.checksMutex.Lock()
+	defer hu.checksMutex.Unlock()
+
+	hu.checks = append(hu.checks, check{name: name, checker: checker})
+}
+
+func (hu *HealthUseCase) CheckReadiness(ctx context.Context) ReadinessOutputDTO {
+	hu.checksMutex.RLock()
+	checks := hu.checks
+	hu.checksMutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	components := make([]ComponentOutputDTO, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = checkComponent(ctx, c.checker)
+		}()
+	}
+	wg.Wait()
+
+	readiness := ReadinessOutputDTO{
+		Status:     StatusReady,
+		Components: make(map[string]ComponentOutputDTO, len(checks)),
+	}
+	for i, c := range checks {
+		readiness.Components[c.name] = components[i]
+		if components[i].Status != StatusUp {
+			readiness.Status = StatusNotReady
+		}
+	}
+
+	if hu.shuttingDown.Load() {
+		readiness.Status = StatusNotReady
+		readiness.Detail = "shutting down"
+	}
+
+	return readiness
+}
+
+func (hu *HealthUseCase) ShutDown() {
+	hu.shuttingDown.Store(true)
+}
+
+func checkComponent(ctx context.Context, checker health_entity.CheckerInterface) ComponentOutputDTO {
+	if err := checker.CheckHealth(ctx); err != nil {
+		return ComponentOutputDTO{Status: StatusDown, Detail: err.Message}
+	}
+
+	return ComponentOutputDTO{Status: StatusUp}
+}
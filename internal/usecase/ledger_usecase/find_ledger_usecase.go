@@ -0,0 +1,60 @@
+package ledger_usecase
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+func (lu *LedgerUseCase) FindLedger(
+	ctx context.Context,
+	auctionId string,
+	findLedgerInput FindLedgerInputDTO) (*LedgerPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerUseCase.FindLedger")
+	defer span.End()
+
+	if _, err := lu.auctionRepository.FindAuctionById(ctx, auctionId); err != nil {
+		return nil, err
+	}
+
+	events, pageInfo, err := lu.ledgerRepository.FindEvents(ctx, auctionId, findLedgerInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	eventOutputs := make([]EventOutputDTO, 0, len(events))
+	for _, event := range events {
+		eventOutputs = append(eventOutputs, toEventOutputDTO(event))
+	}
+
+	return &LedgerPageOutputDTO{
+		Items: eventOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func toEventOutputDTO(event ledger_entity.Event) EventOutputDTO {
+	output := EventOutputDTO{
+		Id:         event.Id,
+		Version:    event.Version,
+		Type:       event.Type,
+		BidId:      event.BidId,
+		UserId:     event.UserId,
+		Reason:     event.Reason,
+		Detail:     event.Detail,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.Amount.Currency != "" {
+		output.Amount = event.Amount.Decimal()
+		output.Currency = string(event.Amount.Currency)
+	}
+	if !event.EndsAt.IsZero() {
+		endsAt := event.EndsAt
+		output.EndsAt = &endsAt
+	}
+
+	return output
+}
@@ -0,0 +1,316 @@
+package ledger_usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type RetractBidInputDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=200"`
+}
+
+// ExtendAuctionInputDTO takes the time added to the end of the auction as a Go
+// duration ("30m", "1h").
+type ExtendAuctionInputDTO struct {
+	Duration string `json:"duration" binding:"required"`
+	Reason   string `json:"reason" binding:"omitempty,max=200"`
+}
+
+// ProjectionOutputDTO is the state of the auction derived from its ledger.
+type ProjectionOutputDTO struct {
+	AuctionId    string               `json:"auction_id"`
+	LeadingBidId string               `json:"leading_bid_id,omitempty"`
+	LeaderId     string               `json:"leader_id,omitempty"`
+	CurrentPrice money_entity.Decimal `json:"current_price,omitempty"`
+	Currency     string               `json:"currency,omitempty"`
+	EndsAt       time.Time            `json:"ends_at"`
+	Version      int64                `json:"version"`
+}
+
+type EventOutputDTO struct {
+	Id         string                  `json:"id"`
+	Version    int64                   `json:"version"`
+	Type       ledger_entity.EventType `json:"type"`
+	BidId      string                  `json:"bid_id,omitempty"`
+	UserId     string                  `json:"user_id,omitempty"`
+	Amount     money_entity.Decimal    `json:"amount,omitempty"`
+	Currency   string                  `json:"currency,omitempty"`
+	Reason     string                  `json:"reason,omitempty"`
+	Detail     string                  `json:"detail,omitempty"`
+	EndsAt     *time.Time              `json:"ends_at,omitempty"`
+	OccurredAt time.Time               `json:"occurred_at"`
+}
+
+type FindLedgerInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+type LedgerPageOutputDTO struct {
+	Items []EventOutputDTO                  `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+// ReplayOutputDTO counts the auctions replayed, the ones whose stored state
+// differed from their ledger and the ones that could not be replayed.
+type ReplayOutputDTO struct {
+	Auctions int `json:"auctions"`
+	Changed  int `json:"changed"`
+	Failed   int `json:"failed"`
+}
+
+type LedgerUseCaseInterface interface {
+	// RetractBid withdraws a bid from its open auction; when it led the
+	// auction, the highest bid left takes the lead.
+	RetractBid(
+		ctx context.Context,
+		bidId string,
+		retractBidInput RetractBidInputDTO) (*ProjectionOutputDTO, *internal_error.InternalError)
+
+	// ExtendAuction moves the end of an open auction that did not end yet.
+	ExtendAuction(
+		ctx context.Context,
+		auctionId string,
+		extendAuctionInput ExtendAuctionInputDTO) (*ProjectionOutputDTO, *internal_error.InternalError)
+
+	FindLedger(
+		ctx context.Context,
+		auctionId string,
+		findLedgerInput FindLedgerInputDTO) (*LedgerPageOutputDTO, *internal_error.InternalError)
+
+	// ReplayAuction rebuilds the leader, the price and the end of the auction
+	// from its ledger.
+	ReplayAuction(
+		ctx context.Context, auctionId string) (*ProjectionOutputDTO, *internal_error.InternalError)
+
+	// ReplayAll replays every auction, going on past the ones that fail.
+	ReplayAll(
+		ctx context.Context) (*ReplayOutputDTO, *internal_error.InternalError)
+}
+
+// projectAttempts bounds how many times a projection is built again after a
+// bid took the lead while it was being written.
+const projectAttempts = 5
+
+type LedgerUseCase struct {
+	ledgerRepository  ledger_entity.LedgerRepositoryInterface
+	auctionRepository auction_entity.AuctionRepositoryInterface
+	bidRepository     bid_entity.BidEntityRepository
+	creditRepository  credit_entity.CreditRepositoryInterface
+
+	auctionInterval time.Duration
+}
+
+func NewLedgerUseCase(
+	ledgerRepository ledger_entity.LedgerRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	bidRepository bid_entity.BidEntityRepository,
+	creditRepository credit_entity.CreditRepositoryInterface) LedgerUseCaseInterface {
+	return &LedgerUseCase{
+		ledgerRepository:  ledgerRepository,
+		auctionRepository: auctionRepository,
+		bidRepository:     bidRepository,
+		creditRepository:  creditRepository,
+		auctionInterval:   getAuctionInterval(),
+	}
+}
+
+func (lu *LedgerUseCase) RetractBid(
+	ctx context.Context,
+	bidId string,
+	retractBidInput RetractBidInputDTO) (*ProjectionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerUseCase.RetractBid")
+	defer span.End()
+
+	bid, err := lu.bidRepository.FindBidById(ctx, bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := lu.findOpenAuction(ctx, bid.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := lu.buildProjection(ctx, *auction)
+	if err != nil {
+		return nil, err
+	}
+	if !before.IsLive(bid.Id) {
+		return nil, internal_error.NewConflictError(
+			fmt.Sprintf("Bid %s is already retracted or not in the ledger", bid.Id))
+	}
+
+	if _, err := lu.ledgerRepository.AppendEvent(
+		ctx, ledger_entity.NewBidRetracted(*bid, retractBidInput.Reason)); err != nil {
+		return nil, err
+	}
+
+	// The ledger is the source of truth; replay marks the bid again.
+	if err := lu.bidRepository.MarkBidRetracted(ctx, bid.Id); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to mark bid %s as retracted", bid.Id), err)
+	}
+
+	projection, _, err := lu.project(ctx, bid.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.Leader != nil && before.Leader.BidId == bid.Id {
+		lu.moveCreditHold(ctx, *bid, projection.Leader)
+	}
+
+	return toProjectionOutputDTO(*projection), nil
+}
+
+// moveCreditHold frees the credit held for a retracted leader and holds it for
+// the bid that took the lead. The new leader may no longer have the credit; as
+// when a hold is lost while the auction runs, this is only logged.
+func (lu *LedgerUseCase) moveCreditHold(
+	ctx context.Context, retracted bid_entity.Bid, leader *auction_entity.LeadingBid) {
+	if err := lu.creditRepository.ReleaseHold(ctx, retracted.UserId, retracted.AuctionId, retracted.Id); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to release the credit held for bid %s", retracted.Id), err)
+	}
+	if leader == nil {
+		return
+	}
+
+	hold := credit_entity.Hold{
+		AuctionId: retracted.AuctionId,
+		BidId:     leader.BidId,
+		Bid:       leader.Amount,
+	}
+	if err := lu.creditRepository.HoldCredit(ctx, leader.UserId, hold); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to hold credit for bid %s", leader.BidId), err)
+	}
+}
+
+func (lu *LedgerUseCase) ExtendAuction(
+	ctx context.Context,
+	auctionId string,
+	extendAuctionInput ExtendAuctionInputDTO) (*ProjectionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerUseCase.ExtendAuction")
+	defer span.End()
+
+	duration, parseErr := time.ParseDuration(extendAuctionInput.Duration)
+	if parseErr != nil || duration <= 0 {
+		return nil, internal_error.NewBadRequestError("Duration must be a positive duration, such as 30m or 1h")
+	}
+
+	auction, err := lu.findOpenAuction(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	endsAt := auction.EndsAt(lu.auctionInterval)
+	if !endsAt.After(time.Now()) {
+		return nil, internal_error.NewConflictError(fmt.Sprintf("Auction %s already ended", auctionId))
+	}
+
+	if _, err := lu.ledgerRepository.AppendEvent(ctx, ledger_entity.NewAuctionExtended(
+		auctionId, endsAt.Add(duration), extendAuctionInput.Reason)); err != nil {
+		return nil, err
+	}
+
+	projection, _, err := lu.project(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectionOutputDTO(*projection), nil
+}
+
+func (lu *LedgerUseCase) findOpenAuction(
+	ctx context.Context, auctionId string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, err := lu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Active && auction.Status != auction_entity.Paused {
+		return nil, internal_error.NewConflictError(fmt.Sprintf("Auction %s is no longer open", auctionId))
+	}
+
+	return auction, nil
+}
+
+// project builds the projection of the auction and stores it. The auction is
+// read before its ledger, and bids enter the ledger before taking the lead, so
+// the stored leader is always in the ledger read; when a bid takes the lead
+// between the reads and the write, the projection is built again.
+func (lu *LedgerUseCase) project(
+	ctx context.Context, auctionId string) (*ledger_entity.Projection, bool, *internal_error.InternalError) {
+	var err *internal_error.InternalError
+	for attempt := 0; attempt < projectAttempts; attempt++ {
+		var auction *auction_entity.Auction
+		if auction, err = lu.auctionRepository.FindAuctionById(ctx, auctionId); err != nil {
+			return nil, false, err
+		}
+
+		var projection *ledger_entity.Projection
+		if projection, err = lu.buildProjection(ctx, *auction); err != nil {
+			return nil, false, err
+		}
+
+		var changed bool
+		changed, err = lu.auctionRepository.UpdateProjection(
+			ctx, auctionId, auction.LeadingBidId, projection.Leader, projection.EndsAt)
+		if err == nil {
+			return projection, changed, nil
+		}
+		if err.Err != "conflict" {
+			return nil, false, err
+		}
+	}
+
+	return nil, false, err
+}
+
+// buildProjection folds the ledger of the auction, which ends interval after
+// its creation unless extended.
+func (lu *LedgerUseCase) buildProjection(
+	ctx context.Context, auction auction_entity.Auction) (*ledger_entity.Projection, *internal_error.InternalError) {
+	events, err := lu.findEvents(ctx, auction.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger_entity.Project(auction.Id, auction.Timestamp.Add(lu.auctionInterval), events)
+}
+
+func toProjectionOutputDTO(projection ledger_entity.Projection) *ProjectionOutputDTO {
+	output := &ProjectionOutputDTO{
+		AuctionId: projection.AuctionId,
+		EndsAt:    projection.EndsAt,
+		Version:   projection.Version,
+	}
+	if projection.Leader != nil {
+		output.LeadingBidId = projection.Leader.BidId
+		output.LeaderId = projection.Leader.UserId
+		output.CurrentPrice = projection.Leader.Amount.Decimal()
+		output.Currency = string(projection.Leader.Amount.Currency)
+	}
+
+	return output
+}
+
+func getAuctionInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_INTERVAL"))
+	if err != nil {
+		return time.Minute * 2
+	}
+
+	return duration
+}
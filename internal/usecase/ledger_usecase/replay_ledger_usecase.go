@@ -0,0 +1,118 @@
+package ledger_usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/ledger_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"go.uber.org/zap"
+)
+
+// replayBatchSize caps the auctions read at a time by ReplayAll.
+const replayBatchSize = 100
+
+func (lu *LedgerUseCase) ReplayAuction(
+	ctx context.Context, auctionId string) (*ProjectionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerUseCase.ReplayAuction")
+	defer span.End()
+
+	projection, _, err := lu.replay(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectionOutputDTO(*projection), nil
+}
+
+func (lu *LedgerUseCase) ReplayAll(ctx context.Context) (*ReplayOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "LedgerUseCase.ReplayAll")
+	defer span.End()
+
+	output := &ReplayOutputDTO{}
+
+	afterId := ""
+	for {
+		auctions, err := lu.auctionRepository.FindAuctionsAfter(ctx, afterId, replayBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(auctions) == 0 {
+			return output, nil
+		}
+
+		for _, auction := range auctions {
+			output.Auctions++
+
+			_, changed, err := lu.replay(ctx, auction.Id)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Error trying to replay auction %s", auction.Id), err)
+				output.Failed++
+				continue
+			}
+			if changed {
+				output.Changed++
+			}
+		}
+
+		afterId = auctions[len(auctions)-1].Id
+	}
+}
+
+// replay stores the projection of the auction and marks again the bids its
+// ledger retracted, in case marking them failed when they were retracted.
+func (lu *LedgerUseCase) replay(
+	ctx context.Context, auctionId string) (*ledger_entity.Projection, bool, *internal_error.InternalError) {
+	projection, changed, err := lu.project(ctx, auctionId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	events, err := lu.ledgerRepository.FindAuctionEvents(ctx, auctionId)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, event := range events {
+		if event.Type != ledger_entity.BidRetracted || event.Version > projection.Version {
+			continue
+		}
+		if err := lu.bidRepository.MarkBidRetracted(ctx, event.BidId); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if changed {
+		logger.Info("Auction projection rebuilt from its ledger",
+			zap.String("auctionId", auctionId), zap.Int64("version", projection.Version))
+	}
+
+	return projection, changed, nil
+}
+
+// findEvents returns the ledger of the auction. Auctions created before bids
+// were recorded in a ledger start with an empty one, so their stored bids are
+// imported first, in the order they were placed.
+func (lu *LedgerUseCase) findEvents(
+	ctx context.Context, auctionId string) ([]ledger_entity.Event, *internal_error.InternalError) {
+	events, err := lu.ledgerRepository.FindAuctionEvents(ctx, auctionId)
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+
+	bids, err := lu.bidRepository.FindAllBidsByAuctionId(ctx, auctionId)
+	if err != nil || len(bids) == 0 {
+		return events, err
+	}
+
+	for _, bid := range bids {
+		if _, err := lu.ledgerRepository.AppendEvent(ctx, ledger_entity.NewBidPlaced(bid)); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info("Stored bids imported into an empty ledger",
+		zap.String("auctionId", auctionId), zap.Int("bids", len(bids)))
+
+	return lu.ledgerRepository.FindAuctionEvents(ctx, auctionId)
+}
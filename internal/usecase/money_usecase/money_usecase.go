@@ -0,0 +1,82 @@
+package money_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+// DisplayCurrencyInputDTO is the currency the user wants to see the amounts in,
+// besides the original ones.
+type DisplayCurrencyInputDTO struct {
+	Currency string `form:"currency" binding:"omitempty,currency"`
+}
+
+// ConvertedAmountOutputDTO is informative only: bids, credit and orders always
+// use the original amount.
+type ConvertedAmountOutputDTO struct {
+	Amount   money_entity.Decimal `json:"amount"`
+	Currency string               `json:"currency"`
+	Rate     string               `json:"rate"`
+	AsOf     time.Time            `json:"as_of"`
+}
+
+// Converter converts amounts into the display currency, looking up the rate of
+// each source currency once. A nil Converter converts nothing, so callers do not
+// need to check whether a display currency was requested.
+type Converter struct {
+	provider money_entity.RateProviderInterface
+	to       money_entity.Currency
+	rates    map[money_entity.Currency]*money_entity.Rate
+}
+
+// NewConverter returns nil when the input has no display currency.
+func NewConverter(
+	provider money_entity.RateProviderInterface,
+	input DisplayCurrencyInputDTO) (*Converter, *internal_error.InternalError) {
+	if input.Currency == "" {
+		return nil, nil
+	}
+
+	to, err := money_entity.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Converter{
+		provider: provider,
+		to:       to,
+		rates:    make(map[money_entity.Currency]*money_entity.Rate),
+	}, nil
+}
+
+func (c *Converter) Convert(
+	ctx context.Context, amount money_entity.Money) (*ConvertedAmountOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "Converter.Convert")
+	defer span.End()
+
+	if c == nil {
+		return nil, nil
+	}
+
+	rate, ok := c.rates[amount.Currency]
+	if !ok {
+		var err *internal_error.InternalError
+		rate, err = c.provider.Rate(ctx, amount.Currency, c.to)
+		if err != nil {
+			return nil, err
+		}
+		c.rates[amount.Currency] = rate
+	}
+
+	converted := amount.Convert(*rate)
+	return &ConvertedAmountOutputDTO{
+		Amount:   converted.Decimal(),
+		Currency: string(converted.Currency),
+		Rate:     rate.String(),
+		AsOf:     rate.AsOf,
+	}, nil
+}
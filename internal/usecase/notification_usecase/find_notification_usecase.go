@@ -0,0 +1,129 @@
+package notification_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+func (nu *NotificationUseCase) FindPreferences(
+	ctx context.Context, userId string) (*PreferencesOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.FindPreferences")
+	defer span.End()
+
+	if _, err := nu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	preferences, err := nu.notificationRepository.FindPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferencesOutputDTO(*preferences), nil
+}
+
+// UpdatePreferences replaces every channel of the user.
+func (nu *NotificationUseCase) UpdatePreferences(
+	ctx context.Context,
+	userId string,
+	preferencesInput PreferencesInputDTO) (*PreferencesOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.UpdatePreferences")
+	defer span.End()
+
+	if _, err := nu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	preferences := &notification_entity.Preferences{
+		UserId:    userId,
+		Channels:  make(map[notification_entity.NotificationType][]notification_entity.Channel),
+		UpdatedAt: time.Now(),
+	}
+	for notificationType, channels := range preferencesInput.Channels {
+		values := make([]notification_entity.Channel, 0, len(channels))
+		for _, channel := range channels {
+			values = append(values, notification_entity.Channel(channel))
+		}
+		preferences.Channels[notification_entity.NotificationType(notificationType)] = values
+	}
+
+	if err := preferences.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := nu.notificationRepository.UpdatePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	return toPreferencesOutputDTO(*preferences), nil
+}
+
+func (nu *NotificationUseCase) FindNotifications(
+	ctx context.Context,
+	userId string,
+	findNotificationsInput FindNotificationsInputDTO) (*NotificationPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.FindNotifications")
+	defer span.End()
+
+	if _, err := nu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	notifications, pageInfo, err := nu.notificationRepository.FindNotifications(
+		ctx, userId, findNotificationsInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]NotificationOutputDTO, 0, len(notifications))
+	for _, notification := range notifications {
+		items = append(items, NotificationOutputDTO{
+			Id:        notification.Id,
+			Type:      string(notification.Type),
+			AuctionId: notification.AuctionId,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			CreatedAt: notification.CreatedAt,
+			ReadAt:    notification.ReadAt,
+		})
+	}
+
+	return &NotificationPageOutputDTO{
+		Items: items,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (nu *NotificationUseCase) MarkAsRead(
+	ctx context.Context, userId, notificationId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.MarkAsRead")
+	defer span.End()
+
+	return nu.notificationRepository.MarkAsRead(ctx, userId, notificationId, time.Now())
+}
+
+func toPreferencesOutputDTO(preferences notification_entity.Preferences) *PreferencesOutputDTO {
+	channels := make(map[string][]string, len(notification_entity.NotificationTypes))
+	for _, notificationType := range notification_entity.NotificationTypes {
+		names := make([]string, 0, len(preferences.Channels[notificationType]))
+		for _, channel := range preferences.Channels[notificationType] {
+			names = append(names, string(channel))
+		}
+		channels[string(notificationType)] = names
+	}
+
+	output := &PreferencesOutputDTO{
+		UserId:   preferences.UserId,
+		Channels: channels,
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		output.UpdatedAt = &preferences.UpdatedAt
+	}
+
+	return output
+}
@@ -0,0 +1,202 @@
+package notification_usecase
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/watchlist_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+	"go.uber.org/zap"
+)
+
+type NotificationOutputDTO struct {
+	Id        string     `json:"id"`
+	Type      string     `json:"type"`
+	AuctionId string     `json:"auction_id"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+type FindNotificationsInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+type NotificationPageOutputDTO struct {
+	Items []NotificationOutputDTO           `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+// PreferencesInputDTO lists the channels of each notification type; types left
+// out are not notified.
+type PreferencesInputDTO struct {
+	Channels map[string][]string `json:"channels" binding:"required"`
+}
+
+type PreferencesOutputDTO struct {
+	UserId    string              `json:"user_id"`
+	Channels  map[string][]string `json:"channels"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty"`
+}
+
+type NotificationUseCaseInterface interface {
+	// NotifyOutbid tells the bidder of previous that bid took the lead.
+	NotifyOutbid(
+		ctx context.Context,
+		previous auction_entity.LeadingBid,
+		bid bid_entity.Bid) *internal_error.InternalError
+
+	// NotifyAuctionWon tells the leader of a completed auction that they won.
+	NotifyAuctionWon(
+		ctx context.Context, auctionId string) *internal_error.InternalError
+
+	// NotifyEndingAuctions tells the watchers of the active auctions ending
+	// within the ending window.
+	NotifyEndingAuctions(
+		ctx context.Context) *internal_error.InternalError
+
+	FindPreferences(
+		ctx context.Context, userId string) (*PreferencesOutputDTO, *internal_error.InternalError)
+
+	UpdatePreferences(
+		ctx context.Context,
+		userId string,
+		preferencesInput PreferencesInputDTO) (*PreferencesOutputDTO, *internal_error.InternalError)
+
+	FindNotifications(
+		ctx context.Context,
+		userId string,
+		findNotificationsInput FindNotificationsInputDTO) (*NotificationPageOutputDTO, *internal_error.InternalError)
+
+	MarkAsRead(
+		ctx context.Context, userId, notificationId string) *internal_error.InternalError
+}
+
+type NotificationUseCase struct {
+	notificationRepository notification_entity.NotificationRepositoryInterface
+	userRepository         user_entity.UserRepositoryInterface
+	auctionRepository      auction_entity.AuctionRepositoryInterface
+	watchlistRepository    watchlist_entity.WatchlistRepositoryInterface
+	notifiers              []notification_entity.NotifierInterface
+
+	auctionInterval time.Duration
+	endingWindow    time.Duration
+	sweepInterval   time.Duration
+}
+
+func NewNotificationUseCase(
+	notificationRepository notification_entity.NotificationRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	watchlistRepository watchlist_entity.WatchlistRepositoryInterface,
+	notifiers ...notification_entity.NotifierInterface) NotificationUseCaseInterface {
+	notificationUseCase := &NotificationUseCase{
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+		auctionRepository:      auctionRepository,
+		watchlistRepository:    watchlistRepository,
+		notifiers:              notifiers,
+		auctionInterval:        getAuctionInterval(),
+		endingWindow:           getEndingWindow(),
+		sweepInterval:          getNotificationSweepInterval(),
+	}
+
+	notificationUseCase.triggerEndingRoutine(context.Background())
+
+	return notificationUseCase
+}
+
+// triggerEndingRoutine periodically warns the watchers of auctions about to end.
+func (nu *NotificationUseCase) triggerEndingRoutine(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(nu.sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := nu.NotifyEndingAuctions(ctx); err != nil {
+					logger.Error("error trying to notify ending auctions", err)
+				}
+			}
+		}
+	}()
+}
+
+// deliver sends the notification through every channel the recipient allows.
+// The key is claimed first, so a fact already notified is skipped even when
+// the delivery through some channel failed.
+func (nu *NotificationUseCase) deliver(
+	ctx context.Context, notification *notification_entity.Notification) *internal_error.InternalError {
+	preferences, err := nu.notificationRepository.FindPreferences(ctx, notification.UserId)
+	if err != nil {
+		return err
+	}
+
+	var notifiers []notification_entity.NotifierInterface
+	for _, notifier := range nu.notifiers {
+		if preferences.Allows(notification.Type, notifier.Channel()) {
+			notifiers = append(notifiers, notifier)
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	claimed, err := nu.notificationRepository.ClaimNotification(ctx, notification.Key, notification.CreatedAt)
+	if err != nil || !claimed {
+		return err
+	}
+
+	recipient, err := nu.userRepository.FindUserById(ctx, notification.UserId)
+	if err != nil {
+		return err
+	}
+
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, *recipient, *notification); err != nil {
+			logger.Error("Error trying to deliver notification", err,
+				zap.String("key", notification.Key), zap.String("channel", string(notifier.Channel())))
+		}
+	}
+
+	return nil
+}
+
+func getAuctionInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_INTERVAL"))
+	if err != nil {
+		return time.Minute * 2
+	}
+
+	return duration
+}
+
+func getEndingWindow() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("NOTIFICATION_ENDING_WINDOW"))
+	if err != nil || duration <= 0 {
+		return 5 * time.Minute
+	}
+
+	return duration
+}
+
+func getNotificationSweepInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("NOTIFICATION_SWEEP_INTERVAL"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
@@ -0,0 +1,136 @@
+package notification_usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/notification_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+func (nu *NotificationUseCase) NotifyOutbid(
+	ctx context.Context,
+	previous auction_entity.LeadingBid,
+	bid bid_entity.Bid) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.NotifyOutbid")
+	defer span.End()
+
+	if previous.UserId == bid.UserId {
+		return nil
+	}
+
+	auction, err := nu.auctionRepository.FindAuctionById(ctx, bid.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	notification, err := notification_entity.CreateNotification(
+		notification_entity.OutbidKey(bid.AuctionId, previous.BidId),
+		previous.UserId,
+		notification_entity.Outbid,
+		bid.AuctionId,
+		"You were outbid",
+		fmt.Sprintf("Your bid of %s on %s was outbid with %s.", previous.Amount, auction.ProductName, bid.Amount))
+	if err != nil {
+		return err
+	}
+
+	return nu.deliver(ctx, notification)
+}
+
+func (nu *NotificationUseCase) NotifyAuctionWon(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.NotifyAuctionWon")
+	defer span.End()
+
+	auction, err := nu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status != auction_entity.Completed || auction.LeaderId == "" {
+		return nil
+	}
+
+	notification, err := notification_entity.CreateNotification(
+		notification_entity.AuctionWonKey(auction.Id, auction.LeaderId),
+		auction.LeaderId,
+		notification_entity.AuctionWon,
+		auction.Id,
+		"You won the auction",
+		fmt.Sprintf("You won %s with a bid of %s.", auction.ProductName, auction.CurrentPrice))
+	if err != nil {
+		return err
+	}
+
+	return nu.deliver(ctx, notification)
+}
+
+func (nu *NotificationUseCase) NotifyEndingAuctions(ctx context.Context) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "NotificationUseCase.NotifyEndingAuctions")
+	defer span.End()
+
+	now := time.Now()
+	endingBefore := now.Add(nu.endingWindow)
+	query := auction_entity.AuctionQuery{
+		AuctionFilter: auction_entity.AuctionFilter{
+			Statuses:     []auction_entity.AuctionStatus{auction_entity.Active},
+			EndingAfter:  &now,
+			EndingBefore: &endingBefore,
+		},
+		Sort: auction_entity.SortEndingSoonest,
+	}
+
+	page := pagination_entity.PageRequest{Limit: pagination_entity.MaxLimit}
+	for {
+		auctions, pageInfo, err := nu.auctionRepository.FindAuctions(ctx, query, page)
+		if err != nil {
+			return err
+		}
+
+		for _, auction := range auctions {
+			if err := nu.notifyWatchers(ctx, auction, now); err != nil {
+				logger.Error("Error trying to notify the watchers of auction "+auction.Id, err)
+			}
+		}
+
+		if !pageInfo.HasMore {
+			return nil
+		}
+		page.Cursor = pageInfo.NextCursor
+	}
+}
+
+func (nu *NotificationUseCase) notifyWatchers(
+	ctx context.Context, auction auction_entity.Auction, now time.Time) *internal_error.InternalError {
+	userIds, err := nu.watchlistRepository.FindWatchers(ctx, auction.Id)
+	if err != nil {
+		return err
+	}
+
+	remaining := auction.Remaining(nu.auctionInterval, now).Round(time.Second)
+	for _, userId := range userIds {
+		notification, err := notification_entity.CreateNotification(
+			notification_entity.AuctionEndingKey(auction.Id, userId),
+			userId,
+			notification_entity.AuctionEnding,
+			auction.Id,
+			"A watched auction is ending",
+			fmt.Sprintf("%s ends in %s, the current price is %s.", auction.ProductName, remaining, auction.CurrentPrice))
+		if err != nil {
+			return err
+		}
+
+		if err := nu.deliver(ctx, notification); err != nil {
+			logger.Error("Error trying to notify watcher "+userId, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package order_usecase
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+func (ou *OrderUseCase) FindOrderById(
+	ctx context.Context, id string) (*OrderOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.FindOrderById")
+	defer span.End()
+
+	order, err := ou.orderRepository.FindOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderOutputDTO(*order), nil
+}
+
+func (ou *OrderUseCase) FindOrders(
+	ctx context.Context,
+	findOrdersInput FindOrdersInputDTO) (*OrderPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.FindOrders")
+	defer span.End()
+
+	filter := order_entity.OrderFilter{
+		BuyerId:  findOrdersInput.BuyerId,
+		SellerId: findOrdersInput.SellerId,
+		Status:   order_entity.OrderStatus(findOrdersInput.Status),
+	}
+
+	orders, pageInfo, err := ou.orderRepository.FindOrders(ctx, filter, findOrdersInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	orderOutputs := make([]OrderOutputDTO, 0, len(orders))
+	for _, order := range orders {
+		orderOutputs = append(orderOutputs, *toOrderOutputDTO(order))
+	}
+
+	return &OrderPageOutputDTO{
+		Items: orderOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
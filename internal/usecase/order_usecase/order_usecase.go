@@ -0,0 +1,210 @@
+package order_usecase
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/bid_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/credit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type OrderOutputDTO struct {
+	Id           string                   `json:"id"`
+	AuctionId    string                   `json:"auction_id"`
+	SellerId     string                   `json:"seller_id,omitempty"`
+	BuyerId      string                   `json:"buyer_id"`
+	BidId        string                   `json:"bid_id"`
+	Amount       money_entity.Decimal     `json:"amount"`
+	Currency     string                   `json:"currency"`
+	Status       order_entity.OrderStatus `json:"status"`
+	SecondChance bool                     `json:"second_chance"`
+	PaymentId    string                   `json:"payment_id,omitempty"`
+	TrackingCode string                   `json:"tracking_code,omitempty"`
+	PaymentDueAt time.Time                `json:"payment_due_at"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// FindOrdersInputDTO filters the orders; the controller fixes BuyerId or
+// SellerId to the authenticated user outside the admin routes.
+type FindOrdersInputDTO struct {
+	pagination_usecase.PageInputDTO
+
+	Status   string `form:"status" binding:"omitempty,oneof=awaiting_payment paid shipped completed unpaid_timeout"`
+	BuyerId  string `form:"buyerId" binding:"omitempty,uuid"`
+	SellerId string `form:"sellerId" binding:"omitempty,uuid"`
+}
+
+type OrderPageOutputDTO struct {
+	Items []OrderOutputDTO                  `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+type PayOrderInputDTO struct {
+	PaymentMethod string `json:"payment_method" binding:"required,min=3,max=100"`
+}
+
+type ShipOrderInputDTO struct {
+	TrackingCode string `json:"tracking_code" binding:"required,min=3,max=100"`
+}
+
+type OrderUseCaseInterface interface {
+	// CreateAuctionOrder creates the order of the winner of a completed
+	// auction. It does nothing for auctions without bids or already settled.
+	CreateAuctionOrder(
+		ctx context.Context, auctionId string) *internal_error.InternalError
+
+	ExpireOverdueOrders(
+		ctx context.Context) *internal_error.InternalError
+
+	FindOrderById(
+		ctx context.Context, id string) (*OrderOutputDTO, *internal_error.InternalError)
+
+	FindOrders(
+		ctx context.Context,
+		findOrdersInput FindOrdersInputDTO) (*OrderPageOutputDTO, *internal_error.InternalError)
+
+	PayOrder(
+		ctx context.Context,
+		id, buyerId string,
+		payOrderInput PayOrderInputDTO) (*OrderOutputDTO, *internal_error.InternalError)
+
+	ShipOrder(
+		ctx context.Context,
+		id, sellerId string,
+		shipOrderInput ShipOrderInputDTO) (*OrderOutputDTO, *internal_error.InternalError)
+
+	CompleteOrder(
+		ctx context.Context, id, buyerId string) (*OrderOutputDTO, *internal_error.InternalError)
+
+	// Stop stops expiring the overdue orders, once the current sweep is done.
+	Stop(ctx context.Context) *internal_error.InternalError
+}
+
+// overdueBatchSize caps the orders expired on each sweep.
+const overdueBatchSize = 100
+
+type OrderUseCase struct {
+	orderRepository   order_entity.OrderRepositoryInterface
+	auctionRepository auction_entity.AuctionRepositoryInterface
+	bidRepository     bid_entity.BidEntityRepository
+	creditRepository  credit_entity.CreditRepositoryInterface
+	paymentProvider   order_entity.PaymentProviderInterface
+	recorder          audit_entity.RecorderInterface
+
+	paymentWindow time.Duration
+	sweepInterval time.Duration
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewOrderUseCase(
+	orderRepository order_entity.OrderRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	bidRepository bid_entity.BidEntityRepository,
+	creditRepository credit_entity.CreditRepositoryInterface,
+	paymentProvider order_entity.PaymentProviderInterface,
+	recorder audit_entity.RecorderInterface) OrderUseCaseInterface {
+	orderUseCase := &OrderUseCase{
+		orderRepository:   orderRepository,
+		auctionRepository: auctionRepository,
+		bidRepository:     bidRepository,
+		creditRepository:  creditRepository,
+		paymentProvider:   paymentProvider,
+		recorder:          recorder,
+		paymentWindow:     getPaymentWindow(),
+		sweepInterval:     getSettlementSweepInterval(),
+		stop:              make(chan struct{}),
+		stopped:           make(chan struct{}),
+	}
+
+	orderUseCase.triggerExpireRoutine(context.Background())
+
+	return orderUseCase
+}
+
+// triggerExpireRoutine periodically times out the orders not paid in time.
+func (ou *OrderUseCase) triggerExpireRoutine(ctx context.Context) {
+	go func() {
+		defer close(ou.stopped)
+
+		ticker := time.NewTicker(ou.sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ou.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ou.ExpireOverdueOrders(ctx); err != nil {
+					logger.Error("error trying to expire overdue orders", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop may be called more than once.
+func (ou *OrderUseCase) Stop(ctx context.Context) *internal_error.InternalError {
+	ou.stopOnce.Do(func() {
+		close(ou.stop)
+	})
+
+	select {
+	case <-ou.stopped:
+		return nil
+	case <-ctx.Done():
+		return internal_error.NewInternalServerError("Timed out expiring the overdue orders")
+	}
+}
+
+func toOrderOutputDTO(order order_entity.Order) *OrderOutputDTO {
+	return &OrderOutputDTO{
+		Id:           order.Id,
+		AuctionId:    order.AuctionId,
+		SellerId:     order.SellerId,
+		BuyerId:      order.BuyerId,
+		BidId:        order.BidId,
+		Amount:       order.Amount.Decimal(),
+		Currency:     string(order.Amount.Currency),
+		Status:       order.Status,
+		SecondChance: order.SecondChance,
+		PaymentId:    order.PaymentId,
+		TrackingCode: order.TrackingCode,
+		PaymentDueAt: order.PaymentDueAt,
+		CreatedAt:    order.CreatedAt,
+		UpdatedAt:    order.UpdatedAt,
+	}
+}
+
+func getPaymentWindow() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("PAYMENT_WINDOW"))
+	if err != nil || duration <= 0 {
+		return 48 * time.Hour
+	}
+
+	return duration
+}
+
+func getSettlementSweepInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("SETTLEMENT_SWEEP_INTERVAL"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
@@ -0,0 +1,214 @@
+package order_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/audit_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/order_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+func (ou *OrderUseCase) CreateAuctionOrder(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.CreateAuctionOrder")
+	defer span.End()
+
+	auction, err := ou.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+	if auction.Status != auction_entity.Completed {
+		return nil
+	}
+
+	winningBid, err := ou.bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil
+		}
+		return err
+	}
+
+	order, err := order_entity.CreateOrder(
+		auction.Id, auction.SellerId, winningBid.UserId, winningBid.Id, winningBid.Amount, false, ou.paymentWindow)
+	if err != nil {
+		return err
+	}
+
+	return ou.createOrder(ctx, order)
+}
+
+// ExpireOverdueOrders times out the orders whose payment deadline passed and
+// offers the auction to the runner-up when the winner was the one who did not pay.
+func (ou *OrderUseCase) ExpireOverdueOrders(ctx context.Context) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.ExpireOverdueOrders")
+	defer span.End()
+
+	orders, err := ou.orderRepository.FindOverdueOrders(ctx, time.Now(), overdueBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, overdue := range orders {
+		order, err := ou.orderRepository.UpdateOrderStatus(
+			ctx, overdue.Id, order_entity.ActionExpire, order_entity.OrderChanges{})
+		if err != nil {
+			// Paid at the last moment or expired by another instance.
+			continue
+		}
+
+		ou.recorder.Record(ctx, audit_entity.NewEntry(
+			ctx, "order.expire", "order", order.Id, toOrderOutputDTO(overdue), toOrderOutputDTO(*order)))
+		ou.releaseCredit(ctx, *order)
+
+		if !order.SecondChance {
+			if err := ou.offerSecondChance(ctx, *order); err != nil {
+				logger.Error("error trying to offer a second chance", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ou *OrderUseCase) offerSecondChance(
+	ctx context.Context, expired order_entity.Order) *internal_error.InternalError {
+	runnerUp, err := ou.bidRepository.FindRunnerUpBid(ctx, expired.AuctionId, expired.BuyerId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil
+		}
+		return err
+	}
+
+	order, err := order_entity.CreateOrder(
+		expired.AuctionId, expired.SellerId, runnerUp.UserId, runnerUp.Id, runnerUp.Amount, true, ou.paymentWindow)
+	if err != nil {
+		return err
+	}
+
+	return ou.createOrder(ctx, order)
+}
+
+// createOrder stores an order created by the settlement, which another
+// instance may have created already.
+func (ou *OrderUseCase) createOrder(ctx context.Context, order *order_entity.Order) *internal_error.InternalError {
+	if err := ou.orderRepository.CreateOrder(ctx, order); err != nil {
+		return ignoreConflict(err)
+	}
+
+	ou.recorder.Record(ctx, audit_entity.NewEntry(ctx, "order.create", "order", order.Id, nil, toOrderOutputDTO(*order)))
+	return nil
+}
+
+func (ou *OrderUseCase) PayOrder(
+	ctx context.Context,
+	id, buyerId string,
+	payOrderInput PayOrderInputDTO) (*OrderOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.PayOrder")
+	defer span.End()
+
+	order, err := ou.orderRepository.FindOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerId != buyerId {
+		return nil, internal_error.NewForbiddenError("Only the buyer can pay this order")
+	}
+
+	if order.Status != order_entity.AwaitingPayment {
+		return nil, internal_error.NewConflictError("Order is not awaiting payment")
+	}
+
+	if order.IsOverdue(time.Now()) {
+		return nil, internal_error.NewConflictError("Order payment deadline has passed")
+	}
+
+	paymentId, err := ou.paymentProvider.Capture(ctx, *order, payOrderInput.PaymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	paid, err := ou.orderRepository.UpdateOrderStatus(
+		ctx, id, order_entity.ActionPay, order_entity.OrderChanges{PaymentId: paymentId})
+	if err != nil {
+		// The order expired meanwhile; give the money back.
+		if refundErr := ou.paymentProvider.Refund(ctx, paymentId); refundErr != nil {
+			logger.Error("error trying to refund payment "+paymentId, refundErr)
+		}
+		return nil, err
+	}
+
+	ou.releaseCredit(ctx, *paid)
+
+	return toOrderOutputDTO(*paid), nil
+}
+
+func (ou *OrderUseCase) ShipOrder(
+	ctx context.Context,
+	id, sellerId string,
+	shipOrderInput ShipOrderInputDTO) (*OrderOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.ShipOrder")
+	defer span.End()
+
+	order, err := ou.orderRepository.FindOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.SellerId != sellerId {
+		return nil, internal_error.NewForbiddenError("Only the seller can ship this order")
+	}
+
+	shipped, err := ou.orderRepository.UpdateOrderStatus(
+		ctx, id, order_entity.ActionShip, order_entity.OrderChanges{TrackingCode: shipOrderInput.TrackingCode})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderOutputDTO(*shipped), nil
+}
+
+func (ou *OrderUseCase) CompleteOrder(
+	ctx context.Context, id, buyerId string) (*OrderOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "OrderUseCase.CompleteOrder")
+	defer span.End()
+
+	order, err := ou.orderRepository.FindOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerId != buyerId {
+		return nil, internal_error.NewForbiddenError("Only the buyer can confirm the receipt")
+	}
+
+	completed, err := ou.orderRepository.UpdateOrderStatus(
+		ctx, id, order_entity.ActionComplete, order_entity.OrderChanges{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderOutputDTO(*completed), nil
+}
+
+// releaseCredit frees the buyer hold in the auction once the order is paid or
+// timed out; the credit is either spent or no longer committed.
+func (ou *OrderUseCase) releaseCredit(ctx context.Context, order order_entity.Order) {
+	if err := ou.creditRepository.ReleaseHold(ctx, order.BuyerId, order.AuctionId, order.BidId); err != nil {
+		logger.Error("error trying to release the credit of order "+order.Id, err)
+	}
+}
+
+func ignoreConflict(err *internal_error.InternalError) *internal_error.InternalError {
+	if err != nil && err.Err == "conflict" {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,257 @@
+package outbox_usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"go.uber.org/zap"
+)
+
+// Handler handles an event for an in-process subscriber. Events are delivered
+// at least once, so handlers must tolerate an event they already handled.
+type Handler func(ctx context.Context, event outbox_entity.Event) *internal_error.InternalError
+
+type OutboxUseCaseInterface interface {
+	// Subscribe registers handler as the consumer name. The offset of each
+	// consumer is kept under its name, so names must not change between
+	// releases.
+	Subscribe(name string, handler Handler)
+
+	// AddPublisher registers publisher as the consumer name, like Subscribe.
+	AddPublisher(name string, publisher outbox_entity.PublisherInterface)
+
+	// Relay numbers the new events and delivers them to every consumer, in
+	// order. A consumer whose handler fails stops at the failed event and
+	// retries it on a later relay, backing off after each failure; once the
+	// event failed the maximum attempts, it is dead-lettered and skipped.
+	Relay(ctx context.Context) *internal_error.InternalError
+}
+
+// relayBatchSize caps the events numbered and delivered to each consumer on
+// each relay.
+const relayBatchSize = 100
+
+// maxRetryBackoff caps the wait before retrying an event that keeps failing.
+const maxRetryBackoff = 5 * time.Minute
+
+type consumer struct {
+	name    string
+	handler Handler
+}
+
+// deliveryFailure counts the failed attempts of a consumer at the event it is
+// stuck on.
+type deliveryFailure struct {
+	sequence int64
+	attempts int
+	retryAt  time.Time
+}
+
+type OutboxUseCase struct {
+	outboxRepository outbox_entity.OutboxRepositoryInterface
+
+	relayInterval time.Duration
+	maxAttempts   int
+
+	consumers      []consumer
+	consumersMutex sync.RWMutex
+
+	// failures is kept by consumer name. It lives in memory, so a restart
+	// gives a failing event its attempts back.
+	failures      map[string]*deliveryFailure
+	failuresMutex sync.Mutex
+}
+
+func NewOutboxUseCase(outboxRepository outbox_entity.OutboxRepositoryInterface) OutboxUseCaseInterface {
+	outboxUseCase := &OutboxUseCase{
+		outboxRepository: outboxRepository,
+		relayInterval:    getRelayInterval(),
+		maxAttempts:      getMaxAttempts(),
+		failures:         make(map[string]*deliveryFailure),
+	}
+
+	outboxUseCase.triggerRelayRoutine(context.Background())
+
+	return outboxUseCase
+}
+
+func (ou *OutboxUseCase) Subscribe(name string, handler Handler) {
+	ou.consumersMutex.Lock()
+	defer ou.consumersMutex.Unlock()
+
+	ou.consumers = append(ou.consumers, consumer{name: name, handler: handler})
+}
+
+func (ou *OutboxUseCase) AddPublisher(name string, publisher outbox_entity.PublisherInterface) {
+	ou.Subscribe(name, publisher.Publish)
+}
+
+func (ou *OutboxUseCase) Relay(ctx context.Context) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "OutboxUseCase.Relay")
+	defer span.End()
+
+	for {
+		numbered, err := ou.outboxRepository.SequenceEvents(ctx, relayBatchSize)
+		if err != nil {
+			return err
+		}
+		if numbered < relayBatchSize {
+			break
+		}
+	}
+
+	ou.consumersMutex.RLock()
+	consumers := append([]consumer(nil), ou.consumers...)
+	ou.consumersMutex.RUnlock()
+
+	// Consumers advance independently, so a slow or failing one does not hold
+	// the others back.
+	var wg sync.WaitGroup
+	for _, c := range consumers {
+		wg.Add(1)
+		go func(c consumer) {
+			defer wg.Done()
+			ou.deliver(ctx, c)
+		}(c)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver hands the events after the offset of c to its handler and moves the
+// offset past the ones handled or given up on.
+func (ou *OutboxUseCase) deliver(ctx context.Context, c consumer) {
+	if ou.backingOff(c.name) {
+		return
+	}
+
+	offset, err := ou.outboxRepository.FindOffset(ctx, c.name)
+	if err != nil {
+		return
+	}
+
+	events, err := ou.outboxRepository.FindEventsAfter(ctx, offset, relayBatchSize)
+	if err != nil {
+		return
+	}
+
+	handled := offset
+	for _, event := range events {
+		if err := c.handler(ctx, event); err != nil {
+			logger.Error("Error trying to deliver outbox event", err,
+				zap.String("consumer", c.name), zap.Int64("sequence", event.Sequence),
+				zap.String("event_type", string(event.Type)))
+			if !ou.giveUp(ctx, c.name, event, err) {
+				break
+			}
+		}
+		ou.clearFailure(c.name)
+		handled = event.Sequence
+	}
+
+	if handled > offset {
+		ou.outboxRepository.UpdateOffset(ctx, c.name, handled)
+	}
+}
+
+// backingOff tells whether the consumer must wait before retrying the event
+// it failed on.
+func (ou *OutboxUseCase) backingOff(name string) bool {
+	ou.failuresMutex.Lock()
+	defer ou.failuresMutex.Unlock()
+
+	failure, ok := ou.failures[name]
+	return ok && time.Now().Before(failure.retryAt)
+}
+
+// giveUp counts a failed attempt at event and tells whether the consumer gave
+// up on it, which only happens once it is dead-lettered. Until then, the wait
+// before the next attempt doubles from the relay interval.
+func (ou *OutboxUseCase) giveUp(
+	ctx context.Context, name string, event outbox_entity.Event, cause *internal_error.InternalError) bool {
+	ou.failuresMutex.Lock()
+	failure, ok := ou.failures[name]
+	if !ok || failure.sequence != event.Sequence {
+		failure = &deliveryFailure{sequence: event.Sequence}
+		ou.failures[name] = failure
+	}
+	failure.attempts++
+	attempts := failure.attempts
+
+	backoff := ou.relayInterval
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	failure.retryAt = time.Now().Add(backoff)
+	ou.failuresMutex.Unlock()
+
+	if attempts < ou.maxAttempts {
+		return false
+	}
+
+	detail := fmt.Sprintf("failed %d times, last with: %s", attempts, cause.Error())
+	if err := ou.outboxRepository.DeadLetterEvent(ctx, name, event, detail); err != nil {
+		return false
+	}
+
+	logger.Info("Outbox event dead-lettered", zap.String("consumer", name),
+		zap.Int64("sequence", event.Sequence), zap.String("event_type", string(event.Type)))
+	return true
+}
+
+func (ou *OutboxUseCase) clearFailure(name string) {
+	ou.failuresMutex.Lock()
+	defer ou.failuresMutex.Unlock()
+
+	delete(ou.failures, name)
+}
+
+// triggerRelayRoutine periodically relays the events recorded by the
+// repositories.
+func (ou *OutboxUseCase) triggerRelayRoutine(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(ou.relayInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ou.Relay(ctx); err != nil {
+					logger.Error("error trying to relay outbox events", err)
+				}
+			}
+		}
+	}()
+}
+
+func getRelayInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("OUTBOX_RELAY_INTERVAL"))
+	if err != nil || duration <= 0 {
+		return time.Second
+	}
+
+	return duration
+}
+
+func getMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("OUTBOX_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		return 5
+	}
+
+	return attempts
+}
@@ -0,0 +1,154 @@
+package outbox_usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutbox keeps numbered events, offsets and dead letters in memory.
+type memoryOutbox struct {
+	outbox_entity.OutboxRepositoryInterface
+
+	mutex       sync.Mutex
+	events      []outbox_entity.Event
+	offsets     map[string]int64
+	deadLetters []outbox_entity.Event
+}
+
+func newMemoryOutbox(sequences ...int64) *memoryOutbox {
+	outbox := &memoryOutbox{offsets: make(map[string]int64)}
+	for _, sequence := range sequences {
+		outbox.events = append(outbox.events, outbox_entity.Event{Sequence: sequence, Type: outbox_entity.BidAccepted})
+	}
+	return outbox
+}
+
+func (o *memoryOutbox) SequenceEvents(ctx context.Context, limit int) (int, *internal_error.InternalError) {
+	return 0, nil
+}
+
+func (o *memoryOutbox) FindEventsAfter(
+	ctx context.Context, sequence int64, limit int) ([]outbox_entity.Event, *internal_error.InternalError) {
+	var events []outbox_entity.Event
+	for _, event := range o.events {
+		if event.Sequence > sequence && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (o *memoryOutbox) FindOffset(ctx context.Context, consumer string) (int64, *internal_error.InternalError) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.offsets[consumer], nil
+}
+
+func (o *memoryOutbox) UpdateOffset(
+	ctx context.Context, consumer string, sequence int64) *internal_error.InternalError {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.offsets[consumer] = sequence
+	return nil
+}
+
+func (o *memoryOutbox) DeadLetterEvent(
+	ctx context.Context, consumer string, event outbox_entity.Event, detail string) *internal_error.InternalError {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.deadLetters = append(o.deadLetters, event)
+	return nil
+}
+
+// failingOn returns a handler failing on the event numbered sequence and the
+// sequences it was called with.
+func failingOn(sequence int64) (Handler, func() []int64) {
+	var mutex sync.Mutex
+	var calls []int64
+
+	handler := func(ctx context.Context, event outbox_entity.Event) *internal_error.InternalError {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		calls = append(calls, event.Sequence)
+		if event.Sequence == sequence {
+			return internal_error.NewInternalServerError("handler error")
+		}
+		return nil
+	}
+
+	return handler, func() []int64 {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]int64(nil), calls...)
+	}
+}
+
+func newOutboxUseCase(outbox *memoryOutbox, relayInterval time.Duration) *OutboxUseCase {
+	return &OutboxUseCase{
+		outboxRepository: outbox,
+		relayInterval:    relayInterval,
+		maxAttempts:      3,
+		failures:         make(map[string]*deliveryFailure),
+	}
+}
+
+func TestRelay(t *testing.T) {
+	t.Run("should dead-letter an event that keeps failing and deliver the next ones", func(t *testing.T) {
+		outbox := newMemoryOutbox(1, 2, 3)
+		useCase := newOutboxUseCase(outbox, time.Nanosecond)
+		handler, calls := failingOn(2)
+		useCase.Subscribe("orders", handler)
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(time.Millisecond)
+			require.Nil(t, useCase.Relay(context.Background()))
+		}
+
+		assert.Equal(t, []int64{1, 2, 2, 2, 3}, calls())
+		require.Len(t, outbox.deadLetters, 1)
+		assert.Equal(t, int64(2), outbox.deadLetters[0].Sequence)
+		assert.Equal(t, int64(3), outbox.offsets["orders"])
+		assert.Empty(t, useCase.failures)
+	})
+
+	t.Run("should wait before retrying a failed event", func(t *testing.T) {
+		outbox := newMemoryOutbox(1, 2)
+		useCase := newOutboxUseCase(outbox, time.Hour)
+		handler, calls := failingOn(1)
+		useCase.Subscribe("orders", handler)
+
+		require.Nil(t, useCase.Relay(context.Background()))
+		require.Nil(t, useCase.Relay(context.Background()))
+
+		assert.Equal(t, []int64{1}, calls())
+		assert.Empty(t, outbox.deadLetters)
+		assert.Zero(t, outbox.offsets["orders"])
+	})
+
+	t.Run("should not hold back the other consumers", func(t *testing.T) {
+		outbox := newMemoryOutbox(1, 2)
+		useCase := newOutboxUseCase(outbox, time.Hour)
+		failing, _ := failingOn(1)
+		healthy, calls := failingOn(0)
+		useCase.Subscribe("orders", failing)
+		useCase.Subscribe("webhooks", healthy)
+
+		require.Nil(t, useCase.Relay(context.Background()))
+
+		assert.Equal(t, []int64{1, 2}, calls())
+		assert.Equal(t, int64(2), outbox.offsets["webhooks"])
+	})
+}
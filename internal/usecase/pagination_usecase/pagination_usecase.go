@@ -0,0 +1,50 @@
+package pagination_usecase
+
+import (
+	"strconv"
+
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/pagination_entity"
+)
+
+type PageInputDTO struct {
+	Limit        string `form:"limit" binding:"omitempty,number"`
+	Cursor       string `form:"cursor"`
+	IncludeTotal string `form:"includeTotal" binding:"omitempty,boolean"`
+}
+
+type PageOutputDTO struct {
+	Limit      int64  `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+type LinksOutputDTO struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+// ToPageRequest converts the already validated query values into a page request.
+func (p PageInputDTO) ToPageRequest() pagination_entity.PageRequest {
+	limit, _ := strconv.ParseInt(p.Limit, 10, 64)
+	includeTotal, _ := strconv.ParseBool(p.IncludeTotal)
+
+	return pagination_entity.PageRequest{
+		Limit:        limit,
+		Cursor:       p.Cursor,
+		IncludeTotal: includeTotal,
+	}
+}
+
+func NewPageOutputDTO(pageInfo *pagination_entity.PageInfo) PageOutputDTO {
+	if pageInfo == nil {
+		return PageOutputDTO{}
+	}
+
+	return PageOutputDTO{
+		Limit:      pageInfo.Limit,
+		NextCursor: pageInfo.NextCursor,
+		HasMore:    pageInfo.HasMore,
+		Total:      pageInfo.Total,
+	}
+}
@@ -0,0 +1,149 @@
+package user_usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+)
+
+type UserInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	// Roles lets users register as sellers; admin can only be granted by another admin.
+	Roles []string `json:"roles" binding:"omitempty,dive,oneof=seller bidder"`
+}
+
+// UserUpdateInputDTO only changes the informed fields.
+type UserUpdateInputDTO struct {
+	Name     *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Password *string `json:"password" binding:"omitempty,min=8,max=72"`
+}
+
+type UserRolesInputDTO struct {
+	Roles []string `json:"roles" binding:"required,min=1,dive,oneof=admin seller bidder"`
+}
+
+func (u *UserUseCase) CreateUser(
+	ctx context.Context,
+	userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.CreateUser")
+	defer span.End()
+
+	user, err := user_entity.CreateUser(userInput.Name, userInput.Email, userInput.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userInput.Roles) > 0 {
+		if err := user.SetRoles(toRoles(userInput.Roles)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.ensureEmailAvailable(ctx, user.Email, user.Id); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUserOutputDTO(*user), nil
+}
+
+func (u *UserUseCase) UpdateUser(
+	ctx context.Context,
+	id string,
+	userInput UserUpdateInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.UpdateUser")
+	defer span.End()
+
+	user, err := u.UserRepository.FindUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if userInput.Name != nil {
+		user.Name = *userInput.Name
+	}
+
+	if userInput.Email != nil {
+		user.Email = user_entity.NormalizeEmail(*userInput.Email)
+	}
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	if userInput.Password != nil {
+		if err := user.SetPassword(*userInput.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.ensureEmailAvailable(ctx, user.Email, user.Id); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUserOutputDTO(*user), nil
+}
+
+func (u *UserUseCase) UpdateUserRoles(
+	ctx context.Context,
+	id string,
+	rolesInput UserRolesInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.UpdateUserRoles")
+	defer span.End()
+
+	user, err := u.UserRepository.FindUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.SetRoles(toRoles(rolesInput.Roles)); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.UpdateUserRoles(ctx, user.Id, user.Roles); err != nil {
+		return nil, err
+	}
+
+	return toUserOutputDTO(*user), nil
+}
+
+func toRoles(names []string) []user_entity.Role {
+	roles := make([]user_entity.Role, 0, len(names))
+	for _, name := range names {
+		roles = append(roles, user_entity.Role(name))
+	}
+
+	return roles
+}
+
+// ensureEmailAvailable gives a friendly conflict before writing; the unique index
+// on email still protects concurrent registrations with the same address.
+func (u *UserUseCase) ensureEmailAvailable(
+	ctx context.Context, email, userId string) *internal_error.InternalError {
+	existing, err := u.UserRepository.FindUserByEmail(ctx, email)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil
+		}
+		return err
+	}
+
+	if existing.Id != userId {
+		return internal_error.NewConflictError(fmt.Sprintf("Email %s is already in use", email))
+	}
+
+	return nil
+}
@@ -2,9 +2,12 @@ package user_usecase
 
 import (
 	"context"
+	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
 	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
 )
 
 func NewUserUseCase(userRepository user_entity.UserRepositoryInterface) UserUseCaseInterface {
@@ -18,25 +21,120 @@ type UserUseCase struct {
 }
 
 type UserOutputDTO struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Roles     []string  `json:"roles"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+// PublicUserOutputDTO is the profile shown to anyone but the user and the
+// admins, without the email and the roles.
+type PublicUserOutputDTO struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u UserOutputDTO) ToPublic() PublicUserOutputDTO {
+	return PublicUserOutputDTO{
+		Id:        u.Id,
+		Name:      u.Name,
+		Timestamp: u.Timestamp,
+	}
+}
+
+type FindUsersInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+type UserPageOutputDTO struct {
+	Items []UserOutputDTO                   `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+// PublicUserPageOutputDTO is the page of users shown to anyone but the admins.
+type PublicUserPageOutputDTO struct {
+	Items []PublicUserOutputDTO             `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+func (p UserPageOutputDTO) ToPublic() PublicUserPageOutputDTO {
+	items := make([]PublicUserOutputDTO, 0, len(p.Items))
+	for _, user := range p.Items {
+		items = append(items, user.ToPublic())
+	}
+
+	return PublicUserPageOutputDTO{Items: items, Page: p.Page, Links: p.Links}
 }
 
 type UserUseCaseInterface interface {
+	CreateUser(
+		ctx context.Context,
+		userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context,
+		id string,
+		userInput UserUpdateInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	UpdateUserRoles(
+		ctx context.Context,
+		id string,
+		rolesInput UserRolesInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
 	FindUserById(
 		ctx context.Context,
 		id string) (*UserOutputDTO, *internal_error.InternalError)
+
+	FindUsers(
+		ctx context.Context,
+		findUsersInput FindUsersInputDTO) (*UserPageOutputDTO, *internal_error.InternalError)
 }
 
 func (u *UserUseCase) FindUserById(
 	ctx context.Context, id string) (*UserOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.FindUserById")
+	defer span.End()
+
 	userEntity, err := u.UserRepository.FindUserById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &UserOutputDTO{
-		Id:   userEntity.Id,
-		Name: userEntity.Name,
+	return toUserOutputDTO(*userEntity), nil
+}
+
+func (u *UserUseCase) FindUsers(
+	ctx context.Context,
+	findUsersInput FindUsersInputDTO) (*UserPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.FindUsers")
+	defer span.End()
+
+	users, pageInfo, err := u.UserRepository.FindUsers(ctx, findUsersInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	userOutputs := make([]UserOutputDTO, 0, len(users))
+	for _, user := range users {
+		userOutputs = append(userOutputs, *toUserOutputDTO(user))
+	}
+
+	return &UserPageOutputDTO{
+		Items: userOutputs,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
 	}, nil
 }
+
+func toUserOutputDTO(user user_entity.User) *UserOutputDTO {
+	return &UserOutputDTO{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		Roles:     user.RoleNames(),
+		Timestamp: user.Timestamp,
+	}
+}
@@ -0,0 +1,192 @@
+package watchlist_usecase
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/watchlist_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type FindWatchlistInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+// HighestBidOutputDTO is the bid leading the auction, taken from the auction
+// itself so the watchlist needs no query per auction.
+type HighestBidOutputDTO struct {
+	BidId    string               `json:"bid_id"`
+	UserId   string               `json:"user_id"`
+	Amount   money_entity.Decimal `json:"amount"`
+	Currency string               `json:"currency"`
+}
+
+type WatchedAuctionOutputDTO struct {
+	Auction              auction_usecase.AuctionOutputDTO `json:"auction"`
+	HighestBid           *HighestBidOutputDTO             `json:"highest_bid"`
+	EndsAt               time.Time                        `json:"ends_at"`
+	TimeRemainingSeconds int64                            `json:"time_remaining_seconds"`
+	WatchedAt            time.Time                        `json:"watched_at"`
+}
+
+type WatchlistPageOutputDTO struct {
+	Items []WatchedAuctionOutputDTO         `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+type WatchlistUseCaseInterface interface {
+	WatchAuction(
+		ctx context.Context, userId, auctionId string) (*WatchedAuctionOutputDTO, *internal_error.InternalError)
+
+	UnwatchAuction(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	FindWatchlist(
+		ctx context.Context,
+		userId string,
+		findWatchlistInput FindWatchlistInputDTO) (*WatchlistPageOutputDTO, *internal_error.InternalError)
+}
+
+type WatchlistUseCase struct {
+	watchlistRepository watchlist_entity.WatchlistRepositoryInterface
+	auctionRepository   auction_entity.AuctionRepositoryInterface
+	userRepository      user_entity.UserRepositoryInterface
+	auctionInterval     time.Duration
+}
+
+func NewWatchlistUseCase(
+	watchlistRepository watchlist_entity.WatchlistRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) WatchlistUseCaseInterface {
+	return &WatchlistUseCase{
+		watchlistRepository: watchlistRepository,
+		auctionRepository:   auctionRepository,
+		userRepository:      userRepository,
+		auctionInterval:     getAuctionInterval(),
+	}
+}
+
+func (wu *WatchlistUseCase) WatchAuction(
+	ctx context.Context, userId, auctionId string) (*WatchedAuctionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WatchlistUseCase.WatchAuction")
+	defer span.End()
+
+	watched, err := watchlist_entity.CreateWatchedAuction(userId, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := wu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	auction, err := wu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wu.watchlistRepository.WatchAuction(ctx, watched); err != nil {
+		return nil, err
+	}
+
+	output := wu.toWatchedAuctionOutputDTO(*watched, *auction, time.Now())
+	return &output, nil
+}
+
+func (wu *WatchlistUseCase) UnwatchAuction(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WatchlistUseCase.UnwatchAuction")
+	defer span.End()
+
+	return wu.watchlistRepository.UnwatchAuction(ctx, userId, auctionId)
+}
+
+// FindWatchlist returns the watched auctions with their leading bid and the time
+// left until the automatic closure.
+func (wu *WatchlistUseCase) FindWatchlist(
+	ctx context.Context,
+	userId string,
+	findWatchlistInput FindWatchlistInputDTO) (*WatchlistPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WatchlistUseCase.FindWatchlist")
+	defer span.End()
+
+	if _, err := wu.userRepository.FindUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	watchlist, pageInfo, err := wu.watchlistRepository.FindWatchlist(
+		ctx, userId, findWatchlistInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	auctionIds := make([]string, 0, len(watchlist))
+	for _, watched := range watchlist {
+		auctionIds = append(auctionIds, watched.AuctionId)
+	}
+
+	auctions, err := wu.auctionRepository.FindAuctionsByIds(ctx, auctionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionsById := make(map[string]auction_entity.Auction, len(auctions))
+	for _, auction := range auctions {
+		auctionsById[auction.Id] = auction
+	}
+
+	now := time.Now()
+	items := make([]WatchedAuctionOutputDTO, 0, len(watchlist))
+	for _, watched := range watchlist {
+		auction, ok := auctionsById[watched.AuctionId]
+		if !ok {
+			continue
+		}
+		items = append(items, wu.toWatchedAuctionOutputDTO(watched, auction, now))
+	}
+
+	return &WatchlistPageOutputDTO{
+		Items: items,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (wu *WatchlistUseCase) toWatchedAuctionOutputDTO(
+	watched watchlist_entity.WatchedAuction,
+	auction auction_entity.Auction,
+	now time.Time) WatchedAuctionOutputDTO {
+	output := WatchedAuctionOutputDTO{
+		Auction:              auction_usecase.NewAuctionOutputDTO(auction),
+		EndsAt:               auction.EndsAt(wu.auctionInterval),
+		TimeRemainingSeconds: int64(auction.Remaining(wu.auctionInterval, now).Seconds()),
+		WatchedAt:            watched.WatchedAt,
+	}
+
+	if auction.LeadingBidId != "" {
+		output.HighestBid = &HighestBidOutputDTO{
+			BidId:    auction.LeadingBidId,
+			UserId:   auction.LeaderId,
+			Amount:   auction.CurrentPrice.Decimal(),
+			Currency: string(auction.Currency()),
+		}
+	}
+
+	return output
+}
+
+func getAuctionInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_INTERVAL"))
+	if err != nil {
+		return time.Minute * 2
+	}
+
+	return duration
+}
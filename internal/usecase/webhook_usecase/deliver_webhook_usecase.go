@@ -0,0 +1,167 @@
+package webhook_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
+	"go.uber.org/zap"
+)
+
+// EventPayloadDTO is the body of every delivery.
+type EventPayloadDTO struct {
+	Id         string      `json:"id"`
+	Type       string      `json:"type"`
+	AuctionId  string      `json:"auction_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type BidAcceptedDataDTO struct {
+	BidId     string               `json:"bid_id"`
+	UserId    string               `json:"user_id"`
+	Amount    money_entity.Decimal `json:"amount"`
+	Currency  string               `json:"currency"`
+	Leading   bool                 `json:"leading"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+// PublishEvent queues a delivery of the event to each webhook subscribed to
+// it; the dispatch routine sends them. The deliveries carry the id of the
+// outbox event, so receivers can discard an event relayed more than once.
+func (wu *WebhookUseCase) PublishEvent(
+	ctx context.Context, event outbox_entity.Event) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.PublishEvent")
+	defer span.End()
+
+	var data interface{}
+
+	switch event.Type {
+	case outbox_entity.AuctionCreated:
+		var auctionData outbox_entity.AuctionData
+		if err := event.Decode(&auctionData); err != nil {
+			return err
+		}
+		auction, err := auctionData.Auction()
+		if err != nil {
+			return err
+		}
+		data = auction_usecase.NewAuctionOutputDTO(auction)
+	case outbox_entity.BidAccepted:
+		var bidData outbox_entity.BidData
+		if err := event.Decode(&bidData); err != nil {
+			return err
+		}
+		data = BidAcceptedDataDTO{
+			BidId:     bidData.BidId,
+			UserId:    bidData.UserId,
+			Amount:    bidData.Amount,
+			Currency:  bidData.Currency,
+			Leading:   bidData.Leading,
+			Timestamp: bidData.Timestamp,
+		}
+	case outbox_entity.AuctionClosed, outbox_entity.AuctionCancelled:
+		auction, err := wu.auctionRepository.FindAuctionById(ctx, event.AuctionId)
+		if err != nil {
+			return err
+		}
+		data = auction_usecase.NewAuctionOutputDTO(*auction)
+	default:
+		return nil
+	}
+
+	webhookEvent := webhook_entity.EventType(event.Type)
+	webhooks, err := wu.webhookRepository.FindWebhooksByEvent(ctx, webhookEvent)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, marshalErr := json.Marshal(EventPayloadDTO{
+		Id:         event.Id,
+		Type:       string(event.Type),
+		AuctionId:  event.AuctionId,
+		OccurredAt: event.OccurredAt,
+		Data:       data,
+	})
+	if marshalErr != nil {
+		logger.Error("Error trying to encode webhook payload", marshalErr)
+		return internal_error.NewInternalServerError("Error trying to encode webhook payload")
+	}
+
+	deliveries := make([]webhook_entity.Delivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, webhook_entity.NewDelivery(webhook.Id, event.Id, webhookEvent, payload))
+	}
+
+	return wu.webhookRepository.CreateDeliveries(ctx, deliveries)
+}
+
+func (wu *WebhookUseCase) DispatchDueDeliveries(ctx context.Context) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.DispatchDueDeliveries")
+	defer span.End()
+
+	webhooks := make(map[string]*webhook_entity.Webhook)
+
+	for range dispatchBatchSize {
+		delivery, err := wu.webhookRepository.ClaimDueDelivery(ctx, time.Now(), deliveryLease)
+		if err != nil {
+			return err
+		}
+		if delivery == nil {
+			return nil
+		}
+
+		webhook, ok := webhooks[delivery.WebhookId]
+		if !ok {
+			webhook, err = wu.webhookRepository.FindWebhookById(ctx, delivery.WebhookId)
+			if err != nil && err.Err != "not_found" {
+				return err
+			}
+			webhooks[delivery.WebhookId] = webhook
+		}
+
+		wu.attempt(ctx, webhook, delivery)
+	}
+
+	return nil
+}
+
+// attempt sends the delivery and records the outcome. Deliveries of deleted
+// webhooks fail without being sent.
+func (wu *WebhookUseCase) attempt(
+	ctx context.Context, webhook *webhook_entity.Webhook, delivery *webhook_entity.Delivery) {
+	now := time.Now()
+
+	if webhook == nil {
+		delivery.Record(0, "Webhook was deleted", webhook_entity.RetryPolicy{MaxAttempts: 1}, now)
+	} else {
+		statusCode, err := wu.sender.Send(ctx, *webhook, *delivery)
+
+		attemptErr := ""
+		if err != nil {
+			attemptErr = err.Message
+		} else if statusCode < 200 || statusCode >= 300 {
+			attemptErr = fmt.Sprintf("Receiver answered with status %d", statusCode)
+		}
+
+		delivery.Record(statusCode, attemptErr, wu.retryPolicy, time.Now())
+	}
+
+	if delivery.Status == webhook_entity.DeliveryFailed {
+		logger.Info("Webhook delivery failed",
+			zap.String("delivery_id", delivery.Id), zap.String("webhook_id", delivery.WebhookId),
+			zap.Int("attempts", delivery.Attempts), zap.String("last_error", delivery.LastError))
+	}
+
+	if err := wu.webhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Error("Error trying to record webhook delivery attempt", err)
+	}
+}
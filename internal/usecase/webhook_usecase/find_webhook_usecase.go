@@ -0,0 +1,135 @@
+package webhook_usecase
+
+import (
+	"context"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/tracing"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+// CreateWebhook returns the secret used to sign the deliveries; it is not shown
+// again.
+func (wu *WebhookUseCase) CreateWebhook(
+	ctx context.Context, webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.CreateWebhook")
+	defer span.End()
+
+	events := make([]webhook_entity.EventType, 0, len(webhookInput.Events))
+	for _, event := range webhookInput.Events {
+		events = append(events, webhook_entity.EventType(event))
+	}
+
+	webhook, err := webhook_entity.CreateWebhook(webhookInput.Url, webhookInput.Description, events)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wu.webhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	output := toWebhookOutputDTO(*webhook)
+	output.Secret = webhook.Secret
+
+	return &output, nil
+}
+
+func (wu *WebhookUseCase) FindWebhookById(
+	ctx context.Context, id string) (*WebhookOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.FindWebhookById")
+	defer span.End()
+
+	webhook, err := wu.webhookRepository.FindWebhookById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := toWebhookOutputDTO(*webhook)
+	return &output, nil
+}
+
+func (wu *WebhookUseCase) FindWebhooks(
+	ctx context.Context,
+	findWebhooksInput FindWebhooksInputDTO) (*WebhookPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.FindWebhooks")
+	defer span.End()
+
+	webhooks, pageInfo, err := wu.webhookRepository.FindWebhooks(ctx, findWebhooksInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]WebhookOutputDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		items = append(items, toWebhookOutputDTO(webhook))
+	}
+
+	return &WebhookPageOutputDTO{
+		Items: items,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (wu *WebhookUseCase) DeleteWebhook(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.DeleteWebhook")
+	defer span.End()
+
+	return wu.webhookRepository.DeleteWebhook(ctx, id)
+}
+
+func (wu *WebhookUseCase) FindDeliveries(
+	ctx context.Context,
+	webhookId string,
+	findDeliveriesInput FindDeliveriesInputDTO) (*DeliveryPageOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.FindDeliveries")
+	defer span.End()
+
+	if _, err := wu.webhookRepository.FindWebhookById(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, pageInfo, err := wu.webhookRepository.FindDeliveries(
+		ctx,
+		webhookId,
+		webhook_entity.DeliveryStatus(findDeliveriesInput.Status),
+		findDeliveriesInput.ToPageRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]DeliveryOutputDTO, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		items = append(items, toDeliveryOutputDTO(delivery))
+	}
+
+	return &DeliveryPageOutputDTO{
+		Items: items,
+		Page:  pagination_usecase.NewPageOutputDTO(pageInfo),
+	}, nil
+}
+
+func (wu *WebhookUseCase) Redeliver(
+	ctx context.Context, webhookId, deliveryId string) (*DeliveryOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Redeliver")
+	defer span.End()
+
+	if _, err := wu.webhookRepository.FindWebhookById(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	delivery, err := wu.webhookRepository.FindDeliveryById(ctx, webhookId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery := delivery.Redeliver()
+	if err := wu.webhookRepository.CreateDeliveries(ctx, []webhook_entity.Delivery{redelivery}); err != nil {
+		return nil, err
+	}
+
+	output := toDeliveryOutputDTO(redelivery)
+	return &output, nil
+}
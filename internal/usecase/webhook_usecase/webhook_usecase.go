@@ -0,0 +1,222 @@
+package webhook_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Berchon/fullcycle-auction_go/configuration/logger"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/outbox_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/webhook_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/internal_error"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/pagination_usecase"
+)
+
+type WebhookInputDTO struct {
+	Url         string   `json:"url" binding:"required,url,max=2048"`
+	Description string   `json:"description" binding:"max=200"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=auction.created bid.accepted auction.closed auction.cancelled"`
+}
+
+// WebhookOutputDTO only carries the secret when the webhook is created.
+type WebhookOutputDTO struct {
+	Id          string    `json:"id"`
+	Url         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Events      []string  `json:"events"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FindWebhooksInputDTO struct {
+	pagination_usecase.PageInputDTO
+}
+
+type WebhookPageOutputDTO struct {
+	Items []WebhookOutputDTO                `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+type DeliveryOutputDTO struct {
+	Id             string          `json:"id"`
+	WebhookId      string          `json:"webhook_id"`
+	EventId        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	RedeliveryOf   string          `json:"redelivery_of,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type FindDeliveriesInputDTO struct {
+	pagination_usecase.PageInputDTO
+
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+}
+
+type DeliveryPageOutputDTO struct {
+	Items []DeliveryOutputDTO               `json:"items"`
+	Page  pagination_usecase.PageOutputDTO  `json:"page"`
+	Links pagination_usecase.LinksOutputDTO `json:"links"`
+}
+
+type WebhookUseCaseInterface interface {
+	CreateWebhook(
+		ctx context.Context, webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError)
+
+	FindWebhookById(
+		ctx context.Context, id string) (*WebhookOutputDTO, *internal_error.InternalError)
+
+	FindWebhooks(
+		ctx context.Context,
+		findWebhooksInput FindWebhooksInputDTO) (*WebhookPageOutputDTO, *internal_error.InternalError)
+
+	DeleteWebhook(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindDeliveries(
+		ctx context.Context,
+		webhookId string,
+		findDeliveriesInput FindDeliveriesInputDTO) (*DeliveryPageOutputDTO, *internal_error.InternalError)
+
+	// Redeliver sends the event of a delivery again, as a new delivery.
+	Redeliver(
+		ctx context.Context, webhookId, deliveryId string) (*DeliveryOutputDTO, *internal_error.InternalError)
+
+	// PublishEvent publishes the outbox events that have a webhook event; the
+	// others are ignored.
+	PublishEvent(
+		ctx context.Context, event outbox_entity.Event) *internal_error.InternalError
+
+	// DispatchDueDeliveries attempts the pending deliveries whose time has come.
+	DispatchDueDeliveries(
+		ctx context.Context) *internal_error.InternalError
+}
+
+const (
+	// dispatchBatchSize caps the deliveries attempted on each dispatch.
+	dispatchBatchSize = 100
+	// deliveryLease keeps a claimed delivery from being sent by another
+	// dispatcher while it is attempted.
+	deliveryLease = time.Minute
+)
+
+type WebhookUseCase struct {
+	webhookRepository webhook_entity.WebhookRepositoryInterface
+	auctionRepository auction_entity.AuctionRepositoryInterface
+	sender            webhook_entity.SenderInterface
+
+	retryPolicy      webhook_entity.RetryPolicy
+	dispatchInterval time.Duration
+}
+
+func NewWebhookUseCase(
+	webhookRepository webhook_entity.WebhookRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	sender webhook_entity.SenderInterface) WebhookUseCaseInterface {
+	webhookUseCase := &WebhookUseCase{
+		webhookRepository: webhookRepository,
+		auctionRepository: auctionRepository,
+		sender:            sender,
+		retryPolicy:       getRetryPolicy(),
+		dispatchInterval:  getDispatchInterval(),
+	}
+
+	webhookUseCase.triggerDispatchRoutine(context.Background())
+
+	return webhookUseCase
+}
+
+// triggerDispatchRoutine periodically sends the new deliveries and retries the
+// failed ones.
+func (wu *WebhookUseCase) triggerDispatchRoutine(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(wu.dispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := wu.DispatchDueDeliveries(ctx); err != nil {
+					logger.Error("error trying to dispatch webhook deliveries", err)
+				}
+			}
+		}
+	}()
+}
+
+func toWebhookOutputDTO(webhook webhook_entity.Webhook) WebhookOutputDTO {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+
+	return WebhookOutputDTO{
+		Id:          webhook.Id,
+		Url:         webhook.Url,
+		Description: webhook.Description,
+		Events:      events,
+		CreatedAt:   webhook.CreatedAt,
+	}
+}
+
+func toDeliveryOutputDTO(delivery webhook_entity.Delivery) DeliveryOutputDTO {
+	output := DeliveryOutputDTO{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		Event:          string(delivery.Event),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+
+	if delivery.Status == webhook_entity.DeliveryPending {
+		output.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	return output
+}
+
+func getRetryPolicy() webhook_entity.RetryPolicy {
+	policy := webhook_entity.RetryPolicy{MaxAttempts: 8, Base: 30 * time.Second, Max: time.Hour}
+
+	if maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err == nil && maxAttempts > 0 {
+		policy.MaxAttempts = maxAttempts
+	}
+
+	if base, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_BASE")); err == nil && base > 0 {
+		policy.Base = base
+	}
+
+	if maxBackoff, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_MAX")); err == nil && maxBackoff > 0 {
+		policy.Max = maxBackoff
+	}
+
+	return policy
+}
+
+func getDispatchInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("WEBHOOK_DISPATCH_INTERVAL"))
+	if err != nil || duration <= 0 {
+		return 5 * time.Second
+	}
+
+	return duration
+}
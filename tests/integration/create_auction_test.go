@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/Berchon/fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/money_entity"
+	"github.com/Berchon/fullcycle-auction_go/internal/usecase/auction_usecase"
 	http_test "github.com/Berchon/fullcycle-auction_go/tests/integration/http"
 	"github.com/Berchon/fullcycle-auction_go/tests/integration/http/fixtures"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +22,46 @@ func TestCreateAuction(t *testing.T) {
 	// db := http_test.NewDB(t)
 	// server := http_test.SetupServer(t, db.Database)
 
+	// Authentication
+	// -------------------------------------------------
+	t.Run("should return 401 when the bearer token is missing", func(t *testing.T) {
+		db := http_test.NewDB(t)
+		defer db.DropAllCollections(t)
+		server := http_test.SetupServer(t, db.Database)
+
+		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		resp := server.DoRequest(req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.NotEmpty(t, resp.Header().Get("WWW-Authenticate"))
+		assert.JSONEq(t, http_test.MissingTokenError, strings.TrimSpace(resp.Body.String()))
+	})
+
+	t.Run("should return 401 when the bearer token is invalid", func(t *testing.T) {
+		db := http_test.NewDB(t)
+		defer db.DropAllCollections(t)
+		server := http_test.SetupServer(t, db.Database)
+
+		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req.Header.Set("Authorization", "Bearer not-a-jwt")
+		resp := server.DoRequest(req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.JSONEq(t, http_test.InvalidTokenError, strings.TrimSpace(resp.Body.String()))
+	})
+
+	t.Run("should return 403 when the user is not a seller", func(t *testing.T) {
+		db := http_test.NewDB(t)
+		defer db.DropAllCollections(t)
+		server := http_test.SetupServer(t, db.Database)
+
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.BidderRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
+		resp := server.DoRequest(req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.JSONEq(t, http_test.SellerRoleRequiredError, strings.TrimSpace(resp.Body.String()))
+	})
+
 	// Validations in controller layer
 	// -------------------------------------------------
 	t.Run("should return 404 when body is missing", func(t *testing.T) {
@@ -27,7 +69,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", "")
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", "")
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
@@ -39,7 +81,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", `{"product_name": "Mola maluca", "category": "Brinquedo"`) // missing closing brace
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", `{"product_name": "Mola maluca", "category": "Brinquedo"`) // missing closing brace
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
@@ -51,7 +93,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.MissingField)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.MissingField)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -63,7 +105,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.InvalidType)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.InvalidType)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
@@ -75,7 +117,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.InvalidCondition)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.InvalidCondition)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -87,7 +129,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.InvalidProductName)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.InvalidProductName)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -99,7 +141,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.InvalidDescriptionAndCondition)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.InvalidDescriptionAndCondition)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -111,7 +153,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidShortDescription)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidShortDescription)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -123,7 +165,7 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.MultipleInvalidFields)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.MultipleInvalidFields)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
@@ -137,29 +179,42 @@ func TestCreateAuction(t *testing.T) {
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.InvalidCategory)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.InvalidCategory)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
 		assert.JSONEq(t, http_test.InvalidAuctionError, strings.TrimSpace(resp.Body.String()))
 	})
 
+	t.Run("should return 400 when category does not exist", func(t *testing.T) {
+		db := http_test.NewDB(t)
+		defer db.DropAllCollections(t)
+		server := http_test.SetupServer(t, db.Database)
+
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
+		resp := server.DoRequest(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.JSONEq(t, http_test.CategoryNotFoundError, strings.TrimSpace(resp.Body.String()))
+	})
+
 	// Validations in repository
 	// -------------------------------------------------
-	t.Run("should return error when InsertOne fails and auction remains open in DB", func(t *testing.T) {
+	t.Run("should return error when the database is unavailable and auction is not created", func(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
-		// Simula falha no InsertOne
+		// Simula falha no banco: a busca da categoria falha antes do InsertOne
 		err := db.Client.Disconnect(context.Background())
 		assert.NoError(t, err, "failed to disconnect mongo client to simulate InsertOne failure")
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusInternalServerError, resp.Code, "should return 500 when insert fails")
-		assert.JSONEq(t, http_test.InsertOneError, strings.TrimSpace(resp.Body.String()))
+		assert.JSONEq(t, http_test.DatabaseUnavailableError, strings.TrimSpace(resp.Body.String()))
 
 		db.Reconnect(t)
 
@@ -173,6 +228,7 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalAppMode := os.Getenv("APP_MODE")
 		os.Setenv("APP_MODE", "test")
@@ -185,11 +241,14 @@ func TestCreateAuction(t *testing.T) {
 		testCtx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequestWithContext(testCtx, req)
 
 		assert.Equal(t, http.StatusCreated, resp.Code, "should return 201 when auction is created successfully")
-		assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+		var created auction_usecase.AuctionOutputDTO
+		http_test.DecodeJSONResponse(t, resp, &created)
+		assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+		assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 
 		// Immediately cancels the context to simulate an early termination.
 		// This mimics a scenario where the application shuts down or the HTTP request ends prematurely.
@@ -211,15 +270,19 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalInterval := os.Getenv("AUCTION_INTERVAL")
 		os.Setenv("AUCTION_INTERVAL", "30ms")
 		defer os.Setenv("AUCTION_INTERVAL", originalInterval)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequest(req)
 		assert.Equal(t, http.StatusCreated, resp.Code, "auction should be created successfully")
-		assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+		var created auction_usecase.AuctionOutputDTO
+		http_test.DecodeJSONResponse(t, resp, &created)
+		assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+		assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 
 		// Simulate a failure in the UpdateOne operation by disconnecting the MongoDB client
 		// before the automatic auction closure can occur. This ensures that when the closure
@@ -248,6 +311,7 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalAppMode := os.Getenv("APP_MODE")
 		os.Setenv("APP_MODE", "test")
@@ -257,11 +321,14 @@ func TestCreateAuction(t *testing.T) {
 		os.Setenv("AUCTION_INTERVAL", "50ms")
 		defer os.Setenv("AUCTION_INTERVAL", originalInterval)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusCreated, resp.Code, "should return 201 when auction is created successfully")
-		assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+		var created auction_usecase.AuctionOutputDTO
+		http_test.DecodeJSONResponse(t, resp, &created)
+		assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+		assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 
 		coll := db.Database.Collection("auctions")
 		var result auction_entity.Auction
@@ -274,6 +341,7 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalAppMode := os.Getenv("APP_MODE")
 		os.Setenv("APP_MODE", "test")
@@ -294,10 +362,13 @@ func TestCreateAuction(t *testing.T) {
 			wg.Add(1)
 			go func(auction map[string]interface{}) {
 				defer wg.Done()
-				req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", auction)
+				req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", auction)
 				resp := server.DoRequest(req)
 				assert.Equal(t, http.StatusCreated, resp.Code, "should return 201 when auction is created successfully")
-				assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+				var created auction_usecase.AuctionOutputDTO
+				http_test.DecodeJSONResponse(t, resp, &created)
+				assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+				assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 			}(a)
 		}
 
@@ -326,6 +397,7 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalAppMode := os.Getenv("APP_MODE")
 		os.Setenv("APP_MODE", "test")
@@ -335,11 +407,14 @@ func TestCreateAuction(t *testing.T) {
 		os.Setenv("AUCTION_INTERVAL", "30ms")
 		defer os.Setenv("AUCTION_INTERVAL", originalInterval)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusCreated, resp.Code, "should return 201 when auction is created successfully")
-		assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+		var created auction_usecase.AuctionOutputDTO
+		http_test.DecodeJSONResponse(t, resp, &created)
+		assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+		assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 
 		time.Sleep(50 * time.Millisecond)
 
@@ -354,22 +429,31 @@ func TestCreateAuction(t *testing.T) {
 		db := http_test.NewDB(t)
 		defer db.DropAllCollections(t)
 		server := http_test.SetupServer(t, db.Database)
+		db.SeedCategories(t, fixtures.Categories...)
 
 		originalAppMode := os.Getenv("APP_MODE")
 		os.Setenv("APP_MODE", "test")
 		defer os.Setenv("APP_MODE", originalAppMode)
 
-		req := http_test.NewJSONRequest(t, http.MethodPost, "/auction", fixtures.ValidAuction)
+		req := server.NewAuthenticatedJSONRequest(t, fixtures.SellerId, fixtures.SellerRoles, http.MethodPost, "/auction", fixtures.ValidAuction)
 		resp := server.DoRequest(req)
 
 		assert.Equal(t, http.StatusCreated, resp.Code, "should return 201 when auction is created successfully")
-		assert.Empty(t, strings.TrimSpace(resp.Body.String()), "response body should be empty on successful creation")
+		var created auction_usecase.AuctionOutputDTO
+		http_test.DecodeJSONResponse(t, resp, &created)
+		assert.NotEmpty(t, created.Id, "response body should contain the created auction id")
+		assert.Equal(t, "/auction/"+created.Id, resp.Header().Get("Location"), "Location header should point to the created auction")
 
 		coll := db.Database.Collection("auctions")
 		var result auction_entity.Auction
-		err := coll.FindOne(context.Background(), bson.M{"product_name": fixtures.ValidAuction["product_name"]}).Decode(&result)
+		err := coll.FindOne(context.Background(), bson.M{"_id": created.Id}).Decode(&result)
 		assert.NoError(t, err, "auction should exist in DB after creation")
 		assert.Equal(t, auction_entity.Active, result.Status, "auction status should be active after creation")
+		assert.Equal(t, fixtures.ValidAuction["product_name"], created.ProductName, "response should echo the created auction")
+		assert.Equal(t, "brinquedo", created.Category, "auction should reference the canonical category slug")
+		assert.Equal(t, fixtures.SellerId, created.SellerId, "seller should come from the token claims")
+		assert.Equal(t, "BRL", created.Currency, "auction should default to the default currency")
+		assert.Equal(t, money_entity.Decimal("0.00"), created.CurrentPrice, "price should be written with the currency decimal places")
 	})
 
 }
@@ -1,6 +1,17 @@
 package fixtures
 
+// SellerId is the authenticated user that creates the auctions in the tests.
+const SellerId = "e73fce6a-ccf5-4c12-87f7-30c5f9c9a6f7"
+
 var (
+	SellerRoles = []string{"seller"}
+	BidderRoles = []string{"bidder"}
+)
+
+var (
+	// Categories referenced by the valid payloads, which must exist before creating auctions.
+	Categories = []string{"Brinquedo", "Esporte", "Instrumentos Musicais"}
+
 	ValidAuction = map[string]interface{}{
 		"product_name": "Mola maluca",
 		"category":     "Brinquedo",
@@ -43,10 +43,38 @@ var (
 		"causes": [{"field":"Condition","message":"Condition must be one of [1 2 3]"}]
 	}`
 
-	InsertOneError = `{
+	DatabaseUnavailableError = `{
 		"code": 500,
 		"err": "internal_server",
-		"message": "Error trying to insert auction",
+		"message": "Error trying to find category by slug",
+		"causes": null
+	}`
+
+	CategoryNotFoundError = `{
+		"code": 400,
+		"err": "bad_request",
+		"message": "Category Brinquedo does not exist",
+		"causes": null
+	}`
+
+	MissingTokenError = `{
+		"code": 401,
+		"err": "unauthorized",
+		"message": "Missing bearer token",
+		"causes": null
+	}`
+
+	InvalidTokenError = `{
+		"code": 401,
+		"err": "unauthorized",
+		"message": "Invalid or expired token",
+		"causes": null
+	}`
+
+	SellerRoleRequiredError = `{
+		"code": 403,
+		"err": "forbidden",
+		"message": "This action requires one of the roles: seller",
 		"causes": null
 	}`
 )
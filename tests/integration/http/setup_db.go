@@ -8,8 +8,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Berchon/fullcycle-auction_go/internal/entity/category_entity"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -122,8 +124,30 @@ func (db *DB) DropCollection(t *testing.T, collectionName string) {
 func (db *DB) DropAllCollections(t *testing.T) {
 	t.Helper()
 
-	collections := []string{"auctions", "bids", "users"}
+	collections := []string{"auctions", "bids", "users", "categories", "bids_dead_letter", "api_keys", "credit_accounts", "orders"}
 	for _, collection := range collections {
 		db.DropCollection(t, collection)
 	}
 }
+
+// SeedCategories inserts root categories with the given names so auctions can be
+// created referencing them.
+func (db *DB) SeedCategories(t *testing.T, names ...string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	t.Cleanup(cancel)
+
+	for _, name := range names {
+		category, err := category_entity.CreateCategory(name, "")
+		require.Nil(t, err, "failed to build category %s", name)
+
+		_, insertErr := db.Database.Collection("categories").InsertOne(ctx, bson.M{
+			"_id":       category.Id,
+			"name":      category.Name,
+			"slug":      category.Slug,
+			"timestamp": category.Timestamp.Unix(),
+		})
+		require.NoError(t, insertErr, "failed to seed category %s", name)
+	}
+}
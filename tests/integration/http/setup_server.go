@@ -4,30 +4,51 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/api/web/router"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/auth"
 	"github.com/Berchon/fullcycle-auction_go/internal/infra/dependencies"
+	"github.com/Berchon/fullcycle-auction_go/internal/infra/metrics"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// testJWTSecret is used when the .env does not define JWT_SECRET.
+const testJWTSecret = "integration-tests-secret-with-32-bytes"
+
 type testServer struct {
 	router *gin.Engine
 	db     *mongo.Database
+	tokens *auth.JWTTokenService
 }
 
 func SetupServer(t *testing.T, db *mongo.Database) *testServer {
 	t.Helper()
 
-	userController, bidController, auctionController := dependencies.InitDependencies(db)
+	if os.Getenv("JWT_SECRET") == "" {
+		t.Setenv("JWT_SECRET", testJWTSecret)
+	}
+
+	// The tests send requests in bursts; the limiter has its own unit tests.
+	t.Setenv("RATE_LIMIT_READ_RATE", "0")
+	t.Setenv("RATE_LIMIT_BID_RATE", "0")
+
+	deps, err := dependencies.InitDependencies(db, metrics.NewPrometheus())
+	require.NoError(t, err, "failed to init dependencies")
+
+	tokens, err := auth.NewJWTTokenServiceFromEnv()
+	require.NoError(t, err, "failed to init token service")
 
 	r := gin.Default()
-	router.RegisterRoutes(r, userController, bidController, auctionController)
+	router.RegisterRoutes(r, deps)
 
 	s := &testServer{
 		router: r,
 		db:     db,
+		tokens: tokens,
 	}
 
 	return s
@@ -46,3 +67,17 @@ func (s *testServer) DoRequestWithContext(ctx context.Context, req *http.Request
 	req = req.WithContext(ctx)
 	return s.DoRequest(req)
 }
+
+// NewAuthenticatedJSONRequest builds a JSON request carrying a bearer token for
+// userId with the given roles.
+func (s *testServer) NewAuthenticatedJSONRequest(
+	t *testing.T, userId string, roles []string, method, url string, body interface{}) *http.Request {
+	t.Helper()
+
+	token, _, err := s.tokens.IssueToken(userId, roles)
+	require.Nil(t, err, "failed to issue token")
+
+	req := NewJSONRequest(t, method, url, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
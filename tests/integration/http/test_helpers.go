@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,3 +26,12 @@ func NewJSONRequest(t *testing.T, method, url string, body interface{}) *http.Re
 	req.Header.Set("Content-Type", "application/json")
 	return req
 }
+
+// DecodeJSONResponse decodes the response body into target. It only reports the
+// failure (instead of stopping the test) so it can be used inside goroutines.
+func DecodeJSONResponse(t *testing.T, resp *httptest.ResponseRecorder, target interface{}) bool {
+	t.Helper()
+
+	err := json.NewDecoder(resp.Body).Decode(target)
+	return assert.NoError(t, err, "failed to decode JSON response body")
+}